@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,12 +11,13 @@ import (
 	"syscall"
 	"time"
 
+	"book-nexus/internal/config"
 	"book-nexus/internal/database"
 	migration "book-nexus/internal/database/migrations"
 	"book-nexus/internal/server"
 )
 
-func gracefulShutdown(apiServer *server.Server, done chan bool) {
+func gracefulShutdown(apiServer *server.Server, timeout time.Duration, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -27,7 +29,7 @@ func gracefulShutdown(apiServer *server.Server, done chan bool) {
 	stop()
 
 	// Create a timeout context for shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	if err := apiServer.Shutdown(shutdownCtx); err != nil {
@@ -41,10 +43,30 @@ func gracefulShutdown(apiServer *server.Server, done chan bool) {
 }
 
 func main() {
-	dbService := database.New()
+	printConfig := flag.Bool("print-config", false, "print the configuration with secrets redacted and exit")
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
+	if *printConfig {
+		if printErr := cfg.Print(os.Stdout); printErr != nil {
+			fmt.Fprintln(os.Stderr, printErr)
+			os.Exit(1)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *printConfig {
+		return
+	}
+
+	dbService, err := database.New(context.Background(), cfg)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
 	db := dbService.DB()
 
-	if err := migration.RunMigrations(db); err != nil {
+	if err := migration.RunMigrations(db, cfg.Database.Schema); err != nil {
 		slog.Error("Failed to run migrations", "error", err)
 		os.Exit(1)
 	}
@@ -55,17 +77,21 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
-	apiServer := server.NewServer()
+	apiServer, err := server.NewServer(cfg, dbService)
+	if err != nil {
+		slog.Error("Failed to start server", "error", err)
+		os.Exit(1)
+	}
 
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(apiServer, done)
+	go gracefulShutdown(apiServer, cfg.Server.ShutdownTimeout, done)
 
-	slog.Info("GraphQL server starting...", "address", fmt.Sprintf(":%d", 8080))
+	slog.Info("GraphQL server starting...", "address", apiServer.Addr(), "env", cfg.Env)
 
-	err := apiServer.Listen()
+	err = apiServer.Listen()
 	if err != nil && err != http.ErrServerClosed {
 		panic(fmt.Sprintf("http server error: %s", err))
 	}
@@ -0,0 +1,31 @@
+// Command hashpassword prints the bcrypt hash of a password read from
+// standard input, for ADMIN_PASSWORD_HASH.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func main() {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatalf("Failed to read password: %v", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		log.Fatal("Password must not be empty")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatalf("Failed to hash password: %v", err)
+	}
+	fmt.Println(string(hash))
+}
@@ -1,27 +1,47 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"book-nexus/internal/config"
 	"book-nexus/internal/database"
 	migration "book-nexus/internal/database/migrations"
+	"book-nexus/internal/seed"
 )
 
 func main() {
 	var csvPath string
+	var opts seed.Options
 	flag.StringVar(&csvPath, "csv", "data/books.csv", "Path to the CSV file to seed from")
-	flag.Parse()
+	flag.IntVar(&opts.BatchSize, "batch-size", seed.DefaultBatchSize, "Rows written per transaction")
+	flag.BoolVar(&opts.Restart, "restart", false, "Ignore the checkpoint of an interrupted import and start from the first row")
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// An interrupt stops the import after rolling back the current batch, so
+	// the next run resumes from the last committed one.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Initialize database
-	dbService := database.New()
+	dbService, err := database.New(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
 	db := dbService.DB()
 	defer dbService.Close()
 
 	// Ensure migrations are run first
 	log.Println("Running migrations...")
-	if err := migration.RunMigrations(db); err != nil {
+	if err := migration.RunMigrations(db, cfg.Database.Schema); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -32,9 +52,16 @@ func main() {
 
 	// Seed database
 	log.Printf("Seeding database from CSV file: %s", csvPath)
-	if err := migration.SeedBooks(db, csvPath); err != nil {
+	stats, err := seed.Import(ctx, db, csvPath, opts)
+	if err != nil && ctx.Err() != nil && stats != nil {
+		log.Printf("Seeding interrupted after %d rows; run again to resume", stats.RowsRead)
+		return
+	}
+	if err != nil {
 		log.Fatalf("Failed to seed database: %v", err)
 	}
 
-	log.Println("Seeding completed successfully!")
+	log.Printf("Seeding completed in %s: %d rows read, %d books inserted, %d skipped (%.0f rows/s)",
+		stats.Elapsed.Round(time.Millisecond), stats.RowsRead, stats.BooksInserted, stats.RowsSkipped, stats.RowsPerSecond())
+	log.Printf("Created %d authors, %d publishers, %d series", stats.Authors, stats.Publishers, stats.Series)
 }
@@ -0,0 +1,39 @@
+// Codegen plugin that writes the persisted query manifest read by the API's
+// strict mode (PERSISTED_QUERIES_FILE): a JSON object mapping the SHA-256 of
+// each operation, printed together with the fragments it uses, to its text.
+const { createHash } = require("node:crypto");
+const { Kind, print, visit } = require("graphql");
+
+module.exports = {
+  plugin(_schema, documents) {
+    const definitions = documents.flatMap((file) =>
+      file.document ? file.document.definitions : [],
+    );
+    const fragments = new Map(
+      definitions
+        .filter((def) => def.kind === Kind.FRAGMENT_DEFINITION)
+        .map((def) => [def.name.value, def]),
+    );
+
+    const manifest = {};
+    for (const operation of definitions) {
+      if (operation.kind !== Kind.OPERATION_DEFINITION) continue;
+
+      const used = new Map();
+      const collect = (node) =>
+        visit(node, {
+          FragmentSpread(spread) {
+            const name = spread.name.value;
+            if (used.has(name) || !fragments.has(name)) return;
+            used.set(name, fragments.get(name));
+            collect(fragments.get(name));
+          },
+        });
+      collect(operation);
+
+      const text = [operation, ...used.values()].map((def) => print(def)).join("\n\n");
+      manifest[createHash("sha256").update(text).digest("hex")] = text;
+    }
+    return JSON.stringify(manifest, null, 2) + "\n";
+  },
+};
@@ -1,13 +1,13 @@
 import { Button } from "@/components/ui/button";
-import { clearAdminPassword } from "@/lib/graphql/admin";
+import { logout } from "@/lib/graphql/admin";
 
 type AdminHeaderProps = {
   onLogout: () => void;
 };
 
 export function AdminHeader({ onLogout }: AdminHeaderProps) {
-  const handleLogout = () => {
-    clearAdminPassword();
+  const handleLogout = async () => {
+    await logout();
     onLogout();
   };
 
@@ -3,20 +3,33 @@ import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
 import { Label } from "@/components/ui/label";
 import { Card } from "@/components/ui/card";
-import { setAdminPassword } from "@/lib/graphql/admin";
+import { login } from "@/lib/graphql/admin";
+import { getErrorMessage } from "@/lib/graphql/client";
 
 type AdminLoginProps = {
   onLogin: () => void;
 };
 
 export function AdminLogin({ onLogin }: AdminLoginProps) {
+  const [email, setEmail] = useState("");
   const [password, setPassword] = useState("");
+  const [error, setError] = useState<string | null>(null);
+  const [isSubmitting, setIsSubmitting] = useState(false);
 
-  const handleLogin = (e: React.FormEvent) => {
+  const handleLogin = async (e: React.FormEvent) => {
     e.preventDefault();
-    if (password.trim()) {
-      setAdminPassword(password);
+    if (!email.trim() || !password) {
+      return;
+    }
+    setIsSubmitting(true);
+    setError(null);
+    try {
+      await login(email.trim(), password);
       onLogin();
+    } catch (err) {
+      setError(getErrorMessage(err));
+    } finally {
+      setIsSubmitting(false);
     }
   };
 
@@ -28,17 +41,30 @@ export function AdminLogin({ onLogin }: AdminLoginProps) {
         </h1>
         <form onSubmit={handleLogin} className="space-y-3 sm:space-y-4">
           <div className="space-y-2">
-            <Label htmlFor="password">Admin Password</Label>
+            <Label htmlFor="email">Email</Label>
+            <Input
+              id="email"
+              type="email"
+              autoComplete="username"
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              placeholder="Enter your email"
+            />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="password">Password</Label>
             <Input
               id="password"
               type="password"
+              autoComplete="current-password"
               value={password}
               onChange={(e) => setPassword(e.target.value)}
-              placeholder="Enter admin password"
+              placeholder="Enter your password"
             />
           </div>
-          <Button type="submit" className="w-full">
-            Login
+          {error && <p className="text-sm text-destructive">{error}</p>}
+          <Button type="submit" className="w-full" disabled={isSubmitting}>
+            {isSubmitting ? "Signing in..." : "Login"}
           </Button>
         </form>
       </Card>
@@ -58,16 +58,13 @@ export function BookCard({ book, onClick }: BookCardProps) {
           </div>
         )}
 
-        {book.tags && (
+        {book.tags.length > 0 && (
           <div className="flex flex-wrap gap-1 pt-2">
-            {book.tags
-              .split(",")
-              .slice(0, 3)
-              .map((tag) => (
-                <Badge key={tag} variant="secondary">
-                  {tag.trim()}
-                </Badge>
-              ))}
+            {book.tags.slice(0, 3).map((tag) => (
+              <Badge key={tag.id} variant="secondary">
+                {tag.name}
+              </Badge>
+            ))}
           </div>
         )}
       </CardContent>
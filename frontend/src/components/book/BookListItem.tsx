@@ -46,19 +46,16 @@ export function BookListItem({ book, onClick }: BookListItemProps) {
           </div>
         )}
 
-        {book.tags && (
+        {book.tags.length > 0 && (
           <div className="flex flex-wrap gap-1 mt-2">
-            {book.tags
-              .split(",")
-              .slice(0, 3)
-              .map((tag) => (
-                <span
-                  key={tag}
-                  className="bg-secondary text-secondary-foreground px-2 py-0.5 rounded"
-                >
-                  {tag.trim()}
-                </span>
-              ))}
+            {book.tags.slice(0, 3).map((tag) => (
+              <span
+                key={tag.id}
+                className="bg-secondary text-secondary-foreground px-2 py-0.5 rounded"
+              >
+                {tag.name}
+              </span>
+            ))}
           </div>
         )}
       </div>
@@ -12,7 +12,7 @@ import type {
   UpdateSeries,
   SearchResult,
 } from "./types";
-import { parseGraphQLError } from "./client";
+import { ApiError, graphqlClient, parseGraphQLError } from "./client";
 
 // Get API endpoint from environment variable or use same origin
 const getApiUrl = (): string => {
@@ -25,26 +25,152 @@ const getApiUrl = (): string => {
 
 const endpoint = getApiUrl();
 
-// Admin password stored in session storage
-const ADMIN_PASSWORD_KEY = "adminPassword";
+// Admin sessions. The access token is only kept in memory; the refresh token
+// is kept in session storage so that reloading the page can start a new
+// session without signing in again. Each refresh token works once.
+const REFRESH_TOKEN_KEY = "adminRefreshToken";
 
-export function getAdminPassword(): string {
-  return sessionStorage.getItem(ADMIN_PASSWORD_KEY) || "";
+// Refresh this long before the access token expires, to allow for clock skew
+// and the request itself.
+const REFRESH_MARGIN_MS = 30_000;
+
+type Session = {
+  accessToken: string;
+  accessTokenExpiresAt: string;
+  refreshToken: string;
+};
+
+const SESSION_FIELDS = /* GraphQL */ `
+  accessToken
+  accessTokenExpiresAt
+  refreshToken
+`;
+
+const LOGIN_MUTATION = /* GraphQL */ `
+  mutation Login($email: String!, $password: String!) {
+    login(email: $email, password: $password) {
+      ${SESSION_FIELDS}
+    }
+  }
+`;
+
+const REFRESH_SESSION_MUTATION = /* GraphQL */ `
+  mutation RefreshSession($refreshToken: String!) {
+    refreshSession(refreshToken: $refreshToken) {
+      ${SESSION_FIELDS}
+    }
+  }
+`;
+
+const LOGOUT_MUTATION = /* GraphQL */ `
+  mutation Logout($refreshToken: String!) {
+    logout(refreshToken: $refreshToken)
+  }
+`;
+
+let accessToken: string | null = null;
+let accessTokenExpiresAt = 0;
+let refreshing: Promise<string> | null = null;
+const sessionEndListeners = new Set<() => void>();
+
+function saveSession(session: Session): string {
+  accessToken = session.accessToken;
+  accessTokenExpiresAt = Date.parse(session.accessTokenExpiresAt);
+  sessionStorage.setItem(REFRESH_TOKEN_KEY, session.refreshToken);
+  return session.accessToken;
 }
 
-export function setAdminPassword(password: string): void {
-  sessionStorage.setItem(ADMIN_PASSWORD_KEY, password);
+function endSession(): void {
+  accessToken = null;
+  accessTokenExpiresAt = 0;
+  sessionStorage.removeItem(REFRESH_TOKEN_KEY);
+  sessionEndListeners.forEach((listener) => listener());
 }
 
-export function clearAdminPassword(): void {
-  sessionStorage.removeItem(ADMIN_PASSWORD_KEY);
+export function hasAdminSession(): boolean {
+  return !!sessionStorage.getItem(REFRESH_TOKEN_KEY);
 }
 
-function createAdminClient(): GraphQLClient {
+// onSessionEnd calls listener when the session ends, by logout or because it
+// could not be refreshed, and returns a function that stops listening.
+export function onSessionEnd(listener: () => void): () => void {
+  sessionEndListeners.add(listener);
+  return () => {
+    sessionEndListeners.delete(listener);
+  };
+}
+
+export async function login(email: string, password: string): Promise<void> {
+  try {
+    const data = await graphqlClient.request<{ login: Session }>(
+      LOGIN_MUTATION,
+      { email, password },
+    );
+    saveSession(data.login);
+  } catch (error) {
+    throw parseGraphQLError(error);
+  }
+}
+
+export async function logout(): Promise<void> {
+  const refreshToken = sessionStorage.getItem(REFRESH_TOKEN_KEY);
+  endSession();
+  if (!refreshToken) {
+    return;
+  }
+  try {
+    await graphqlClient.request(LOGOUT_MUTATION, { refreshToken });
+  } catch (error) {
+    // The session is gone from this browser either way, and the refresh
+    // token expires on its own.
+    console.error("Logout failed:", error);
+  }
+}
+
+async function refreshSession(): Promise<string> {
+  const refreshToken = sessionStorage.getItem(REFRESH_TOKEN_KEY);
+  if (!refreshToken) {
+    endSession();
+    throw new ApiError("Your session has ended. Please sign in again.", {
+      code: "UNAUTHORIZED",
+      isUnauthorized: true,
+    });
+  }
+  try {
+    const data = await graphqlClient.request<{ refreshSession: Session }>(
+      REFRESH_SESSION_MUTATION,
+      { refreshToken },
+    );
+    return saveSession(data.refreshSession);
+  } catch (error) {
+    const apiError = parseGraphQLError(error);
+    if (!apiError.isNetworkError) {
+      endSession();
+    }
+    throw apiError;
+  }
+}
+
+// getAccessToken returns the access token, refreshing the session first when
+// the token is missing or about to expire. Concurrent requests share one
+// refresh, since the refresh token can only be used once.
+async function getAccessToken(): Promise<string> {
+  if (accessToken && Date.now() < accessTokenExpiresAt - REFRESH_MARGIN_MS) {
+    return accessToken;
+  }
+  if (!refreshing) {
+    refreshing = refreshSession().finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+}
+
+async function createAdminClient(): Promise<GraphQLClient> {
   return new GraphQLClient(endpoint, {
     headers: {
       "Content-Type": "application/json",
-      "X-Admin-Password": getAdminPassword(),
+      Authorization: `Bearer ${await getAccessToken()}`,
     },
   });
 }
@@ -54,16 +180,16 @@ async function adminRequest<T>(
   variables?: Record<string, unknown>,
 ): Promise<T> {
   try {
-    const client = createAdminClient();
+    const client = await createAdminClient();
     return await client.request<T>(query, variables);
   } catch (error) {
     console.error("Admin GraphQL request failed:", error);
-    throw parseGraphQLError(error);
+    throw error instanceof ApiError ? error : parseGraphQLError(error);
   }
 }
 
 // Queries for listing entities
-const LIST_AUTHORS_QUERY = `
+const LIST_AUTHORS_QUERY = /* GraphQL */ `
   query ListAuthors($limit: Int, $offset: Int, $search: String) {
     authors(limit: $limit, offset: $offset, search: $search) {
       id
@@ -75,7 +201,7 @@ const LIST_AUTHORS_QUERY = `
   }
 `;
 
-const LIST_SERIES_QUERY = `
+const LIST_SERIES_QUERY = /* GraphQL */ `
   query ListSeries($limit: Int, $offset: Int, $search: String) {
     seriesList(limit: $limit, offset: $offset, search: $search) {
       id
@@ -87,7 +213,7 @@ const LIST_SERIES_QUERY = `
   }
 `;
 
-const LIST_BOOKS_QUERY = `
+const LIST_BOOKS_QUERY = /* GraphQL */ `
   query ListBooks($limit: Int, $offset: Int) {
     books(limit: $limit, offset: $offset) {
       id
@@ -108,8 +234,8 @@ const LIST_BOOKS_QUERY = `
   }
 `;
 
-const SEARCH_BOOKS_QUERY = `
-  query SearchBooks($input: SearchBooksInput!) {
+const SEARCH_BOOKS_QUERY = /* GraphQL */ `
+  query AdminSearchBooks($input: SearchBooksInput!) {
     searchBooks(input: $input) {
       books {
         id
@@ -133,7 +259,7 @@ const SEARCH_BOOKS_QUERY = `
 `;
 
 // Mutations
-const CREATE_BOOK_MUTATION = `
+const CREATE_BOOK_MUTATION = /* GraphQL */ `
   mutation CreateBook($input: NewBook!) {
     createBook(input: $input) {
       id
@@ -142,7 +268,7 @@ const CREATE_BOOK_MUTATION = `
   }
 `;
 
-const UPDATE_BOOK_MUTATION = `
+const UPDATE_BOOK_MUTATION = /* GraphQL */ `
   mutation UpdateBook($id: ID!, $input: UpdateBook!) {
     updateBook(id: $id, input: $input) {
       id
@@ -151,13 +277,13 @@ const UPDATE_BOOK_MUTATION = `
   }
 `;
 
-const DELETE_BOOK_MUTATION = `
+const DELETE_BOOK_MUTATION = /* GraphQL */ `
   mutation DeleteBook($id: ID!) {
     deleteBook(id: $id)
   }
 `;
 
-const CREATE_AUTHOR_MUTATION = `
+const CREATE_AUTHOR_MUTATION = /* GraphQL */ `
   mutation CreateAuthor($input: NewAuthor!) {
     createAuthor(input: $input) {
       id
@@ -166,7 +292,7 @@ const CREATE_AUTHOR_MUTATION = `
   }
 `;
 
-const UPDATE_AUTHOR_MUTATION = `
+const UPDATE_AUTHOR_MUTATION = /* GraphQL */ `
   mutation UpdateAuthor($id: ID!, $input: UpdateAuthor!) {
     updateAuthor(id: $id, input: $input) {
       id
@@ -175,13 +301,13 @@ const UPDATE_AUTHOR_MUTATION = `
   }
 `;
 
-const DELETE_AUTHOR_MUTATION = `
+const DELETE_AUTHOR_MUTATION = /* GraphQL */ `
   mutation DeleteAuthor($id: ID!) {
     deleteAuthor(id: $id)
   }
 `;
 
-const CREATE_SERIES_MUTATION = `
+const CREATE_SERIES_MUTATION = /* GraphQL */ `
   mutation CreateSeries($input: NewSeries!) {
     createSeries(input: $input) {
       id
@@ -190,7 +316,7 @@ const CREATE_SERIES_MUTATION = `
   }
 `;
 
-const UPDATE_SERIES_MUTATION = `
+const UPDATE_SERIES_MUTATION = /* GraphQL */ `
   mutation UpdateSeries($id: ID!, $input: UpdateSeries!) {
     updateSeries(id: $id, input: $input) {
       id
@@ -199,7 +325,7 @@ const UPDATE_SERIES_MUTATION = `
   }
 `;
 
-const DELETE_SERIES_MUTATION = `
+const DELETE_SERIES_MUTATION = /* GraphQL */ `
   mutation DeleteSeries($id: ID!) {
     deleteSeries(id: $id)
   }
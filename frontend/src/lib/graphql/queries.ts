@@ -9,7 +9,7 @@ import type {
 } from "./types";
 
 // Fragment for book list items (used in search results)
-const BOOK_LIST_FRAGMENT = `
+const BOOK_LIST_FRAGMENT = /* GraphQL */ `
   fragment BookListItem on Book {
     id
     title
@@ -33,14 +33,22 @@ const BOOK_LIST_FRAGMENT = `
     seriesPosition
     pages
     language
-    genres
-    tags
+    genres {
+      id
+      name
+      slug
+    }
+    tags {
+      id
+      name
+      slug
+    }
     imageUrl
   }
 `;
 
 // Fragment for full book details
-const BOOK_DETAIL_FRAGMENT = `
+const BOOK_DETAIL_FRAGMENT = /* GraphQL */ `
   fragment BookDetail on Book {
     id
     title
@@ -72,8 +80,16 @@ const BOOK_DETAIL_FRAGMENT = `
     pages
     language
     description
-    genres
-    tags
+    genres {
+      id
+      name
+      slug
+    }
+    tags {
+      id
+      name
+      slug
+    }
     imageUrl
     createdAt
     updatedAt
@@ -88,7 +104,7 @@ const BOOK_DETAIL_FRAGMENT = `
   }
 `;
 
-const SEARCH_BOOKS_QUERY = `
+const SEARCH_BOOKS_QUERY = /* GraphQL */ `
   ${BOOK_LIST_FRAGMENT}
   query SearchBooks($input: SearchBooksInput!) {
     searchBooks(input: $input) {
@@ -100,7 +116,7 @@ const SEARCH_BOOKS_QUERY = `
   }
 `;
 
-const GET_BOOK_QUERY = `
+const GET_BOOK_QUERY = /* GraphQL */ `
   ${BOOK_DETAIL_FRAGMENT}
   query GetBook($id: ID!) {
     book(id: $id) {
@@ -109,7 +125,7 @@ const GET_BOOK_QUERY = `
   }
 `;
 
-const GET_AUTHOR_QUERY = `
+const GET_AUTHOR_QUERY = /* GraphQL */ `
   query GetAuthor($id: ID!) {
     author(id: $id) {
       id
@@ -133,7 +149,7 @@ const GET_AUTHOR_QUERY = `
   }
 `;
 
-const GET_AUTHOR_BY_SLUG_QUERY = `
+const GET_AUTHOR_BY_SLUG_QUERY = /* GraphQL */ `
   query GetAuthorBySlug($slug: String!) {
     authorBySlug(slug: $slug) {
       id
@@ -157,7 +173,7 @@ const GET_AUTHOR_BY_SLUG_QUERY = `
   }
 `;
 
-const GET_SERIES_QUERY = `
+const GET_SERIES_QUERY = /* GraphQL */ `
   query GetSeries($id: ID!) {
     series(id: $id) {
       id
@@ -181,7 +197,7 @@ const GET_SERIES_QUERY = `
   }
 `;
 
-const GET_SERIES_BY_SLUG_QUERY = `
+const GET_SERIES_BY_SLUG_QUERY = /* GraphQL */ `
   query GetSeriesBySlug($slug: String!) {
     seriesBySlug(slug: $slug) {
       id
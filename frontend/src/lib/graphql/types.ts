@@ -37,6 +37,20 @@ export type Series = {
   updatedAt: string;
 };
 
+// Genre type
+export type Genre = {
+  id: string;
+  name: string;
+  slug: string;
+};
+
+// Tag type
+export type Tag = {
+  id: string;
+  name: string;
+  slug: string;
+};
+
 // Book type matching normalized GraphQL schema
 export type Book = {
   id: string;
@@ -52,8 +66,8 @@ export type Book = {
   description?: Maybe<string>;
   series?: Maybe<Series>;
   seriesPosition?: Maybe<number>;
-  genres?: Maybe<string>;
-  tags?: Maybe<string>;
+  genres: Array<Genre>;
+  tags: Array<Tag>;
   imageUrl?: Maybe<string>;
   createdAt: string;
   updatedAt: string;
@@ -66,7 +80,8 @@ export type SortOption =
   | "title_desc"
   | "date_asc"
   | "date_desc"
-  | "author";
+  | "author"
+  | "relevance";
 
 // Search input type
 export type SearchBooksInput = {
@@ -76,7 +91,17 @@ export type SearchBooksInput = {
   seriesId?: InputMaybe<string>;
   authorName?: InputMaybe<string>;
   genre?: InputMaybe<string>;
+  genres?: InputMaybe<Array<string>>;
+  genreMatch?: InputMaybe<"ANY" | "ALL">;
+  language?: InputMaybe<string>;
+  minPages?: InputMaybe<number>;
+  maxPages?: InputMaybe<number>;
+  publishedAfter?: InputMaybe<string>;
+  publishedBefore?: InputMaybe<string>;
+  isbn?: InputMaybe<string>;
+  inSeries?: InputMaybe<boolean>;
   sortBy?: InputMaybe<SortOption>;
+  fuzzy?: InputMaybe<boolean>;
   limit?: InputMaybe<number>;
   offset?: InputMaybe<number>;
 };
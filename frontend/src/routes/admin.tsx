@@ -1,7 +1,7 @@
 import { createFileRoute } from "@tanstack/react-router";
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { ErrorBoundary } from "@/components/ErrorBoundary";
-import { getAdminPassword } from "@/lib/graphql/admin";
+import { hasAdminSession, onSessionEnd } from "@/lib/graphql/admin";
 import {
   AdminLogin,
   AdminHeader,
@@ -23,10 +23,15 @@ export const Route = createFileRoute("/admin")({
 
 function AdminPage() {
   const [isAuthenticated, setIsAuthenticated] = useState(() => {
-    return !!getAdminPassword();
+    return hasAdminSession();
   });
   const [activeTab, setActiveTab] = useState<Tab>("books");
 
+  // Back to the login form when the session cannot be refreshed.
+  useEffect(() => {
+    return onSessionEnd(() => setIsAuthenticated(false));
+  }, []);
+
   const handleLogin = () => {
     setIsAuthenticated(true);
   };
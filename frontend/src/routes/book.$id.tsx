@@ -222,50 +222,50 @@ function BookDetailPage() {
             </div>
 
             {/* Genres and Tags */}
-            {(book.genres || book.tags) && (
+            {(book.genres.length > 0 || book.tags.length > 0) && (
               <>
                 <Separator />
                 <div className="space-y-4">
-                  {book.genres && (
+                  {book.genres.length > 0 && (
                     <div>
                       <h3 className="text-base sm:text-lg font-semibold mb-2 sm:mb-3">
                         Genres
                       </h3>
                       <div className="flex flex-wrap gap-2">
-                        {book.genres.split(",").map((genre) => (
+                        {book.genres.map((genre) => (
                           <Link
-                            key={genre}
+                            key={genre.id}
                             to="/search"
-                            search={{ genre: genre.trim(), q: "", page: 1 }}
+                            search={{ genre: genre.slug, q: "", page: 1 }}
                           >
                             <Badge
                               variant="default"
                               className="px-2 sm:px-3 py-1 cursor-pointer hover:opacity-80 transition-opacity"
                             >
-                              {genre.trim()}
+                              {genre.name}
                             </Badge>
                           </Link>
                         ))}
                       </div>
                     </div>
                   )}
-                  {book.tags && (
+                  {book.tags.length > 0 && (
                     <div>
                       <h3 className="text-base sm:text-lg font-semibold mb-2 sm:mb-3">
                         Tags
                       </h3>
                       <div className="flex flex-wrap gap-2">
-                        {book.tags.split(",").map((tag) => (
+                        {book.tags.map((tag) => (
                           <Link
-                            key={tag}
+                            key={tag.id}
                             to="/search"
-                            search={{ q: tag.trim(), genre: "", page: 1 }}
+                            search={{ q: tag.name, genre: "", page: 1 }}
                           >
                             <Badge
                               variant="secondary"
                               className="px-2 sm:px-3 py-1 cursor-pointer hover:opacity-80 transition-opacity"
                             >
-                              {tag.trim()}
+                              {tag.name}
                             </Badge>
                           </Link>
                         ))}
@@ -186,7 +186,9 @@ function DocsPage() {
     isbn10
     isbn13
     pages
-    genres
+    genres {
+      name
+    }
   }
 }`}
                         </pre>
@@ -233,7 +235,8 @@ function DocsPage() {
                           </li>
                           <li>
                             <code>sortBy</code> (String, optional): Sort option
-                            (title_asc, title_desc, date_asc, date_desc, author)
+                            (newest, title_asc, title_desc, date_asc, date_desc,
+                            author, relevance)
                           </li>
                           <li>
                             <code>limit</code> (Int, default: 20): Maximum
@@ -264,7 +267,9 @@ function DocsPage() {
         name
       }
       publishedDate
-      genres
+      genres {
+        name
+      }
     }
   }
 }`}
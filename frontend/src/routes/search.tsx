@@ -56,7 +56,8 @@ function SearchResultsPage() {
   const { data, isLoading, error } = useSearchBooks({
     query: q || undefined,
     genre: genre || undefined,
-    sortBy: sort || undefined,
+    sortBy: sort || "relevance",
+    fuzzy: true,
     limit: ITEMS_PER_PAGE,
     offset,
   });
@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"book-nexus/internal/audit"
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// record writes an audit event on tx, the transaction of the mutation it
+// describes, so the event commits with the change or not at all. Failing to
+// write it fails the mutation.
+func record(ctx context.Context, tx database.DBTX, entityType string, id uuid.UUID, action audit.Action, before, after any) error {
+	return audit.NewService(tx).Record(ctx, audit.Event{
+		EntityType: entityType,
+		EntityID:   id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// recordTrashedBooks writes a delete event for each book a cascading delete
+// trashed along with its author or series. The rows are as trashed, which
+// differs from how they were only in deleted_at.
+func recordTrashedBooks(ctx context.Context, tx database.DBTX, trashed []sqlc.Book) error {
+	for _, book := range trashed {
+		before := book
+		before.DeletedAt = nil
+		if err := record(ctx, tx, audit.TypeBook, book.ID, audit.ActionDelete, before, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bookRelation is the books column a delete moves books off.
+type bookRelation int
+
+const (
+	bookAuthor bookRelation = iota
+	bookPublisher
+	bookSeries
+)
+
+// recordMovedBooks writes an update event for each book that deleting the
+// entity it referenced through relation, from, moved to another entity or
+// left without one. The rows are as updated, which differs from how they
+// were only in that column and updated_at.
+func recordMovedBooks(ctx context.Context, tx database.DBTX, relation bookRelation, from uuid.UUID, moved []sqlc.Book) error {
+	for _, book := range moved {
+		before := book
+		switch relation {
+		case bookAuthor:
+			before.AuthorID = from
+		case bookPublisher:
+			before.PublisherID = pgtype.UUID{Bytes: from, Valid: true}
+		case bookSeries:
+			before.SeriesID = pgtype.UUID{Bytes: from, Valid: true}
+		}
+		if err := record(ctx, tx, audit.TypeBook, book.ID, audit.ActionUpdate, before, book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordRestoredBooks writes a restore event for each book restored along
+// with its author or series.
+func recordRestoredBooks(ctx context.Context, tx database.DBTX, restored []sqlc.Book) error {
+	for _, book := range restored {
+		if err := record(ctx, tx, audit.TypeBook, book.ID, audit.ActionRestore, nil, book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditSnapshot loads a live entity as it is now, for the before or after
+// side of an audit diff.
+func auditSnapshot(ctx context.Context, q *sqlc.Queries, entityType string, id uuid.UUID) (any, error) {
+	switch entityType {
+	case audit.TypeBook:
+		return q.GetBookByID(ctx, id)
+	case audit.TypeAuthor:
+		return q.GetAuthorByID(ctx, id)
+	case audit.TypeSeries:
+		return q.GetSeriesByID(ctx, id)
+	case audit.TypePublisher:
+		return q.GetPublisherByID(ctx, id)
+	default:
+		return nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+}
+
+// userSnapshot is a user as the audit log keeps it, without the password
+// hash.
+type userSnapshot struct {
+	ID        uuid.UUID
+	Email     string
+	Name      string
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func auditUser(u sqlc.User) userSnapshot {
+	return userSnapshot{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// apiTokenSnapshot is an API token as the audit log keeps it, without the
+// token hash.
+type apiTokenSnapshot struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func auditAPIToken(t sqlc.ApiToken) apiTokenSnapshot {
+	return apiTokenSnapshot{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		RevokedAt:  t.RevokedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
@@ -1,38 +1,25 @@
 package graph
 
 import (
+	"book-nexus/internal/users"
 	"context"
 	"errors"
-	"os"
 )
 
-type contextKey string
-
-const adminAuthKey contextKey = "isAdmin"
-
-var ErrUnauthorized = errors.New("unauthorized: admin access required")
-
-// IsAdmin checks if the current request has admin privileges
-func IsAdmin(ctx context.Context) bool {
-	val, ok := ctx.Value(adminAuthKey).(bool)
-	return ok && val
-}
-
-// WithAdminAuth adds admin authentication status to context
-func WithAdminAuth(ctx context.Context, password string) context.Context {
-	adminPassword := os.Getenv("ADMIN_PASSWORD")
-	if adminPassword == "" {
-		// No admin password configured, deny all admin access
-		return context.WithValue(ctx, adminAuthKey, false)
-	}
-	isAdmin := password == adminPassword
-	return context.WithValue(ctx, adminAuthKey, isAdmin)
-}
+var (
+	ErrUnauthorized = errors.New("unauthorized: sign in with an API token or the admin password")
+	ErrForbidden    = errors.New("forbidden: your role does not allow this")
+)
 
-// RequireAdmin returns an error if the request is not authenticated as admin
-func RequireAdmin(ctx context.Context) error {
-	if !IsAdmin(ctx) {
+// Require returns an error unless the request is authenticated as a
+// principal whose role has the permission.
+func Require(ctx context.Context, perm users.Permission) error {
+	p, ok := users.PrincipalFrom(ctx)
+	if !ok {
 		return ErrUnauthorized
 	}
+	if !p.Role.Can(perm) {
+		return ErrForbidden
+	}
 	return nil
 }
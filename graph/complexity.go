@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"book-nexus/graph/model"
+	"book-nexus/internal/pagination"
+	"book-nexus/internal/suggest"
+	"book-nexus/internal/trash"
+)
+
+// Sizes assumed for lists whose length a query does not set.
+const (
+	// defaultListLimit is what the offset-paginated list queries return
+	// without a limit.
+	defaultListLimit = 100
+	// defaultSearchLimit is the SearchBooksInput.limit default.
+	defaultSearchLimit = 20
+	// nestedListSize estimates lists nested under an entity, like an
+	// author's books, which have no limit argument.
+	nestedListSize = 20
+	// smallListSize estimates short nested lists: a book's genres and tags,
+	// and its recommendations.
+	smallListSize = 5
+	// maxListSize bounds the size used in the calculation so the cost cannot
+	// overflow. Anything near it is far over any limit anyway.
+	maxListSize = 1 << 20
+)
+
+// NewComplexity returns the cost functions for the executable schema. A list
+// costs its size times the cost of one element, so a query pays for what it
+// fans out to: books(limit: 1000) { recommendations { title } } costs about
+// 1000 * 5 * 2. Fields not set here cost 1 plus their children.
+func NewComplexity() ComplexityRoot {
+	var c ComplexityRoot
+
+	offsetList := func(child int, _ *string, limit *int32, _ *int32) int {
+		return listCost(child, listSize(limit, defaultListLimit))
+	}
+	connection := func(child int, _ *string, first *int32, _ *string) int {
+		return listCost(child, int(pagination.PageSize(first)))
+	}
+
+	c.Query.Books = func(child int, limit *int32, _ *int32) int {
+		return listCost(child, listSize(limit, defaultListLimit))
+	}
+	c.Query.Authors = offsetList
+	c.Query.Publishers = offsetList
+	c.Query.SeriesList = offsetList
+	c.Query.Genres = offsetList
+	c.Query.Tags = offsetList
+	c.Query.SearchBooks = func(child int, input model.SearchBooksInput) int {
+		return listCost(child, listSize(input.Limit, defaultSearchLimit))
+	}
+	c.Query.Suggest = func(child int, _ string, _ []model.EntityType, limit *int32) int {
+		return listCost(child, min(listSize(limit, suggest.DefaultLimit), suggest.MaxLimit))
+	}
+	c.Query.Trash = func(child int, _ *model.EntityType, limit *int32) int {
+		return listCost(child, min(listSize(limit, trash.DefaultLimit), trash.MaxLimit))
+	}
+
+	c.Query.BooksConnection = func(child int, first *int32, _ *string, _ *string) int {
+		return listCost(child, int(pagination.PageSize(first)))
+	}
+	c.Query.SearchBooksConnection = func(child int, _ model.SearchBooksInput, first *int32, _ *string) int {
+		return listCost(child, int(pagination.PageSize(first)))
+	}
+	c.Query.AuthorsConnection = connection
+	c.Query.PublishersConnection = connection
+	c.Query.SeriesConnection = connection
+	c.Query.AuditLog = func(child int, _ *string, _ *model.EntityType, _ *string, first *int32, _ *string) int {
+		return listCost(child, int(pagination.PageSize(first)))
+	}
+
+	nested := func(child int) int { return listCost(child, nestedListSize) }
+	small := func(child int) int { return listCost(child, smallListSize) }
+	c.Author.Books = nested
+	c.Publisher.Books = nested
+	c.Series.Books = nested
+	c.Genre.Books = nested
+	c.Tag.Books = nested
+	c.Author.Revisions = nested
+	c.Book.Revisions = nested
+	c.Publisher.Revisions = nested
+	c.Series.Revisions = nested
+	c.Book.Recommendations = small
+	c.Book.Genres = small
+	c.Book.Tags = small
+
+	// upsertBooks returns a result per input row.
+	c.Mutation.UpsertBooks = func(child int, input []*model.BookUpsertInput, _ model.BookMatch, _ *string) int {
+		return listCost(child, len(input))
+	}
+
+	return c
+}
+
+// listSize is the size a limit argument asks for, or def without one.
+func listSize(limit *int32, def int) int {
+	if limit == nil {
+		return def
+	}
+	return min(max(int(*limit), 1), maxListSize)
+}
+
+func listCost(child, size int) int {
+	return 1 + size*max(child, 1)
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"book-nexus/graph/model"
+	"book-nexus/internal/deletion"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// maxBlockingBookIDs caps how many blocking book IDs a DELETE_RESTRICTED
+// error carries; blockingBookCount always has the full number.
+const maxBlockingBookIDs = 100
+
+// toDeleteStrategy converts the GraphQL delete strategy into the service one.
+// A nil strategy means RESTRICT.
+func toDeleteStrategy(strategy *model.DeleteStrategy) (deletion.Strategy, error) {
+	if strategy == nil {
+		return deletion.Strategy{Mode: deletion.Restrict}, nil
+	}
+
+	out := deletion.Strategy{Mode: deletion.Mode(strategy.Mode)}
+	if strategy.ReassignTo != nil {
+		targetID, err := uuid.Parse(*strategy.ReassignTo)
+		if err != nil {
+			return deletion.Strategy{}, fmt.Errorf("invalid reassignTo ID: %v", err)
+		}
+		out.ReassignTo = &targetID
+	}
+	return out, nil
+}
+
+// deleteError turns a *deletion.BlockedError into a GraphQL error with a
+// DELETE_RESTRICTED code and the blocking book IDs as extensions, so clients
+// can show what is in the way. Other errors pass through unchanged.
+func deleteError(err error) error {
+	var blocked *deletion.BlockedError
+	if !errors.As(err, &blocked) {
+		return err
+	}
+
+	ids := blocked.BookIDs
+	if len(ids) > maxBlockingBookIDs {
+		ids = ids[:maxBlockingBookIDs]
+	}
+	bookIDs := make([]string, len(ids))
+	for i, id := range ids {
+		bookIDs[i] = id.String()
+	}
+
+	return &gqlerror.Error{
+		Message: blocked.Error(),
+		Extensions: map[string]any{
+			"code":              "DELETE_RESTRICTED",
+			"entity":            blocked.Entity,
+			"blockingBookIds":   bookIDs,
+			"blockingBookCount": len(blocked.BookIDs),
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"context"
+
+	"book-nexus/internal/database/sqlc"
+)
+
+// CountFunc computes a connection's totalCount only when a client selects it.
+type CountFunc func(ctx context.Context) (int64, error)
+
+type BookEdge struct {
+	Cursor string
+	Node   *sqlc.Book
+}
+
+type BookConnection struct {
+	Edges    []*BookEdge
+	PageInfo *PageInfo
+	Count    CountFunc
+}
+
+type AuthorEdge struct {
+	Cursor string
+	Node   *sqlc.Author
+}
+
+type AuthorConnection struct {
+	Edges    []*AuthorEdge
+	PageInfo *PageInfo
+	Count    CountFunc
+}
+
+type PublisherEdge struct {
+	Cursor string
+	Node   *sqlc.Publisher
+}
+
+type PublisherConnection struct {
+	Edges    []*PublisherEdge
+	PageInfo *PageInfo
+	Count    CountFunc
+}
+
+type SeriesEdge struct {
+	Cursor string
+	Node   *sqlc.Series
+}
+
+type SeriesConnection struct {
+	Edges    []*SeriesEdge
+	PageInfo *PageInfo
+	Count    CountFunc
+}
@@ -4,11 +4,69 @@ package model
 
 import (
 	"book-nexus/internal/database/sqlc"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
 )
 
+type AuditEventConnection struct {
+	Edges    []*AuditEventEdge `json:"edges"`
+	PageInfo *PageInfo         `json:"pageInfo"`
+}
+
+type AuditEventEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *sqlc.AuditEvent `json:"node"`
+}
+
+type BookUpsertInput struct {
+	Title          string  `json:"title"`
+	Subtitle       *string `json:"subtitle,omitempty"`
+	AuthorID       *string `json:"authorId,omitempty"`
+	AuthorName     *string `json:"authorName,omitempty"`
+	PublisherID    *string `json:"publisherId,omitempty"`
+	PublisherName  *string `json:"publisherName,omitempty"`
+	PublishedDate  *string `json:"publishedDate,omitempty"`
+	Isbn10         *string `json:"isbn10,omitempty"`
+	Isbn13         *string `json:"isbn13,omitempty"`
+	Pages          *int32  `json:"pages,omitempty"`
+	Language       *string `json:"language,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	SeriesID       *string `json:"seriesId,omitempty"`
+	SeriesName     *string `json:"seriesName,omitempty"`
+	SeriesPosition *int32  `json:"seriesPosition,omitempty"`
+	Genres         *string `json:"genres,omitempty"`
+	Tags           *string `json:"tags,omitempty"`
+	ImageURL       *string `json:"imageUrl,omitempty"`
+}
+
+type BookUpsertResult struct {
+	Index  int32            `json:"index"`
+	Status BookUpsertStatus `json:"status"`
+	Book   *sqlc.Book       `json:"book,omitempty"`
+	Error  *string          `json:"error,omitempty"`
+}
+
+type DeleteStrategy struct {
+	Mode       DeleteMode `json:"mode"`
+	ReassignTo *string    `json:"reassignTo,omitempty"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int32  `json:"count"`
+}
+
 type Mutation struct {
 }
 
+type NewAPIToken struct {
+	Token    string         `json:"token"`
+	APIToken *sqlc.ApiToken `json:"apiToken"`
+}
+
 type NewAuthor struct {
 	Name string  `json:"name"`
 	Slug *string `json:"slug,omitempty"`
@@ -33,30 +91,104 @@ type NewBook struct {
 	ImageURL       *string `json:"imageUrl,omitempty"`
 }
 
+type NewBookWithRelations struct {
+	Title          string  `json:"title"`
+	Subtitle       *string `json:"subtitle,omitempty"`
+	AuthorName     string  `json:"authorName"`
+	PublisherName  *string `json:"publisherName,omitempty"`
+	PublishedDate  *string `json:"publishedDate,omitempty"`
+	Isbn10         *string `json:"isbn10,omitempty"`
+	Isbn13         *string `json:"isbn13,omitempty"`
+	Pages          *int32  `json:"pages,omitempty"`
+	Language       *string `json:"language,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	SeriesName     *string `json:"seriesName,omitempty"`
+	SeriesPosition *int32  `json:"seriesPosition,omitempty"`
+	Genres         *string `json:"genres,omitempty"`
+	Tags           *string `json:"tags,omitempty"`
+	ImageURL       *string `json:"imageUrl,omitempty"`
+}
+
+type NewPublisher struct {
+	Name    string  `json:"name"`
+	Slug    *string `json:"slug,omitempty"`
+	Website *string `json:"website,omitempty"`
+}
+
 type NewSeries struct {
 	Name        string  `json:"name"`
 	Slug        *string `json:"slug,omitempty"`
 	Description *string `json:"description,omitempty"`
 }
 
+type NewUser struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Role     Role   `json:"role"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
 type Query struct {
 }
 
 type SearchBooksInput struct {
-	Query       *string `json:"query,omitempty"`
-	AuthorID    *string `json:"authorId,omitempty"`
-	PublisherID *string `json:"publisherId,omitempty"`
-	SeriesID    *string `json:"seriesId,omitempty"`
-	AuthorName  *string `json:"authorName,omitempty"`
-	Genre       *string `json:"genre,omitempty"`
-	SortBy      *string `json:"sortBy,omitempty"`
-	Limit       *int32  `json:"limit,omitempty"`
-	Offset      *int32  `json:"offset,omitempty"`
+	Query           *string     `json:"query,omitempty"`
+	AuthorID        *string     `json:"authorId,omitempty"`
+	PublisherID     *string     `json:"publisherId,omitempty"`
+	SeriesID        *string     `json:"seriesId,omitempty"`
+	AuthorName      *string     `json:"authorName,omitempty"`
+	Genre           *string     `json:"genre,omitempty"`
+	Genres          []string    `json:"genres,omitempty"`
+	GenreMatch      *GenreMatch `json:"genreMatch,omitempty"`
+	Language        *string     `json:"language,omitempty"`
+	MinPages        *int32      `json:"minPages,omitempty"`
+	MaxPages        *int32      `json:"maxPages,omitempty"`
+	PublishedAfter  *string     `json:"publishedAfter,omitempty"`
+	PublishedBefore *string     `json:"publishedBefore,omitempty"`
+	Isbn            *string     `json:"isbn,omitempty"`
+	InSeries        *bool       `json:"inSeries,omitempty"`
+	SortBy          *string     `json:"sortBy,omitempty"`
+	Fuzzy           *bool       `json:"fuzzy,omitempty"`
+	Limit           *int32      `json:"limit,omitempty"`
+	Offset          *int32      `json:"offset,omitempty"`
+}
+
+type SearchFacets struct {
+	Genres     []*FacetValue `json:"genres"`
+	Languages  []*FacetValue `json:"languages"`
+	Publishers []*FacetValue `json:"publishers"`
+	Authors    []*FacetValue `json:"authors"`
+	Decades    []*FacetValue `json:"decades"`
+}
+
+type Session struct {
+	AccessToken           string     `json:"accessToken"`
+	AccessTokenExpiresAt  string     `json:"accessTokenExpiresAt"`
+	RefreshToken          string     `json:"refreshToken"`
+	RefreshTokenExpiresAt string     `json:"refreshTokenExpiresAt"`
+	User                  *sqlc.User `json:"user"`
 }
 
-type SearchResult struct {
-	Books []*sqlc.Book `json:"books"`
-	Total int32        `json:"total"`
+type Suggestion struct {
+	Type  EntityType `json:"type"`
+	ID    string     `json:"id"`
+	Name  string     `json:"name"`
+	Slug  *string    `json:"slug,omitempty"`
+	Score float64    `json:"score"`
+}
+
+type TrashItem struct {
+	Type      EntityType `json:"type"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	DeletedAt string     `json:"deletedAt"`
 }
 
 type UpdateAuthor struct {
@@ -83,8 +215,427 @@ type UpdateBook struct {
 	ImageURL       *string `json:"imageUrl,omitempty"`
 }
 
+type UpdatePublisher struct {
+	Name    string  `json:"name"`
+	Slug    *string `json:"slug,omitempty"`
+	Website *string `json:"website,omitempty"`
+}
+
 type UpdateSeries struct {
 	Name        string  `json:"name"`
 	Slug        *string `json:"slug,omitempty"`
 	Description *string `json:"description,omitempty"`
 }
+
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "CREATE"
+	AuditActionUpdate  AuditAction = "UPDATE"
+	AuditActionDelete  AuditAction = "DELETE"
+	AuditActionRestore AuditAction = "RESTORE"
+	AuditActionPurge   AuditAction = "PURGE"
+	AuditActionRevert  AuditAction = "REVERT"
+)
+
+var AllAuditAction = []AuditAction{
+	AuditActionCreate,
+	AuditActionUpdate,
+	AuditActionDelete,
+	AuditActionRestore,
+	AuditActionPurge,
+	AuditActionRevert,
+}
+
+func (e AuditAction) IsValid() bool {
+	switch e {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionRestore, AuditActionPurge, AuditActionRevert:
+		return true
+	}
+	return false
+}
+
+func (e AuditAction) String() string {
+	return string(e)
+}
+
+func (e *AuditAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditAction", str)
+	}
+	return nil
+}
+
+func (e AuditAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AuditAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AuditAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type BookMatch string
+
+const (
+	BookMatchIsbn13      BookMatch = "ISBN13"
+	BookMatchIsbn10      BookMatch = "ISBN10"
+	BookMatchTitleAuthor BookMatch = "TITLE_AUTHOR"
+)
+
+var AllBookMatch = []BookMatch{
+	BookMatchIsbn13,
+	BookMatchIsbn10,
+	BookMatchTitleAuthor,
+}
+
+func (e BookMatch) IsValid() bool {
+	switch e {
+	case BookMatchIsbn13, BookMatchIsbn10, BookMatchTitleAuthor:
+		return true
+	}
+	return false
+}
+
+func (e BookMatch) String() string {
+	return string(e)
+}
+
+func (e *BookMatch) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BookMatch(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BookMatch", str)
+	}
+	return nil
+}
+
+func (e BookMatch) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BookMatch) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BookMatch) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type BookUpsertStatus string
+
+const (
+	BookUpsertStatusCreated   BookUpsertStatus = "CREATED"
+	BookUpsertStatusUpdated   BookUpsertStatus = "UPDATED"
+	BookUpsertStatusUnchanged BookUpsertStatus = "UNCHANGED"
+	BookUpsertStatusError     BookUpsertStatus = "ERROR"
+)
+
+var AllBookUpsertStatus = []BookUpsertStatus{
+	BookUpsertStatusCreated,
+	BookUpsertStatusUpdated,
+	BookUpsertStatusUnchanged,
+	BookUpsertStatusError,
+}
+
+func (e BookUpsertStatus) IsValid() bool {
+	switch e {
+	case BookUpsertStatusCreated, BookUpsertStatusUpdated, BookUpsertStatusUnchanged, BookUpsertStatusError:
+		return true
+	}
+	return false
+}
+
+func (e BookUpsertStatus) String() string {
+	return string(e)
+}
+
+func (e *BookUpsertStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BookUpsertStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BookUpsertStatus", str)
+	}
+	return nil
+}
+
+func (e BookUpsertStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BookUpsertStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BookUpsertStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DeleteMode string
+
+const (
+	DeleteModeRestrict   DeleteMode = "RESTRICT"
+	DeleteModeReassignTo DeleteMode = "REASSIGN_TO"
+	DeleteModeCascade    DeleteMode = "CASCADE"
+	DeleteModeNullify    DeleteMode = "NULLIFY"
+)
+
+var AllDeleteMode = []DeleteMode{
+	DeleteModeRestrict,
+	DeleteModeReassignTo,
+	DeleteModeCascade,
+	DeleteModeNullify,
+}
+
+func (e DeleteMode) IsValid() bool {
+	switch e {
+	case DeleteModeRestrict, DeleteModeReassignTo, DeleteModeCascade, DeleteModeNullify:
+		return true
+	}
+	return false
+}
+
+func (e DeleteMode) String() string {
+	return string(e)
+}
+
+func (e *DeleteMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeleteMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeleteMode", str)
+	}
+	return nil
+}
+
+func (e DeleteMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DeleteMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DeleteMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type EntityType string
+
+const (
+	EntityTypeBook      EntityType = "BOOK"
+	EntityTypeAuthor    EntityType = "AUTHOR"
+	EntityTypeSeries    EntityType = "SERIES"
+	EntityTypePublisher EntityType = "PUBLISHER"
+	EntityTypeUser      EntityType = "USER"
+	EntityTypeAPIToken  EntityType = "API_TOKEN"
+)
+
+var AllEntityType = []EntityType{
+	EntityTypeBook,
+	EntityTypeAuthor,
+	EntityTypeSeries,
+	EntityTypePublisher,
+	EntityTypeUser,
+	EntityTypeAPIToken,
+}
+
+func (e EntityType) IsValid() bool {
+	switch e {
+	case EntityTypeBook, EntityTypeAuthor, EntityTypeSeries, EntityTypePublisher, EntityTypeUser, EntityTypeAPIToken:
+		return true
+	}
+	return false
+}
+
+func (e EntityType) String() string {
+	return string(e)
+}
+
+func (e *EntityType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EntityType", str)
+	}
+	return nil
+}
+
+func (e EntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EntityType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EntityType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GenreMatch string
+
+const (
+	GenreMatchAny GenreMatch = "ANY"
+	GenreMatchAll GenreMatch = "ALL"
+)
+
+var AllGenreMatch = []GenreMatch{
+	GenreMatchAny,
+	GenreMatchAll,
+}
+
+func (e GenreMatch) IsValid() bool {
+	switch e {
+	case GenreMatchAny, GenreMatchAll:
+		return true
+	}
+	return false
+}
+
+func (e GenreMatch) String() string {
+	return string(e)
+}
+
+func (e *GenreMatch) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GenreMatch(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GenreMatch", str)
+	}
+	return nil
+}
+
+func (e GenreMatch) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GenreMatch) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GenreMatch) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Role string
+
+const (
+	RoleViewer Role = "VIEWER"
+	RoleEditor Role = "EDITOR"
+	RoleAdmin  Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleViewer,
+	RoleEditor,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleViewer, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"context"
+
+	"book-nexus/internal/books"
+	"book-nexus/internal/database/sqlc"
+)
+
+// FacetsFunc computes a search's facets only when a client selects them.
+type FacetsFunc func(ctx context.Context) (*books.Facets, error)
+
+type SearchResult struct {
+	Books      []*sqlc.Book
+	Total      int32
+	LoadFacets FacetsFunc
+}
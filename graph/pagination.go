@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"book-nexus/graph/model"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/pagination"
+)
+
+// derefString returns the value of an optional string argument, or "".
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func pageInfo[T any](page *pagination.Page[T], after *string) *model.PageInfo {
+	info := &model.PageInfo{
+		HasNextPage:     page.HasNextPage,
+		HasPreviousPage: derefString(after) != "",
+	}
+	if n := len(page.Cursors); n > 0 {
+		start, end := page.Cursors[0].Encode(), page.Cursors[n-1].Encode()
+		info.StartCursor = &start
+		info.EndCursor = &end
+	}
+	return info
+}
+
+func bookConnection(page *pagination.Page[sqlc.Book], after *string, count model.CountFunc) *model.BookConnection {
+	edges := make([]*model.BookEdge, len(page.Items))
+	for i := range page.Items {
+		edges[i] = &model.BookEdge{Cursor: page.Cursors[i].Encode(), Node: &page.Items[i]}
+	}
+	return &model.BookConnection{Edges: edges, PageInfo: pageInfo(page, after), Count: count}
+}
+
+func authorConnection(page *pagination.Page[sqlc.Author], after *string, count model.CountFunc) *model.AuthorConnection {
+	edges := make([]*model.AuthorEdge, len(page.Items))
+	for i := range page.Items {
+		edges[i] = &model.AuthorEdge{Cursor: page.Cursors[i].Encode(), Node: &page.Items[i]}
+	}
+	return &model.AuthorConnection{Edges: edges, PageInfo: pageInfo(page, after), Count: count}
+}
+
+func publisherConnection(page *pagination.Page[sqlc.Publisher], after *string, count model.CountFunc) *model.PublisherConnection {
+	edges := make([]*model.PublisherEdge, len(page.Items))
+	for i := range page.Items {
+		edges[i] = &model.PublisherEdge{Cursor: page.Cursors[i].Encode(), Node: &page.Items[i]}
+	}
+	return &model.PublisherConnection{Edges: edges, PageInfo: pageInfo(page, after), Count: count}
+}
+
+func seriesConnection(page *pagination.Page[sqlc.Series], after *string, count model.CountFunc) *model.SeriesConnection {
+	edges := make([]*model.SeriesEdge, len(page.Items))
+	for i := range page.Items {
+		edges[i] = &model.SeriesEdge{Cursor: page.Cursors[i].Encode(), Node: &page.Items[i]}
+	}
+	return &model.SeriesConnection{Edges: edges, PageInfo: pageInfo(page, after), Count: count}
+}
+
+func auditEventConnection(page *pagination.Page[sqlc.AuditEvent], after *string) *model.AuditEventConnection {
+	edges := make([]*model.AuditEventEdge, len(page.Items))
+	for i := range page.Items {
+		edges[i] = &model.AuditEventEdge{Cursor: page.Cursors[i].Encode(), Node: &page.Items[i]}
+	}
+	return &model.AuditEventConnection{Edges: edges, PageInfo: pageInfo(page, after)}
+}
@@ -1,6 +1,9 @@
 package graph
 
-import "book-nexus/internal/database"
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/users"
+)
 
 // This file will not be regenerated automatically.
 //
@@ -8,5 +11,6 @@ import "book-nexus/internal/database"
 // here.
 
 type Resolver struct {
-	DB database.Service
+	DB       database.Service
+	Sessions *users.Sessions
 }
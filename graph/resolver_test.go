@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/dbtest"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/loaders"
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+// queryCounter counts the queries run on the connections of a pool.
+type queryCounter struct {
+	n atomic.Int64
+}
+
+func (c *queryCounter) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	c.n.Add(1)
+	return ctx
+}
+
+func (c *queryCounter) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+// testDB serves every read and write from a single pool.
+type testDB struct {
+	pool *pgxpool.Pool
+}
+
+func (d testDB) Health() map[string]string            { return map[string]string{"status": "up"} }
+func (d testDB) Close() error                         { return nil }
+func (d testDB) DB() *pgxpool.Pool                    { return d.pool }
+func (d testDB) Reader(context.Context) *pgxpool.Pool { return d.pool }
+func (d testDB) Queries() *sqlc.Queries               { return sqlc.New(d.pool) }
+func (d testDB) WithTx(ctx context.Context, fn func(tx database.Tx) error) error {
+	return pgx.BeginFunc(ctx, d.pool, fn)
+}
+
+// newCountingClient returns a GraphQL client backed by a fresh database and
+// the counter of the queries it runs.
+func newCountingClient(t *testing.T) (*client.Client, *pgxpool.Pool, *queryCounter) {
+	t.Helper()
+	base := dbtest.New(t)
+	counter := &queryCounter{}
+	cfg := base.Config()
+	cfg.ConnConfig.Tracer = counter
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	db := testDB{pool: pool}
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{DB: db}}))
+	srv.AddTransport(transport.POST{})
+	return client.New(loaders.Middleware(db, srv)), pool, counter
+}
+
+func TestBookListQueryCountIsConstant(t *testing.T) {
+	c, pool, counter := newCountingClient(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+
+	// 40 books spread over 8 authors, publishers and series.
+	for i := 0; i < 8; i++ {
+		author, err := q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Name: fmt.Sprintf("Author %d", i)})
+		if err != nil {
+			t.Fatalf("create author: %v", err)
+		}
+		publisher, err := q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Name: fmt.Sprintf("Publisher %d", i)})
+		if err != nil {
+			t.Fatalf("create publisher: %v", err)
+		}
+		series, err := q.CreateSeries(ctx, sqlc.CreateSeriesParams{Name: fmt.Sprintf("Series %d", i)})
+		if err != nil {
+			t.Fatalf("create series: %v", err)
+		}
+		for j := 0; j < 5; j++ {
+			if _, err := q.CreateBook(ctx, sqlc.CreateBookParams{
+				Title:       fmt.Sprintf("Book %d-%d", i, j),
+				AuthorID:    author.ID,
+				PublisherID: pgtype.UUID{Bytes: publisher.ID, Valid: true},
+				SeriesID:    pgtype.UUID{Bytes: series.ID, Valid: true},
+			}); err != nil {
+				t.Fatalf("create book: %v", err)
+			}
+		}
+	}
+
+	queries := func(limit int) int64 {
+		var resp struct {
+			Books []struct {
+				Title string
+			}
+		}
+		counter.n.Store(0)
+		c.MustPost(`query($limit: Int) {
+			books(limit: $limit) {
+				title
+				author { name }
+				publisher { name }
+				series { name }
+				genres { name }
+				tags { name }
+			}
+		}`, &resp, client.Var("limit", limit))
+		if len(resp.Books) != limit {
+			t.Fatalf("expected %d books, got %d", limit, len(resp.Books))
+		}
+		return counter.n.Load()
+	}
+
+	small, large := queries(2), queries(40)
+	if small != large {
+		t.Fatalf("expected the same number of queries for 2 and 40 books, got %d and %d", small, large)
+	}
+	// The list itself, then one batch each for authors, publishers, series,
+	// genres and tags.
+	if large != 6 {
+		t.Fatalf("expected 6 queries, got %d", large)
+	}
+}
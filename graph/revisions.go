@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/revisions"
+	"book-nexus/internal/users"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// entityRevisions lists the revisions of an entity for its revisions field.
+// Snapshots can hold fields the public schema does not expose, so the list
+// needs the history permission.
+func (r *Resolver) entityRevisions(ctx context.Context, id uuid.UUID) ([]*sqlc.EntityRevision, error) {
+	if err := Require(ctx, users.PermViewHistory); err != nil {
+		return nil, err
+	}
+
+	revs, err := revisions.NewService(r.DB.Reader(ctx)).ListRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*sqlc.EntityRevision, len(revs))
+	for i := range revs {
+		result[i] = &revs[i]
+	}
+	return result, nil
+}
+
+// parseRevert parses the arguments shared by the revert mutations.
+func parseRevert(entity, id, revisionID string) (uuid.UUID, uuid.UUID, error) {
+	entityID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid %s ID: %v", entity, err)
+	}
+	revID, err := uuid.Parse(revisionID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid revision ID: %v", err)
+	}
+	return entityID, revID, nil
+}
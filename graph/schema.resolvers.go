@@ -7,20 +7,88 @@ package graph
 
 import (
 	"book-nexus/graph/model"
+	"book-nexus/internal/audit"
 	"book-nexus/internal/authors"
 	"book-nexus/internal/books"
+	"book-nexus/internal/database"
 	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/genres"
+	"book-nexus/internal/idempotency"
+	"book-nexus/internal/loaders"
+	"book-nexus/internal/pagination"
 	"book-nexus/internal/publishers"
 	"book-nexus/internal/recommendations"
 	"book-nexus/internal/series"
+	"book-nexus/internal/suggest"
+	"book-nexus/internal/tags"
+	"book-nexus/internal/trash"
+	"book-nexus/internal/users"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// ID is the resolver for the id field.
+func (r *apiTokenResolver) ID(ctx context.Context, obj *sqlc.ApiToken) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// ExpiresAt is the resolver for the expiresAt field.
+func (r *apiTokenResolver) ExpiresAt(ctx context.Context, obj *sqlc.ApiToken) (*string, error) {
+	return optionalTime(obj.ExpiresAt), nil
+}
+
+// LastUsedAt is the resolver for the lastUsedAt field.
+func (r *apiTokenResolver) LastUsedAt(ctx context.Context, obj *sqlc.ApiToken) (*string, error) {
+	return optionalTime(obj.LastUsedAt), nil
+}
+
+// RevokedAt is the resolver for the revokedAt field.
+func (r *apiTokenResolver) RevokedAt(ctx context.Context, obj *sqlc.ApiToken) (*string, error) {
+	return optionalTime(obj.RevokedAt), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *apiTokenResolver) CreatedAt(ctx context.Context, obj *sqlc.ApiToken) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// ID is the resolver for the id field.
+func (r *auditEventResolver) ID(ctx context.Context, obj *sqlc.AuditEvent) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// EntityType is the resolver for the entityType field.
+func (r *auditEventResolver) EntityType(ctx context.Context, obj *sqlc.AuditEvent) (model.EntityType, error) {
+	return model.EntityType(obj.EntityType), nil
+}
+
+// EntityID is the resolver for the entityId field.
+func (r *auditEventResolver) EntityID(ctx context.Context, obj *sqlc.AuditEvent) (string, error) {
+	return obj.EntityID.String(), nil
+}
+
+// Action is the resolver for the action field.
+func (r *auditEventResolver) Action(ctx context.Context, obj *sqlc.AuditEvent) (model.AuditAction, error) {
+	return model.AuditAction(obj.Action), nil
+}
+
+// Diff is the resolver for the diff field.
+func (r *auditEventResolver) Diff(ctx context.Context, obj *sqlc.AuditEvent) (string, error) {
+	return string(obj.Diff), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *auditEventResolver) CreatedAt(ctx context.Context, obj *sqlc.AuditEvent) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
 // ID is the resolver for the id field.
 func (r *authorResolver) ID(ctx context.Context, obj *sqlc.Author) (string, error) {
 	return obj.ID.String(), nil
@@ -28,22 +96,12 @@ func (r *authorResolver) ID(ctx context.Context, obj *sqlc.Author) (string, erro
 
 // Books is the resolver for the books field.
 func (r *authorResolver) Books(ctx context.Context, obj *sqlc.Author) ([]*sqlc.Book, error) {
-	svc := books.NewService(r.DB.DB())
-	bookList, err := svc.GetBooksByAuthor(ctx, obj.ID)
-	if err != nil {
-		return nil, err
-	}
-	var result []*sqlc.Book
-	for i := range bookList {
-		result = append(result, &bookList[i])
-	}
-	return result, nil
+	return loaders.For(ctx).BooksByAuthor.Load(ctx, obj.ID)
 }
 
 // BookCount is the resolver for the bookCount field.
 func (r *authorResolver) BookCount(ctx context.Context, obj *sqlc.Author) (int32, error) {
-	svc := authors.NewService(r.DB.DB())
-	count, err := svc.GetAuthorBookCount(ctx, obj.ID)
+	count, err := loaders.For(ctx).BookCountByAuthor.Load(ctx, obj.ID)
 	if err != nil {
 		return 0, err
 	}
@@ -60,6 +118,20 @@ func (r *authorResolver) UpdatedAt(ctx context.Context, obj *sqlc.Author) (strin
 	return obj.UpdatedAt.Format(time.RFC3339), nil
 }
 
+// Revisions is the resolver for the revisions field.
+func (r *authorResolver) Revisions(ctx context.Context, obj *sqlc.Author) ([]*sqlc.EntityRevision, error) {
+	return r.entityRevisions(ctx, obj.ID)
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *authorConnectionResolver) TotalCount(ctx context.Context, obj *model.AuthorConnection) (int32, error) {
+	count, err := obj.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
 // ID is the resolver for the id field.
 func (r *bookResolver) ID(ctx context.Context, obj *sqlc.Book) (string, error) {
 	return obj.ID.String(), nil
@@ -67,8 +139,7 @@ func (r *bookResolver) ID(ctx context.Context, obj *sqlc.Book) (string, error) {
 
 // Author is the resolver for the author field.
 func (r *bookResolver) Author(ctx context.Context, obj *sqlc.Book) (*sqlc.Author, error) {
-	svc := authors.NewService(r.DB.DB())
-	return svc.GetAuthor(ctx, obj.AuthorID)
+	return loaders.For(ctx).AuthorByID.Load(ctx, obj.AuthorID)
 }
 
 // Publisher is the resolver for the publisher field.
@@ -76,8 +147,7 @@ func (r *bookResolver) Publisher(ctx context.Context, obj *sqlc.Book) (*sqlc.Pub
 	if !obj.PublisherID.Valid {
 		return nil, nil
 	}
-	svc := publishers.NewService(r.DB.DB())
-	return svc.GetPublisher(ctx, obj.PublisherID.Bytes)
+	return loaders.For(ctx).PublisherByID.Load(ctx, obj.PublisherID.Bytes)
 }
 
 // PublishedDate is the resolver for the publishedDate field.
@@ -94,8 +164,17 @@ func (r *bookResolver) Series(ctx context.Context, obj *sqlc.Book) (*sqlc.Series
 	if !obj.SeriesID.Valid {
 		return nil, nil
 	}
-	svc := series.NewService(r.DB.DB())
-	return svc.GetSeries(ctx, obj.SeriesID.Bytes)
+	return loaders.For(ctx).SeriesByID.Load(ctx, obj.SeriesID.Bytes)
+}
+
+// Genres is the resolver for the genres field.
+func (r *bookResolver) Genres(ctx context.Context, obj *sqlc.Book) ([]*sqlc.Genre, error) {
+	return loaders.For(ctx).GenresByBook.Load(ctx, obj.ID)
+}
+
+// Tags is the resolver for the tags field.
+func (r *bookResolver) Tags(ctx context.Context, obj *sqlc.Book) ([]*sqlc.Tag, error) {
+	return loaders.For(ctx).TagsByBook.Load(ctx, obj.ID)
 }
 
 // CreatedAt is the resolver for the createdAt field.
@@ -110,7 +189,7 @@ func (r *bookResolver) UpdatedAt(ctx context.Context, obj *sqlc.Book) (string, e
 
 // Recommendations is the resolver for the recommendations field.
 func (r *bookResolver) Recommendations(ctx context.Context, obj *sqlc.Book) ([]*sqlc.Book, error) {
-	svc := recommendations.NewService(r.DB.DB())
+	svc := recommendations.NewService(r.DB.Reader(ctx))
 	bookList, err := svc.GetRecommendations(ctx, obj.ID, 5)
 	if err != nil {
 		return nil, err
@@ -122,9 +201,42 @@ func (r *bookResolver) Recommendations(ctx context.Context, obj *sqlc.Book) ([]*
 	return result, nil
 }
 
+// Revisions is the resolver for the revisions field.
+func (r *bookResolver) Revisions(ctx context.Context, obj *sqlc.Book) ([]*sqlc.EntityRevision, error) {
+	return r.entityRevisions(ctx, obj.ID)
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *bookConnectionResolver) TotalCount(ctx context.Context, obj *model.BookConnection) (int32, error) {
+	count, err := obj.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
+// ID is the resolver for the id field.
+func (r *genreResolver) ID(ctx context.Context, obj *sqlc.Genre) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// Books is the resolver for the books field.
+func (r *genreResolver) Books(ctx context.Context, obj *sqlc.Genre) ([]*sqlc.Book, error) {
+	return loaders.For(ctx).BooksByGenre.Load(ctx, obj.ID)
+}
+
+// BookCount is the resolver for the bookCount field.
+func (r *genreResolver) BookCount(ctx context.Context, obj *sqlc.Genre) (int32, error) {
+	count, err := loaders.For(ctx).BookCountByGenre.Load(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
 // CreateBook is the resolver for the createBook field.
 func (r *mutationResolver) CreateBook(ctx context.Context, input model.NewBook) (*sqlc.Book, error) {
-	if err := RequireAdmin(ctx); err != nil {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
 		return nil, err
 	}
 
@@ -133,22 +245,80 @@ func (r *mutationResolver) CreateBook(ctx context.Context, input model.NewBook)
 		return nil, fmt.Errorf("invalid author ID: %v", err)
 	}
 
-	var publisherID pgtype.UUID
+	var publisherID *uuid.UUID
 	if input.PublisherID != nil {
 		pid, err := uuid.Parse(*input.PublisherID)
 		if err != nil {
 			return nil, fmt.Errorf("invalid publisher ID: %v", err)
 		}
-		publisherID = pgtype.UUID{Bytes: pid, Valid: true}
+		publisherID = &pid
 	}
 
-	var seriesID pgtype.UUID
+	var seriesID *uuid.UUID
 	if input.SeriesID != nil {
 		sid, err := uuid.Parse(*input.SeriesID)
 		if err != nil {
 			return nil, fmt.Errorf("invalid series ID: %v", err)
 		}
-		seriesID = pgtype.UUID{Bytes: sid, Valid: true}
+		seriesID = &sid
+	}
+
+	var publishedDate *time.Time
+	if input.PublishedDate != nil {
+		t, err := time.Parse("2006-01-02", *input.PublishedDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format: %v", err)
+		}
+		publishedDate = &t
+	}
+
+	var pages *int32
+	if input.Pages != nil {
+		p := int32(*input.Pages)
+		pages = &p
+	}
+
+	var seriesPosition *int32
+	if input.SeriesPosition != nil {
+		p := int32(*input.SeriesPosition)
+		seriesPosition = &p
+	}
+
+	var book *sqlc.Book
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		var err error
+		book, err = books.NewService(tx).CreateBook(ctx, books.CreateBookInput{
+			Title:          input.Title,
+			Subtitle:       input.Subtitle,
+			AuthorID:       authorID,
+			PublisherID:    publisherID,
+			PublishedDate:  publishedDate,
+			ISBN10:         input.Isbn10,
+			ISBN13:         input.Isbn13,
+			Pages:          pages,
+			Language:       input.Language,
+			Description:    input.Description,
+			SeriesID:       seriesID,
+			SeriesPosition: seriesPosition,
+			Genres:         input.Genres,
+			Tags:           input.Tags,
+			ImageURL:       input.ImageURL,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeBook, book.ID, audit.ActionCreate, nil, book)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// CreateBookWithRelations is the resolver for the createBookWithRelations field.
+func (r *mutationResolver) CreateBookWithRelations(ctx context.Context, input model.NewBookWithRelations) (*sqlc.Book, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
 	}
 
 	var publishedDate *time.Time
@@ -172,23 +342,69 @@ func (r *mutationResolver) CreateBook(ctx context.Context, input model.NewBook)
 		seriesPosition = &p
 	}
 
-	q := sqlc.New(r.DB.DB())
-	book, err := q.CreateBook(ctx, sqlc.CreateBookParams{
-		Title:          input.Title,
-		Subtitle:       input.Subtitle,
-		AuthorID:       authorID,
-		PublisherID:    publisherID,
-		PublishedDate:  publishedDate,
-		Isbn10:         input.Isbn10,
-		Isbn13:         input.Isbn13,
-		Pages:          pages,
-		Language:       input.Language,
-		Description:    input.Description,
-		SeriesID:       seriesID,
-		SeriesPosition: seriesPosition,
-		Genres:         input.Genres,
-		Tags:           input.Tags,
-		ImageUrl:       input.ImageURL,
+	// Entities created along the way are audited in the same transaction,
+	// so a rolled back attempt leaves no trace.
+	var book sqlc.Book
+	err := r.DB.WithTx(ctx, func(tx database.Tx) error {
+		author, isNew, err := authors.NewService(tx).UpsertAuthorByName(ctx, input.AuthorName)
+		if err != nil {
+			return err
+		}
+		if isNew {
+			if err := record(ctx, tx, audit.TypeAuthor, author.ID, audit.ActionCreate, nil, author); err != nil {
+				return err
+			}
+		}
+
+		var publisherID pgtype.UUID
+		if input.PublisherName != nil {
+			publisher, isNew, err := publishers.NewService(tx).UpsertPublisherByName(ctx, *input.PublisherName)
+			if err != nil {
+				return err
+			}
+			if isNew {
+				if err := record(ctx, tx, audit.TypePublisher, publisher.ID, audit.ActionCreate, nil, publisher); err != nil {
+					return err
+				}
+			}
+			publisherID = pgtype.UUID{Bytes: publisher.ID, Valid: true}
+		}
+
+		var seriesID pgtype.UUID
+		if input.SeriesName != nil {
+			s, isNew, err := series.NewService(tx).UpsertSeriesByName(ctx, *input.SeriesName)
+			if err != nil {
+				return err
+			}
+			if isNew {
+				if err := record(ctx, tx, audit.TypeSeries, s.ID, audit.ActionCreate, nil, s); err != nil {
+					return err
+				}
+			}
+			seriesID = pgtype.UUID{Bytes: s.ID, Valid: true}
+		}
+
+		book, err = sqlc.New(tx).CreateBook(ctx, sqlc.CreateBookParams{
+			Title:          input.Title,
+			Subtitle:       input.Subtitle,
+			AuthorID:       author.ID,
+			PublisherID:    publisherID,
+			PublishedDate:  publishedDate,
+			Isbn10:         input.Isbn10,
+			Isbn13:         input.Isbn13,
+			Pages:          pages,
+			Language:       input.Language,
+			Description:    input.Description,
+			SeriesID:       seriesID,
+			SeriesPosition: seriesPosition,
+			Genres:         input.Genres,
+			Tags:           input.Tags,
+			ImageUrl:       input.ImageURL,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeBook, book.ID, audit.ActionCreate, nil, book)
 	})
 	if err != nil {
 		return nil, err
@@ -196,9 +412,76 @@ func (r *mutationResolver) CreateBook(ctx context.Context, input model.NewBook)
 	return &book, nil
 }
 
+// UpsertBooks is the resolver for the upsertBooks field.
+func (r *mutationResolver) UpsertBooks(ctx context.Context, input []*model.BookUpsertInput, matchOn model.BookMatch, idempotencyKey *string) ([]*model.BookUpsertResult, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
+	}
+
+	// Rows that do not convert are rejected here; the rest go to the
+	// service, which reports on them by their position among those sent.
+	results := make([]*model.BookUpsertResult, len(input))
+	var (
+		rows    []books.UpsertBookInput
+		indexes []int
+	)
+	for i, in := range input {
+		row, err := toUpsertBook(in)
+		if err != nil {
+			results[i] = upsertError(i, err)
+			continue
+		}
+		rows = append(rows, row)
+		indexes = append(indexes, i)
+	}
+
+	err := r.DB.WithTx(ctx, func(tx database.Tx) error {
+		if idempotencyKey != nil {
+			var stored []storedUpsertResult
+			request := upsertBooksRequest{Input: input, MatchOn: matchOn}
+			replay, err := idempotency.Claim(ctx, tx, *idempotencyKey, upsertBooksOperation, request, &stored)
+			if err != nil {
+				return err
+			}
+			if replay {
+				results, err = replayUpsertResults(ctx, sqlc.New(tx), stored)
+				return err
+			}
+		}
+
+		upserted, err := books.NewService(tx).UpsertBooks(ctx, rows, books.Match(matchOn))
+		if err != nil {
+			return err
+		}
+		if err := recordUpserts(ctx, tx, upserted); err != nil {
+			return err
+		}
+		for j, row := range upserted.Rows {
+			i := indexes[j]
+			results[i] = &model.BookUpsertResult{
+				Index:  int32(i),
+				Status: model.BookUpsertStatus(row.Status),
+				Book:   row.Book,
+			}
+			if row.Status == books.UpsertError {
+				results[i].Error = &row.Error
+			}
+		}
+
+		if idempotencyKey != nil {
+			return idempotency.Save(ctx, tx, *idempotencyKey, storeUpsertResults(results))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 // UpdateBook is the resolver for the updateBook field.
 func (r *mutationResolver) UpdateBook(ctx context.Context, id string, input model.UpdateBook) (*sqlc.Book, error) {
-	if err := RequireAdmin(ctx); err != nil {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
 		return nil, err
 	}
 
@@ -212,22 +495,22 @@ func (r *mutationResolver) UpdateBook(ctx context.Context, id string, input mode
 		return nil, fmt.Errorf("invalid author ID: %v", err)
 	}
 
-	var publisherID pgtype.UUID
+	var publisherID *uuid.UUID
 	if input.PublisherID != nil {
 		pid, err := uuid.Parse(*input.PublisherID)
 		if err != nil {
 			return nil, fmt.Errorf("invalid publisher ID: %v", err)
 		}
-		publisherID = pgtype.UUID{Bytes: pid, Valid: true}
+		publisherID = &pid
 	}
 
-	var seriesID pgtype.UUID
+	var seriesID *uuid.UUID
 	if input.SeriesID != nil {
 		sid, err := uuid.Parse(*input.SeriesID)
 		if err != nil {
 			return nil, fmt.Errorf("invalid series ID: %v", err)
 		}
-		seriesID = pgtype.UUID{Bytes: sid, Valid: true}
+		seriesID = &sid
 	}
 
 	var publishedDate *time.Time
@@ -251,34 +534,44 @@ func (r *mutationResolver) UpdateBook(ctx context.Context, id string, input mode
 		seriesPosition = &p
 	}
 
-	q := sqlc.New(r.DB.DB())
-	book, err := q.UpdateBook(ctx, sqlc.UpdateBookParams{
-		ID:             bookID,
-		Title:          input.Title,
-		Subtitle:       input.Subtitle,
-		AuthorID:       authorID,
-		PublisherID:    publisherID,
-		PublishedDate:  publishedDate,
-		Isbn10:         input.Isbn10,
-		Isbn13:         input.Isbn13,
-		Pages:          pages,
-		Language:       input.Language,
-		Description:    input.Description,
-		SeriesID:       seriesID,
-		SeriesPosition: seriesPosition,
-		Genres:         input.Genres,
-		Tags:           input.Tags,
-		ImageUrl:       input.ImageURL,
+	var book *sqlc.Book
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetBookByIDForUpdate(ctx, bookID)
+		if err != nil {
+			return err
+		}
+		book, err = books.NewService(tx).UpdateBook(ctx, books.UpdateBookInput{
+			ID:             bookID,
+			Title:          input.Title,
+			Subtitle:       input.Subtitle,
+			AuthorID:       authorID,
+			PublisherID:    publisherID,
+			PublishedDate:  publishedDate,
+			ISBN10:         input.Isbn10,
+			ISBN13:         input.Isbn13,
+			Pages:          pages,
+			Language:       input.Language,
+			Description:    input.Description,
+			SeriesID:       seriesID,
+			SeriesPosition: seriesPosition,
+			Genres:         input.Genres,
+			Tags:           input.Tags,
+			ImageURL:       input.ImageURL,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeBook, book.ID, audit.ActionUpdate, before, book)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &book, nil
+	return book, nil
 }
 
 // DeleteBook is the resolver for the deleteBook field.
 func (r *mutationResolver) DeleteBook(ctx context.Context, id string) (bool, error) {
-	if err := RequireAdmin(ctx); err != nil {
+	if err := Require(ctx, users.PermDeleteBooks); err != nil {
 		return false, err
 	}
 
@@ -287,24 +580,69 @@ func (r *mutationResolver) DeleteBook(ctx context.Context, id string) (bool, err
 		return false, fmt.Errorf("invalid book ID: %v", err)
 	}
 
-	q := sqlc.New(r.DB.DB())
-	if err := q.DeleteBook(ctx, bookID); err != nil {
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetBookByIDForUpdate(ctx, bookID)
+		if err != nil {
+			return err
+		}
+		if err := books.NewService(tx).DeleteBook(ctx, bookID); err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeBook, bookID, audit.ActionDelete, before, nil)
+	})
+	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
+// RevertBook is the resolver for the revertBook field.
+func (r *mutationResolver) RevertBook(ctx context.Context, id string, revisionID string) (*sqlc.Book, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
+	}
+
+	bookID, revID, err := parseRevert("book", id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var book *sqlc.Book
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetBookByIDForUpdate(ctx, bookID)
+		if err != nil {
+			return err
+		}
+		book, err = books.NewService(tx).RevertBook(ctx, bookID, revID)
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeBook, book.ID, audit.ActionRevert, before, book)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
 // CreateAuthor is the resolver for the createAuthor field.
 func (r *mutationResolver) CreateAuthor(ctx context.Context, input model.NewAuthor) (*sqlc.Author, error) {
-	if err := RequireAdmin(ctx); err != nil {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
 		return nil, err
 	}
 
-	q := sqlc.New(r.DB.DB())
-	author, err := q.CreateAuthor(ctx, sqlc.CreateAuthorParams{
-		Name: input.Name,
-		Slug: input.Slug,
-		Bio:  input.Bio,
+	var author sqlc.Author
+	err := r.DB.WithTx(ctx, func(tx database.Tx) error {
+		var err error
+		author, err = sqlc.New(tx).CreateAuthor(ctx, sqlc.CreateAuthorParams{
+			Name: input.Name,
+			Slug: input.Slug,
+			Bio:  input.Bio,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeAuthor, author.ID, audit.ActionCreate, nil, author)
 	})
 	if err != nil {
 		return nil, err
@@ -314,7 +652,7 @@ func (r *mutationResolver) CreateAuthor(ctx context.Context, input model.NewAuth
 
 // UpdateAuthor is the resolver for the updateAuthor field.
 func (r *mutationResolver) UpdateAuthor(ctx context.Context, id string, input model.UpdateAuthor) (*sqlc.Author, error) {
-	if err := RequireAdmin(ctx); err != nil {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
 		return nil, err
 	}
 
@@ -323,22 +661,32 @@ func (r *mutationResolver) UpdateAuthor(ctx context.Context, id string, input mo
 		return nil, fmt.Errorf("invalid author ID: %v", err)
 	}
 
-	q := sqlc.New(r.DB.DB())
-	author, err := q.UpdateAuthor(ctx, sqlc.UpdateAuthorParams{
-		ID:   authorID,
-		Name: input.Name,
-		Slug: input.Slug,
-		Bio:  input.Bio,
+	var author *sqlc.Author
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetAuthorByIDForUpdate(ctx, authorID)
+		if err != nil {
+			return err
+		}
+		author, err = authors.NewService(tx).UpdateAuthor(ctx, authors.UpdateAuthorInput{
+			ID:   authorID,
+			Name: input.Name,
+			Slug: input.Slug,
+			Bio:  input.Bio,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeAuthor, author.ID, audit.ActionUpdate, before, author)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &author, nil
+	return author, nil
 }
 
 // DeleteAuthor is the resolver for the deleteAuthor field.
-func (r *mutationResolver) DeleteAuthor(ctx context.Context, id string) (bool, error) {
-	if err := RequireAdmin(ctx); err != nil {
+func (r *mutationResolver) DeleteAuthor(ctx context.Context, id string, strategy *model.DeleteStrategy) (bool, error) {
+	if err := Require(ctx, users.PermDeleteEntities); err != nil {
 		return false, err
 	}
 
@@ -347,34 +695,91 @@ func (r *mutationResolver) DeleteAuthor(ctx context.Context, id string) (bool, e
 		return false, fmt.Errorf("invalid author ID: %v", err)
 	}
 
-	q := sqlc.New(r.DB.DB())
-	if err := q.DeleteAuthor(ctx, authorID); err != nil {
+	deleteStrategy, err := toDeleteStrategy(strategy)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetAuthorByIDForUpdate(ctx, authorID)
+		if err != nil {
+			return err
+		}
+		trashed, moved, err := authors.NewService(tx).DeleteAuthor(ctx, authorID, deleteStrategy)
+		if err != nil {
+			return deleteError(err)
+		}
+		if err := record(ctx, tx, audit.TypeAuthor, authorID, audit.ActionDelete, before, nil); err != nil {
+			return err
+		}
+		if err := recordMovedBooks(ctx, tx, bookAuthor, authorID, moved); err != nil {
+			return err
+		}
+		return recordTrashedBooks(ctx, tx, trashed)
+	})
+	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
+// RevertAuthor is the resolver for the revertAuthor field.
+func (r *mutationResolver) RevertAuthor(ctx context.Context, id string, revisionID string) (*sqlc.Author, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
+	}
+
+	authorID, revID, err := parseRevert("author", id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var author *sqlc.Author
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetAuthorByIDForUpdate(ctx, authorID)
+		if err != nil {
+			return err
+		}
+		author, err = authors.NewService(tx).RevertAuthor(ctx, authorID, revID)
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeAuthor, author.ID, audit.ActionRevert, before, author)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return author, nil
+}
+
 // CreateSeries is the resolver for the createSeries field.
 func (r *mutationResolver) CreateSeries(ctx context.Context, input model.NewSeries) (*sqlc.Series, error) {
-	if err := RequireAdmin(ctx); err != nil {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
 		return nil, err
 	}
 
-	q := sqlc.New(r.DB.DB())
-	series, err := q.CreateSeries(ctx, sqlc.CreateSeriesParams{
-		Name:        input.Name,
-		Slug:        input.Slug,
-		Description: input.Description,
+	var s sqlc.Series
+	err := r.DB.WithTx(ctx, func(tx database.Tx) error {
+		var err error
+		s, err = sqlc.New(tx).CreateSeries(ctx, sqlc.CreateSeriesParams{
+			Name:        input.Name,
+			Slug:        input.Slug,
+			Description: input.Description,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeSeries, s.ID, audit.ActionCreate, nil, s)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &series, nil
+	return &s, nil
 }
 
 // UpdateSeries is the resolver for the updateSeries field.
 func (r *mutationResolver) UpdateSeries(ctx context.Context, id string, input model.UpdateSeries) (*sqlc.Series, error) {
-	if err := RequireAdmin(ctx); err != nil {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
 		return nil, err
 	}
 
@@ -383,22 +788,32 @@ func (r *mutationResolver) UpdateSeries(ctx context.Context, id string, input mo
 		return nil, fmt.Errorf("invalid series ID: %v", err)
 	}
 
-	q := sqlc.New(r.DB.DB())
-	series, err := q.UpdateSeries(ctx, sqlc.UpdateSeriesParams{
-		ID:          seriesID,
-		Name:        input.Name,
-		Slug:        input.Slug,
-		Description: input.Description,
+	var s *sqlc.Series
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetSeriesByIDForUpdate(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		s, err = series.NewService(tx).UpdateSeries(ctx, series.UpdateSeriesInput{
+			ID:          seriesID,
+			Name:        input.Name,
+			Slug:        input.Slug,
+			Description: input.Description,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeSeries, s.ID, audit.ActionUpdate, before, s)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &series, nil
+	return s, nil
 }
 
 // DeleteSeries is the resolver for the deleteSeries field.
-func (r *mutationResolver) DeleteSeries(ctx context.Context, id string) (bool, error) {
-	if err := RequireAdmin(ctx); err != nil {
+func (r *mutationResolver) DeleteSeries(ctx context.Context, id string, strategy *model.DeleteStrategy) (bool, error) {
+	if err := Require(ctx, users.PermDeleteEntities); err != nil {
 		return false, err
 	}
 
@@ -407,55 +822,460 @@ func (r *mutationResolver) DeleteSeries(ctx context.Context, id string) (bool, e
 		return false, fmt.Errorf("invalid series ID: %v", err)
 	}
 
-	q := sqlc.New(r.DB.DB())
-	if err := q.DeleteSeries(ctx, seriesID); err != nil {
+	deleteStrategy, err := toDeleteStrategy(strategy)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetSeriesByIDForUpdate(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		trashed, moved, err := series.NewService(tx).DeleteSeries(ctx, seriesID, deleteStrategy)
+		if err != nil {
+			return deleteError(err)
+		}
+		if err := record(ctx, tx, audit.TypeSeries, seriesID, audit.ActionDelete, before, nil); err != nil {
+			return err
+		}
+		if err := recordMovedBooks(ctx, tx, bookSeries, seriesID, moved); err != nil {
+			return err
+		}
+		return recordTrashedBooks(ctx, tx, trashed)
+	})
+	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-// ID is the resolver for the id field.
-func (r *publisherResolver) ID(ctx context.Context, obj *sqlc.Publisher) (string, error) {
-	return obj.ID.String(), nil
-}
+// RevertSeries is the resolver for the revertSeries field.
+func (r *mutationResolver) RevertSeries(ctx context.Context, id string, revisionID string) (*sqlc.Series, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
+	}
 
-// Books is the resolver for the books field.
-func (r *publisherResolver) Books(ctx context.Context, obj *sqlc.Publisher) ([]*sqlc.Book, error) {
-	svc := books.NewService(r.DB.DB())
-	bookList, err := svc.GetBooksByPublisher(ctx, obj.ID)
+	seriesID, revID, err := parseRevert("series", id, revisionID)
 	if err != nil {
 		return nil, err
 	}
-	var result []*sqlc.Book
-	for i := range bookList {
-		result = append(result, &bookList[i])
-	}
-	return result, nil
-}
 
-// BookCount is the resolver for the bookCount field.
-func (r *publisherResolver) BookCount(ctx context.Context, obj *sqlc.Publisher) (int32, error) {
-	svc := publishers.NewService(r.DB.DB())
-	count, err := svc.GetPublisherBookCount(ctx, obj.ID)
+	var s *sqlc.Series
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetSeriesByIDForUpdate(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		s, err = series.NewService(tx).RevertSeries(ctx, seriesID, revID)
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeSeries, s.ID, audit.ActionRevert, before, s)
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return int32(count), nil
+	return s, nil
 }
 
-// CreatedAt is the resolver for the createdAt field.
-func (r *publisherResolver) CreatedAt(ctx context.Context, obj *sqlc.Publisher) (string, error) {
-	return obj.CreatedAt.Format(time.RFC3339), nil
-}
+// CreatePublisher is the resolver for the createPublisher field.
+func (r *mutationResolver) CreatePublisher(ctx context.Context, input model.NewPublisher) (*sqlc.Publisher, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
+	}
 
-// UpdatedAt is the resolver for the updatedAt field.
-func (r *publisherResolver) UpdatedAt(ctx context.Context, obj *sqlc.Publisher) (string, error) {
-	return obj.UpdatedAt.Format(time.RFC3339), nil
+	var publisher *sqlc.Publisher
+	err := r.DB.WithTx(ctx, func(tx database.Tx) error {
+		var err error
+		publisher, err = publishers.NewService(tx).CreatePublisher(ctx, publishers.CreatePublisherInput{
+			Name:    input.Name,
+			Slug:    input.Slug,
+			Website: input.Website,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypePublisher, publisher.ID, audit.ActionCreate, nil, publisher)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return publisher, nil
 }
 
-// Books is the resolver for the books field.
-func (r *queryResolver) Books(ctx context.Context, limit *int32, offset *int32) ([]*sqlc.Book, error) {
-	svc := books.NewService(r.DB.DB())
+// UpdatePublisher is the resolver for the updatePublisher field.
+func (r *mutationResolver) UpdatePublisher(ctx context.Context, id string, input model.UpdatePublisher) (*sqlc.Publisher, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
+	}
+
+	publisherID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publisher ID: %v", err)
+	}
+
+	var publisher *sqlc.Publisher
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetPublisherByIDForUpdate(ctx, publisherID)
+		if err != nil {
+			return err
+		}
+		publisher, err = publishers.NewService(tx).UpdatePublisher(ctx, publishers.UpdatePublisherInput{
+			ID:      publisherID,
+			Name:    input.Name,
+			Slug:    input.Slug,
+			Website: input.Website,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypePublisher, publisher.ID, audit.ActionUpdate, before, publisher)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return publisher, nil
+}
+
+// DeletePublisher is the resolver for the deletePublisher field.
+func (r *mutationResolver) DeletePublisher(ctx context.Context, id string, strategy *model.DeleteStrategy) (bool, error) {
+	if err := Require(ctx, users.PermDeleteEntities); err != nil {
+		return false, err
+	}
+
+	publisherID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid publisher ID: %v", err)
+	}
+
+	deleteStrategy, err := toDeleteStrategy(strategy)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetPublisherByIDForUpdate(ctx, publisherID)
+		if err != nil {
+			return err
+		}
+		moved, err := publishers.NewService(tx).DeletePublisher(ctx, publisherID, deleteStrategy)
+		if err != nil {
+			return deleteError(err)
+		}
+		if err := record(ctx, tx, audit.TypePublisher, publisherID, audit.ActionDelete, before, nil); err != nil {
+			return err
+		}
+		return recordMovedBooks(ctx, tx, bookPublisher, publisherID, moved)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RevertPublisher is the resolver for the revertPublisher field.
+func (r *mutationResolver) RevertPublisher(ctx context.Context, id string, revisionID string) (*sqlc.Publisher, error) {
+	if err := Require(ctx, users.PermEditCatalog); err != nil {
+		return nil, err
+	}
+
+	publisherID, revID, err := parseRevert("publisher", id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var publisher *sqlc.Publisher
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetPublisherByIDForUpdate(ctx, publisherID)
+		if err != nil {
+			return err
+		}
+		publisher, err = publishers.NewService(tx).RevertPublisher(ctx, publisherID, revID)
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypePublisher, publisher.ID, audit.ActionRevert, before, publisher)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return publisher, nil
+}
+
+// Restore is the resolver for the restore field.
+func (r *mutationResolver) Restore(ctx context.Context, id string) (bool, error) {
+	if err := Require(ctx, users.PermManageTrash); err != nil {
+		return false, err
+	}
+
+	entityID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid ID: %v", err)
+	}
+
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		entityType, restored, err := trash.NewService(tx).Restore(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		after, err := auditSnapshot(ctx, sqlc.New(tx), entityType, entityID)
+		if err != nil {
+			return err
+		}
+		if err := record(ctx, tx, entityType, entityID, audit.ActionRestore, nil, after); err != nil {
+			return err
+		}
+		return recordRestoredBooks(ctx, tx, restored)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Purge is the resolver for the purge field.
+func (r *mutationResolver) Purge(ctx context.Context, id string) (bool, error) {
+	if err := Require(ctx, users.PermManageTrash); err != nil {
+		return false, err
+	}
+
+	entityID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid ID: %v", err)
+	}
+
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		entityType, err := trash.NewService(tx).Purge(ctx, entityID)
+		if err != nil {
+			return deleteError(err)
+		}
+		return record(ctx, tx, entityType, entityID, audit.ActionPurge, nil, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, email string, password string) (*model.Session, error) {
+	session, err := r.Sessions.Login(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	return toSession(session), nil
+}
+
+// RefreshSession is the resolver for the refreshSession field.
+func (r *mutationResolver) RefreshSession(ctx context.Context, refreshToken string) (*model.Session, error) {
+	session, err := r.Sessions.Refresh(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return toSession(session), nil
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context, refreshToken string) (bool, error) {
+	if err := r.Sessions.Logout(ctx, refreshToken); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser) (*sqlc.User, error) {
+	if err := Require(ctx, users.PermManageUsers); err != nil {
+		return nil, err
+	}
+
+	role, err := users.ParseRole(input.Role.String())
+	if err != nil {
+		return nil, err
+	}
+	var user *sqlc.User
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		var err error
+		user, err = users.NewService(tx).CreateUser(ctx, users.CreateUserInput{
+			Email:    input.Email,
+			Name:     input.Name,
+			Password: input.Password,
+			Role:     role,
+		})
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeUser, user.ID, audit.ActionCreate, nil, auditUser(*user))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetUserRole is the resolver for the setUserRole field.
+func (r *mutationResolver) SetUserRole(ctx context.Context, id string, role model.Role) (*sqlc.User, error) {
+	if err := Require(ctx, users.PermManageUsers); err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %v", err)
+	}
+	newRole, err := users.ParseRole(role.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var user *sqlc.User
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		before, err := sqlc.New(tx).GetUserByIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		user, err = users.NewService(tx).SetRole(ctx, userID, newRole)
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeUser, userID, audit.ActionUpdate, auditUser(before), auditUser(*user))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateAPIToken is the resolver for the createApiToken field.
+func (r *mutationResolver) CreateAPIToken(ctx context.Context, name string, userID *string, expiresAt *string) (*model.NewAPIToken, error) {
+	p, ok := users.PrincipalFrom(ctx)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	ownerID := p.UserID
+	if userID != nil {
+		id, err := uuid.Parse(*userID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID: %v", err)
+		}
+		ownerID = id
+	}
+	if ownerID == uuid.Nil {
+		return nil, fmt.Errorf("userId is required when signed in with the admin password")
+	}
+	if err := requireSelfOr(ctx, ownerID, users.PermManageUsers); err != nil {
+		return nil, err
+	}
+
+	var expires *time.Time
+	if expiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiresAt: %v", err)
+		}
+		expires = &t
+	}
+
+	var (
+		token    string
+		apiToken *sqlc.ApiToken
+	)
+	err := r.DB.WithTx(ctx, func(tx database.Tx) error {
+		var err error
+		token, apiToken, err = users.NewService(tx).CreateToken(ctx, ownerID, name, expires)
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeAPIToken, apiToken.ID, audit.ActionCreate, nil, auditAPIToken(*apiToken))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.NewAPIToken{Token: token, APIToken: apiToken}, nil
+}
+
+// RevokeAPIToken is the resolver for the revokeApiToken field.
+func (r *mutationResolver) RevokeAPIToken(ctx context.Context, id string) (bool, error) {
+	tokenID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid token ID: %v", err)
+	}
+
+	err = r.DB.WithTx(ctx, func(tx database.Tx) error {
+		q := sqlc.New(tx)
+		before, err := q.GetApiTokenByIDForUpdate(ctx, tokenID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return users.ErrTokenNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := requireSelfOr(ctx, before.UserID, users.PermManageUsers); err != nil {
+			return err
+		}
+		if err := users.NewService(tx).RevokeToken(ctx, tokenID); err != nil {
+			return err
+		}
+		after, err := q.GetApiTokenByID(ctx, tokenID)
+		if err != nil {
+			return err
+		}
+		return record(ctx, tx, audit.TypeAPIToken, tokenID, audit.ActionUpdate, auditAPIToken(before), auditAPIToken(after))
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ID is the resolver for the id field.
+func (r *publisherResolver) ID(ctx context.Context, obj *sqlc.Publisher) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// Books is the resolver for the books field.
+func (r *publisherResolver) Books(ctx context.Context, obj *sqlc.Publisher) ([]*sqlc.Book, error) {
+	return loaders.For(ctx).BooksByPublisher.Load(ctx, obj.ID)
+}
+
+// BookCount is the resolver for the bookCount field.
+func (r *publisherResolver) BookCount(ctx context.Context, obj *sqlc.Publisher) (int32, error) {
+	count, err := loaders.For(ctx).BookCountByPublisher.Load(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *publisherResolver) CreatedAt(ctx context.Context, obj *sqlc.Publisher) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *publisherResolver) UpdatedAt(ctx context.Context, obj *sqlc.Publisher) (string, error) {
+	return obj.UpdatedAt.Format(time.RFC3339), nil
+}
+
+// Revisions is the resolver for the revisions field.
+func (r *publisherResolver) Revisions(ctx context.Context, obj *sqlc.Publisher) ([]*sqlc.EntityRevision, error) {
+	return r.entityRevisions(ctx, obj.ID)
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *publisherConnectionResolver) TotalCount(ctx context.Context, obj *model.PublisherConnection) (int32, error) {
+	count, err := obj.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
+// Books is the resolver for the books field.
+func (r *queryResolver) Books(ctx context.Context, limit *int32, offset *int32) ([]*sqlc.Book, error) {
+	svc := books.NewService(r.DB.Reader(ctx))
 	l := int32(100)
 	o := int32(0)
 	if limit != nil {
@@ -481,57 +1301,19 @@ func (r *queryResolver) Book(ctx context.Context, id string) (*sqlc.Book, error)
 	if err != nil {
 		return nil, fmt.Errorf("invalid UUID: %v", err)
 	}
-	svc := books.NewService(r.DB.DB())
+	svc := books.NewService(r.DB.Reader(ctx))
 	return svc.GetBook(ctx, uid)
 }
 
 // SearchBooks is the resolver for the searchBooks field.
 func (r *queryResolver) SearchBooks(ctx context.Context, input model.SearchBooksInput) (*model.SearchResult, error) {
-	svc := books.NewService(r.DB.DB())
-
-	var query, authorID, publisherID, seriesID, authorName, genre, sortBy string
-	if input.Query != nil {
-		query = *input.Query
-	}
-	if input.AuthorID != nil {
-		authorID = *input.AuthorID
-	}
-	if input.PublisherID != nil {
-		publisherID = *input.PublisherID
-	}
-	if input.SeriesID != nil {
-		seriesID = *input.SeriesID
-	}
-	if input.AuthorName != nil {
-		authorName = *input.AuthorName
-	}
-	if input.Genre != nil {
-		genre = *input.Genre
-	}
-	if input.SortBy != nil {
-		sortBy = *input.SortBy
-	}
+	svc := books.NewService(r.DB.Reader(ctx))
 
-	limit := int32(20)
-	offset := int32(0)
-	if input.Limit != nil {
-		limit = *input.Limit
-	}
-	if input.Offset != nil {
-		offset = *input.Offset
+	searchInput, err := toSearchInput(input)
+	if err != nil {
+		return nil, err
 	}
-
-	result, err := svc.SearchBooks(ctx, books.SearchInput{
-		Query:       query,
-		AuthorID:    authorID,
-		PublisherID: publisherID,
-		SeriesID:    seriesID,
-		AuthorName:  authorName,
-		Genre:       genre,
-		SortBy:      sortBy,
-		Limit:       limit,
-		Offset:      offset,
-	})
+	result, err := svc.SearchBooks(ctx, searchInput)
 	if err != nil {
 		return nil, fmt.Errorf("search books: %v", err)
 	}
@@ -544,28 +1326,87 @@ func (r *queryResolver) SearchBooks(ctx context.Context, input model.SearchBooks
 	return &model.SearchResult{
 		Books: bookPtrs,
 		Total: int32(result.Total),
+		LoadFacets: func(ctx context.Context) (*books.Facets, error) {
+			return svc.SearchFacets(ctx, searchInput)
+		},
 	}, nil
 }
 
+// BooksConnection is the resolver for the booksConnection field.
+func (r *queryResolver) BooksConnection(ctx context.Context, first *int32, after *string, sortBy *string) (*model.BookConnection, error) {
+	svc := books.NewService(r.DB.Reader(ctx))
+	page, err := svc.ListBooksPage(ctx, derefString(sortBy), pagination.PageSize(first), derefString(after))
+	if err != nil {
+		return nil, err
+	}
+	return bookConnection(page, after, svc.CountBooks), nil
+}
+
+// SearchBooksConnection is the resolver for the searchBooksConnection field.
+func (r *queryResolver) SearchBooksConnection(ctx context.Context, input model.SearchBooksInput, first *int32, after *string) (*model.BookConnection, error) {
+	svc := books.NewService(r.DB.Reader(ctx))
+	searchInput, err := toSearchInput(input)
+	if err != nil {
+		return nil, err
+	}
+	page, err := svc.SearchBooksPage(ctx, searchInput, pagination.PageSize(first), derefString(after))
+	if err != nil {
+		return nil, fmt.Errorf("search books: %v", err)
+	}
+	return bookConnection(page, after, func(ctx context.Context) (int64, error) {
+		return svc.CountSearchResults(ctx, searchInput)
+	}), nil
+}
+
+// Suggest is the resolver for the suggest field.
+func (r *queryResolver) Suggest(ctx context.Context, prefix string, types []model.EntityType, limit *int32) ([]*model.Suggestion, error) {
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = t.String()
+	}
+	var l int32
+	if limit != nil {
+		l = *limit
+	}
+
+	svc := suggest.NewService(r.DB.Reader(ctx))
+	rows, err := svc.Suggest(ctx, prefix, typeNames, l)
+	if err != nil {
+		return nil, fmt.Errorf("suggest: %v", err)
+	}
+
+	result := make([]*model.Suggestion, len(rows))
+	for i, row := range rows {
+		result[i] = &model.Suggestion{
+			Type:  model.EntityType(row.EntityType),
+			ID:    row.ID.String(),
+			Name:  row.Name,
+			Slug:  row.Slug,
+			Score: row.Score,
+		}
+	}
+	return result, nil
+}
+
 // Author is the resolver for the author field.
 func (r *queryResolver) Author(ctx context.Context, id string) (*sqlc.Author, error) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid UUID: %v", err)
 	}
-	svc := authors.NewService(r.DB.DB())
+	svc := authors.NewService(r.DB.Reader(ctx))
 	return svc.GetAuthor(ctx, uid)
 }
 
 // AuthorBySlug is the resolver for the authorBySlug field.
 func (r *queryResolver) AuthorBySlug(ctx context.Context, slug string) (*sqlc.Author, error) {
-	svc := authors.NewService(r.DB.DB())
+	svc := authors.NewService(r.DB.Reader(ctx))
 	return svc.GetAuthorBySlug(ctx, slug)
 }
 
 // Authors is the resolver for the authors field.
 func (r *queryResolver) Authors(ctx context.Context, search *string, limit *int32, offset *int32) ([]*sqlc.Author, error) {
-	svc := authors.NewService(r.DB.DB())
+	svc := authors.NewService(r.DB.Reader(ctx))
 	l := int32(100)
 	o := int32(0)
 	if limit != nil {
@@ -593,25 +1434,40 @@ func (r *queryResolver) Authors(ctx context.Context, search *string, limit *int3
 	return result, nil
 }
 
+// AuthorsConnection is the resolver for the authorsConnection field.
+func (r *queryResolver) AuthorsConnection(ctx context.Context, search *string, first *int32, after *string) (*model.AuthorConnection, error) {
+	svc := authors.NewService(r.DB.Reader(ctx))
+	page, err := svc.ListAuthorsPage(ctx, derefString(search), pagination.PageSize(first), derefString(after))
+	if err != nil {
+		return nil, err
+	}
+	return authorConnection(page, after, func(ctx context.Context) (int64, error) {
+		if derefString(search) != "" {
+			return svc.CountAuthorsSearch(ctx, *search)
+		}
+		return svc.CountAuthors(ctx)
+	}), nil
+}
+
 // Publisher is the resolver for the publisher field.
 func (r *queryResolver) Publisher(ctx context.Context, id string) (*sqlc.Publisher, error) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid UUID: %v", err)
 	}
-	svc := publishers.NewService(r.DB.DB())
+	svc := publishers.NewService(r.DB.Reader(ctx))
 	return svc.GetPublisher(ctx, uid)
 }
 
 // PublisherBySlug is the resolver for the publisherBySlug field.
 func (r *queryResolver) PublisherBySlug(ctx context.Context, slug string) (*sqlc.Publisher, error) {
-	svc := publishers.NewService(r.DB.DB())
+	svc := publishers.NewService(r.DB.Reader(ctx))
 	return svc.GetPublisherBySlug(ctx, slug)
 }
 
 // Publishers is the resolver for the publishers field.
 func (r *queryResolver) Publishers(ctx context.Context, search *string, limit *int32, offset *int32) ([]*sqlc.Publisher, error) {
-	svc := publishers.NewService(r.DB.DB())
+	svc := publishers.NewService(r.DB.Reader(ctx))
 	l := int32(100)
 	o := int32(0)
 	if limit != nil {
@@ -639,25 +1495,40 @@ func (r *queryResolver) Publishers(ctx context.Context, search *string, limit *i
 	return result, nil
 }
 
+// PublishersConnection is the resolver for the publishersConnection field.
+func (r *queryResolver) PublishersConnection(ctx context.Context, search *string, first *int32, after *string) (*model.PublisherConnection, error) {
+	svc := publishers.NewService(r.DB.Reader(ctx))
+	page, err := svc.ListPublishersPage(ctx, derefString(search), pagination.PageSize(first), derefString(after))
+	if err != nil {
+		return nil, err
+	}
+	return publisherConnection(page, after, func(ctx context.Context) (int64, error) {
+		if derefString(search) != "" {
+			return svc.CountPublishersSearch(ctx, *search)
+		}
+		return svc.CountPublishers(ctx)
+	}), nil
+}
+
 // Series is the resolver for the series field.
 func (r *queryResolver) Series(ctx context.Context, id string) (*sqlc.Series, error) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid UUID: %v", err)
 	}
-	svc := series.NewService(r.DB.DB())
+	svc := series.NewService(r.DB.Reader(ctx))
 	return svc.GetSeries(ctx, uid)
 }
 
 // SeriesBySlug is the resolver for the seriesBySlug field.
 func (r *queryResolver) SeriesBySlug(ctx context.Context, slug string) (*sqlc.Series, error) {
-	svc := series.NewService(r.DB.DB())
+	svc := series.NewService(r.DB.Reader(ctx))
 	return svc.GetSeriesBySlug(ctx, slug)
 }
 
 // SeriesList is the resolver for the seriesList field.
 func (r *queryResolver) SeriesList(ctx context.Context, search *string, limit *int32, offset *int32) ([]*sqlc.Series, error) {
-	svc := series.NewService(r.DB.DB())
+	svc := series.NewService(r.DB.Reader(ctx))
 	l := int32(100)
 	o := int32(0)
 	if limit != nil {
@@ -685,29 +1556,242 @@ func (r *queryResolver) SeriesList(ctx context.Context, search *string, limit *i
 	return result, nil
 }
 
-// ID is the resolver for the id field.
-func (r *seriesResolver) ID(ctx context.Context, obj *sqlc.Series) (string, error) {
-	return obj.ID.String(), nil
+// SeriesConnection is the resolver for the seriesConnection field.
+func (r *queryResolver) SeriesConnection(ctx context.Context, search *string, first *int32, after *string) (*model.SeriesConnection, error) {
+	svc := series.NewService(r.DB.Reader(ctx))
+	page, err := svc.ListSeriesPage(ctx, derefString(search), pagination.PageSize(first), derefString(after))
+	if err != nil {
+		return nil, err
+	}
+	return seriesConnection(page, after, func(ctx context.Context) (int64, error) {
+		if derefString(search) != "" {
+			return svc.CountSeriesSearch(ctx, *search)
+		}
+		return svc.CountSeries(ctx)
+	}), nil
 }
 
-// Books is the resolver for the books field.
-func (r *seriesResolver) Books(ctx context.Context, obj *sqlc.Series) ([]*sqlc.Book, error) {
-	svc := books.NewService(r.DB.DB())
-	bookList, err := svc.GetBooksBySeries(ctx, obj.ID)
+// Genre is the resolver for the genre field.
+func (r *queryResolver) Genre(ctx context.Context, id string) (*sqlc.Genre, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID: %v", err)
+	}
+	svc := genres.NewService(r.DB.Reader(ctx))
+	return svc.GetGenre(ctx, uid)
+}
+
+// GenreBySlug is the resolver for the genreBySlug field.
+func (r *queryResolver) GenreBySlug(ctx context.Context, slug string) (*sqlc.Genre, error) {
+	svc := genres.NewService(r.DB.Reader(ctx))
+	return svc.GetGenreBySlug(ctx, slug)
+}
+
+// Genres is the resolver for the genres field.
+func (r *queryResolver) Genres(ctx context.Context, search *string, limit *int32, offset *int32) ([]*sqlc.Genre, error) {
+	svc := genres.NewService(r.DB.Reader(ctx))
+	l := int32(100)
+	o := int32(0)
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	var genreList []sqlc.Genre
+	var err error
+	if search != nil && *search != "" {
+		genreList, err = svc.SearchGenres(ctx, *search, l, o)
+	} else {
+		genreList, err = svc.ListGenres(ctx, l, o)
+	}
 	if err != nil {
 		return nil, err
 	}
-	var result []*sqlc.Book
-	for i := range bookList {
-		result = append(result, &bookList[i])
+
+	var result []*sqlc.Genre
+	for i := range genreList {
+		result = append(result, &genreList[i])
 	}
 	return result, nil
 }
 
+// Tag is the resolver for the tag field.
+func (r *queryResolver) Tag(ctx context.Context, id string) (*sqlc.Tag, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID: %v", err)
+	}
+	svc := tags.NewService(r.DB.Reader(ctx))
+	return svc.GetTag(ctx, uid)
+}
+
+// TagBySlug is the resolver for the tagBySlug field.
+func (r *queryResolver) TagBySlug(ctx context.Context, slug string) (*sqlc.Tag, error) {
+	svc := tags.NewService(r.DB.Reader(ctx))
+	return svc.GetTagBySlug(ctx, slug)
+}
+
+// Tags is the resolver for the tags field.
+func (r *queryResolver) Tags(ctx context.Context, search *string, limit *int32, offset *int32) ([]*sqlc.Tag, error) {
+	svc := tags.NewService(r.DB.Reader(ctx))
+	l := int32(100)
+	o := int32(0)
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	var tagList []sqlc.Tag
+	var err error
+	if search != nil && *search != "" {
+		tagList, err = svc.SearchTags(ctx, *search, l, o)
+	} else {
+		tagList, err = svc.ListTags(ctx, l, o)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*sqlc.Tag
+	for i := range tagList {
+		result = append(result, &tagList[i])
+	}
+	return result, nil
+}
+
+// Trash is the resolver for the trash field.
+func (r *queryResolver) Trash(ctx context.Context, typeArg *model.EntityType, limit *int32) ([]*model.TrashItem, error) {
+	if err := Require(ctx, users.PermManageTrash); err != nil {
+		return nil, err
+	}
+
+	var types []string
+	if typeArg != nil {
+		types = []string{typeArg.String()}
+	}
+	var l int32
+	if limit != nil {
+		l = *limit
+	}
+
+	svc := trash.NewService(r.DB.Reader(ctx))
+	items, err := svc.List(ctx, types, l)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.TrashItem, len(items))
+	for i, item := range items {
+		result[i] = &model.TrashItem{
+			Type:      model.EntityType(item.Type),
+			ID:        item.ID.String(),
+			Name:      item.Name,
+			DeletedAt: item.DeletedAt.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*sqlc.User, error) {
+	p, ok := users.PrincipalFrom(ctx)
+	if !ok || p.UserID == uuid.Nil {
+		return nil, nil
+	}
+	return users.NewService(r.DB.Reader(ctx)).GetUser(ctx, p.UserID)
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context) ([]*sqlc.User, error) {
+	if err := Require(ctx, users.PermManageUsers); err != nil {
+		return nil, err
+	}
+
+	list, err := users.NewService(r.DB.Reader(ctx)).ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*sqlc.User, len(list))
+	for i := range list {
+		result[i] = &list[i]
+	}
+	return result, nil
+}
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, entityID *string, entityType *model.EntityType, since *string, first *int32, after *string) (*model.AuditEventConnection, error) {
+	if err := Require(ctx, users.PermViewAudit); err != nil {
+		return nil, err
+	}
+
+	filter := audit.Filter{}
+	if entityID != nil {
+		id, err := uuid.Parse(*entityID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entityId: %v", err)
+		}
+		filter.EntityID = id
+	}
+	if entityType != nil {
+		filter.EntityType = entityType.String()
+	}
+	if since != nil {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since: %v", err)
+		}
+		filter.Since = t
+	}
+
+	svc := audit.NewService(r.DB.Reader(ctx))
+	page, err := svc.ListEventsPage(ctx, filter, pagination.PageSize(first), derefString(after))
+	if err != nil {
+		return nil, err
+	}
+	return auditEventConnection(page, after), nil
+}
+
+// ID is the resolver for the id field.
+func (r *revisionResolver) ID(ctx context.Context, obj *sqlc.EntityRevision) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// Snapshot is the resolver for the snapshot field.
+func (r *revisionResolver) Snapshot(ctx context.Context, obj *sqlc.EntityRevision) (string, error) {
+	return string(obj.Snapshot), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *revisionResolver) CreatedAt(ctx context.Context, obj *sqlc.EntityRevision) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// Facets is the resolver for the facets field.
+func (r *searchResultResolver) Facets(ctx context.Context, obj *model.SearchResult) (*model.SearchFacets, error) {
+	facets, err := obj.LoadFacets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("search facets: %v", err)
+	}
+	return toSearchFacets(facets), nil
+}
+
+// ID is the resolver for the id field.
+func (r *seriesResolver) ID(ctx context.Context, obj *sqlc.Series) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// Books is the resolver for the books field.
+func (r *seriesResolver) Books(ctx context.Context, obj *sqlc.Series) ([]*sqlc.Book, error) {
+	return loaders.For(ctx).BooksBySeries.Load(ctx, obj.ID)
+}
+
 // BookCount is the resolver for the bookCount field.
 func (r *seriesResolver) BookCount(ctx context.Context, obj *sqlc.Series) (int32, error) {
-	svc := series.NewService(r.DB.DB())
-	count, err := svc.GetSeriesBookCount(ctx, obj.ID)
+	count, err := loaders.For(ctx).BookCountBySeries.Load(ctx, obj.ID)
 	if err != nil {
 		return 0, err
 	}
@@ -724,27 +1808,143 @@ func (r *seriesResolver) UpdatedAt(ctx context.Context, obj *sqlc.Series) (strin
 	return obj.UpdatedAt.Format(time.RFC3339), nil
 }
 
+// Revisions is the resolver for the revisions field.
+func (r *seriesResolver) Revisions(ctx context.Context, obj *sqlc.Series) ([]*sqlc.EntityRevision, error) {
+	return r.entityRevisions(ctx, obj.ID)
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *seriesConnectionResolver) TotalCount(ctx context.Context, obj *model.SeriesConnection) (int32, error) {
+	count, err := obj.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
+// ID is the resolver for the id field.
+func (r *tagResolver) ID(ctx context.Context, obj *sqlc.Tag) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// Books is the resolver for the books field.
+func (r *tagResolver) Books(ctx context.Context, obj *sqlc.Tag) ([]*sqlc.Book, error) {
+	return loaders.For(ctx).BooksByTag.Load(ctx, obj.ID)
+}
+
+// BookCount is the resolver for the bookCount field.
+func (r *tagResolver) BookCount(ctx context.Context, obj *sqlc.Tag) (int32, error) {
+	count, err := loaders.For(ctx).BookCountByTag.Load(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return int32(count), nil
+}
+
+// ID is the resolver for the id field.
+func (r *userResolver) ID(ctx context.Context, obj *sqlc.User) (string, error) {
+	return obj.ID.String(), nil
+}
+
+// Role is the resolver for the role field.
+func (r *userResolver) Role(ctx context.Context, obj *sqlc.User) (model.Role, error) {
+	return model.Role(strings.ToUpper(obj.Role)), nil
+}
+
+// APITokens is the resolver for the apiTokens field.
+func (r *userResolver) APITokens(ctx context.Context, obj *sqlc.User) ([]*sqlc.ApiToken, error) {
+	if err := requireSelfOr(ctx, obj.ID, users.PermManageUsers); err != nil {
+		return nil, err
+	}
+
+	tokens, err := users.NewService(r.DB.Reader(ctx)).ListTokens(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*sqlc.ApiToken, len(tokens))
+	for i := range tokens {
+		result[i] = &tokens[i]
+	}
+	return result, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *userResolver) CreatedAt(ctx context.Context, obj *sqlc.User) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *userResolver) UpdatedAt(ctx context.Context, obj *sqlc.User) (string, error) {
+	return obj.UpdatedAt.Format(time.RFC3339), nil
+}
+
+// ApiToken returns ApiTokenResolver implementation.
+func (r *Resolver) ApiToken() ApiTokenResolver { return &apiTokenResolver{r} }
+
+// AuditEvent returns AuditEventResolver implementation.
+func (r *Resolver) AuditEvent() AuditEventResolver { return &auditEventResolver{r} }
+
 // Author returns AuthorResolver implementation.
 func (r *Resolver) Author() AuthorResolver { return &authorResolver{r} }
 
+// AuthorConnection returns AuthorConnectionResolver implementation.
+func (r *Resolver) AuthorConnection() AuthorConnectionResolver { return &authorConnectionResolver{r} }
+
 // Book returns BookResolver implementation.
 func (r *Resolver) Book() BookResolver { return &bookResolver{r} }
 
+// BookConnection returns BookConnectionResolver implementation.
+func (r *Resolver) BookConnection() BookConnectionResolver { return &bookConnectionResolver{r} }
+
+// Genre returns GenreResolver implementation.
+func (r *Resolver) Genre() GenreResolver { return &genreResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Publisher returns PublisherResolver implementation.
 func (r *Resolver) Publisher() PublisherResolver { return &publisherResolver{r} }
 
+// PublisherConnection returns PublisherConnectionResolver implementation.
+func (r *Resolver) PublisherConnection() PublisherConnectionResolver {
+	return &publisherConnectionResolver{r}
+}
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Revision returns RevisionResolver implementation.
+func (r *Resolver) Revision() RevisionResolver { return &revisionResolver{r} }
+
+// SearchResult returns SearchResultResolver implementation.
+func (r *Resolver) SearchResult() SearchResultResolver { return &searchResultResolver{r} }
+
 // Series returns SeriesResolver implementation.
 func (r *Resolver) Series() SeriesResolver { return &seriesResolver{r} }
 
+// SeriesConnection returns SeriesConnectionResolver implementation.
+func (r *Resolver) SeriesConnection() SeriesConnectionResolver { return &seriesConnectionResolver{r} }
+
+// Tag returns TagResolver implementation.
+func (r *Resolver) Tag() TagResolver { return &tagResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type apiTokenResolver struct{ *Resolver }
+type auditEventResolver struct{ *Resolver }
 type authorResolver struct{ *Resolver }
+type authorConnectionResolver struct{ *Resolver }
 type bookResolver struct{ *Resolver }
+type bookConnectionResolver struct{ *Resolver }
+type genreResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type publisherResolver struct{ *Resolver }
+type publisherConnectionResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type revisionResolver struct{ *Resolver }
+type searchResultResolver struct{ *Resolver }
 type seriesResolver struct{ *Resolver }
+type seriesConnectionResolver struct{ *Resolver }
+type tagResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"book-nexus/graph/model"
+	"book-nexus/internal/books"
+	"fmt"
+	"time"
+)
+
+// toSearchInput converts the GraphQL search input into the service input,
+// applying the schema defaults for paging.
+func toSearchInput(input model.SearchBooksInput) (books.SearchInput, error) {
+	limit := int32(20)
+	offset := int32(0)
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+	if input.Offset != nil {
+		offset = *input.Offset
+	}
+
+	publishedAfter, err := parseDate(input.PublishedAfter)
+	if err != nil {
+		return books.SearchInput{}, fmt.Errorf("invalid publishedAfter: %v", err)
+	}
+	publishedBefore, err := parseDate(input.PublishedBefore)
+	if err != nil {
+		return books.SearchInput{}, fmt.Errorf("invalid publishedBefore: %v", err)
+	}
+
+	genreMatch := books.GenreMatchAny
+	if input.GenreMatch != nil && *input.GenreMatch == model.GenreMatchAll {
+		genreMatch = books.GenreMatchAll
+	}
+
+	return books.SearchInput{
+		Query:           derefString(input.Query),
+		AuthorID:        derefString(input.AuthorID),
+		PublisherID:     derefString(input.PublisherID),
+		SeriesID:        derefString(input.SeriesID),
+		AuthorName:      derefString(input.AuthorName),
+		Genre:           derefString(input.Genre),
+		Genres:          input.Genres,
+		GenreMatch:      genreMatch,
+		Language:        derefString(input.Language),
+		MinPages:        input.MinPages,
+		MaxPages:        input.MaxPages,
+		PublishedAfter:  publishedAfter,
+		PublishedBefore: publishedBefore,
+		ISBN:            derefString(input.Isbn),
+		InSeries:        input.InSeries,
+		SortBy:          derefString(input.SortBy),
+		Fuzzy:           input.Fuzzy != nil && *input.Fuzzy,
+		Limit:           limit,
+		Offset:          offset,
+	}, nil
+}
+
+// parseDate parses an optional YYYY-MM-DD date argument.
+func parseDate(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// toSearchFacets converts the service facets into their GraphQL model.
+func toSearchFacets(f *books.Facets) *model.SearchFacets {
+	return &model.SearchFacets{
+		Genres:     toFacetValues(f.Genres),
+		Languages:  toFacetValues(f.Languages),
+		Publishers: toFacetValues(f.Publishers),
+		Authors:    toFacetValues(f.Authors),
+		Decades:    toFacetValues(f.Decades),
+	}
+}
+
+func toFacetValues(values []books.FacetValue) []*model.FacetValue {
+	result := make([]*model.FacetValue, len(values))
+	for i, v := range values {
+		result[i] = &model.FacetValue{
+			Value: v.Value,
+			Label: v.Label,
+			Count: int32(v.Count),
+		}
+	}
+	return result
+}
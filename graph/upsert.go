@@ -0,0 +1,183 @@
+package graph
+
+import (
+	"book-nexus/graph/model"
+	"book-nexus/internal/audit"
+	"book-nexus/internal/books"
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// upsertBooksOperation names upsertBooks requests under their idempotency
+// keys.
+const upsertBooksOperation = "upsertBooks"
+
+// upsertBooksRequest is what identifies an upsertBooks request, so a key
+// reused for a different one is caught.
+type upsertBooksRequest struct {
+	Input   []*model.BookUpsertInput `json:"input"`
+	MatchOn model.BookMatch          `json:"matchOn"`
+}
+
+// storedUpsertResult is a BookUpsertResult as kept under an idempotency
+// key. Replays return the books as they are by then.
+type storedUpsertResult struct {
+	Status model.BookUpsertStatus `json:"status"`
+	BookID *uuid.UUID             `json:"bookId,omitempty"`
+	Error  *string                `json:"error,omitempty"`
+}
+
+// toUpsertBook converts one upsertBooks row into the service input.
+func toUpsertBook(in *model.BookUpsertInput) (books.UpsertBookInput, error) {
+	author, err := toRef("author", in.AuthorID, in.AuthorName)
+	if err != nil {
+		return books.UpsertBookInput{}, err
+	}
+	if author == nil {
+		return books.UpsertBookInput{}, fmt.Errorf("authorId or authorName is required")
+	}
+	publisher, err := toRef("publisher", in.PublisherID, in.PublisherName)
+	if err != nil {
+		return books.UpsertBookInput{}, err
+	}
+	series, err := toRef("series", in.SeriesID, in.SeriesName)
+	if err != nil {
+		return books.UpsertBookInput{}, err
+	}
+
+	var publishedDate *time.Time
+	if in.PublishedDate != nil {
+		t, err := time.Parse("2006-01-02", *in.PublishedDate)
+		if err != nil {
+			return books.UpsertBookInput{}, fmt.Errorf("invalid date format: %v", err)
+		}
+		publishedDate = &t
+	}
+
+	return books.UpsertBookInput{
+		Title:          in.Title,
+		Subtitle:       in.Subtitle,
+		Author:         *author,
+		Publisher:      publisher,
+		PublishedDate:  publishedDate,
+		ISBN10:         in.Isbn10,
+		ISBN13:         in.Isbn13,
+		Pages:          in.Pages,
+		Language:       in.Language,
+		Description:    in.Description,
+		Series:         series,
+		SeriesPosition: in.SeriesPosition,
+		Genres:         in.Genres,
+		Tags:           in.Tags,
+		ImageURL:       in.ImageURL,
+	}, nil
+}
+
+// toRef converts an ID or name pair into a reference, nil when neither is
+// set.
+func toRef(entity string, id, name *string) (*books.Ref, error) {
+	switch {
+	case id != nil && name != nil:
+		return nil, fmt.Errorf("set %sId or %sName, not both", entity, entity)
+	case id != nil:
+		parsed, err := uuid.Parse(*id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s ID: %v", entity, err)
+		}
+		return &books.Ref{ID: &parsed}, nil
+	case name != nil:
+		return &books.Ref{Name: strings.TrimSpace(*name)}, nil
+	}
+	return nil, nil
+}
+
+func upsertError(index int, err error) *model.BookUpsertResult {
+	msg := err.Error()
+	return &model.BookUpsertResult{Index: int32(index), Status: model.BookUpsertStatusError, Error: &msg}
+}
+
+func storeUpsertResults(results []*model.BookUpsertResult) []storedUpsertResult {
+	stored := make([]storedUpsertResult, len(results))
+	for i, res := range results {
+		stored[i] = storedUpsertResult{Status: res.Status, Error: res.Error}
+		if res.Book != nil {
+			stored[i].BookID = &res.Book.ID
+		}
+	}
+	return stored
+}
+
+// replayUpsertResults rebuilds the results kept under an idempotency key. A
+// book deleted since keeps its row's status, with no book and an error
+// saying so.
+func replayUpsertResults(ctx context.Context, q *sqlc.Queries, stored []storedUpsertResult) ([]*model.BookUpsertResult, error) {
+	var ids []uuid.UUID
+	for _, res := range stored {
+		if res.BookID != nil {
+			ids = append(ids, *res.BookID)
+		}
+	}
+	found, err := q.GetBooksByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*sqlc.Book, len(found))
+	for i := range found {
+		if found[i].DeletedAt == nil {
+			byID[found[i].ID] = &found[i]
+		}
+	}
+
+	results := make([]*model.BookUpsertResult, len(stored))
+	for i, res := range stored {
+		results[i] = &model.BookUpsertResult{Index: int32(i), Status: res.Status, Error: res.Error}
+		if res.BookID == nil {
+			continue
+		}
+		if book, ok := byID[*res.BookID]; ok {
+			results[i].Book = book
+		} else {
+			msg := fmt.Sprintf("book %s has been deleted since", *res.BookID)
+			results[i].Error = &msg
+		}
+	}
+	return results, nil
+}
+
+// recordUpserts audits what upsertBooks created and updated.
+func recordUpserts(ctx context.Context, tx database.DBTX, upserted *books.UpsertBooksResult) error {
+	for _, author := range upserted.Authors {
+		if err := record(ctx, tx, audit.TypeAuthor, author.ID, audit.ActionCreate, nil, author); err != nil {
+			return err
+		}
+	}
+	for _, publisher := range upserted.Publishers {
+		if err := record(ctx, tx, audit.TypePublisher, publisher.ID, audit.ActionCreate, nil, publisher); err != nil {
+			return err
+		}
+	}
+	for _, series := range upserted.Series {
+		if err := record(ctx, tx, audit.TypeSeries, series.ID, audit.ActionCreate, nil, series); err != nil {
+			return err
+		}
+	}
+	for _, row := range upserted.Rows {
+		var err error
+		switch row.Status {
+		case books.UpsertCreated:
+			err = record(ctx, tx, audit.TypeBook, row.Book.ID, audit.ActionCreate, nil, row.Book)
+		case books.UpsertUpdated:
+			err = record(ctx, tx, audit.TypeBook, row.Book.ID, audit.ActionUpdate, row.Before, row.Book)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
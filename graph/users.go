@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"book-nexus/graph/model"
+	"book-nexus/internal/users"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// requireSelfOr allows a signed-in user to act on their own account, and
+// anyone with perm to act on any account.
+func requireSelfOr(ctx context.Context, userID uuid.UUID, perm users.Permission) error {
+	p, ok := users.PrincipalFrom(ctx)
+	if !ok {
+		return ErrUnauthorized
+	}
+	if p.UserID != uuid.Nil && p.UserID == userID {
+		return nil
+	}
+	return Require(ctx, perm)
+}
+
+// optionalTime formats a nullable timestamp for a nullable String field.
+func optionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
+
+func toSession(s *users.Session) *model.Session {
+	return &model.Session{
+		AccessToken:           s.AccessToken,
+		AccessTokenExpiresAt:  s.AccessExpiresAt.Format(time.RFC3339),
+		RefreshToken:          s.RefreshToken,
+		RefreshTokenExpiresAt: s.RefreshExpiresAt.Format(time.RFC3339),
+		User:                  &s.User,
+	}
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+)
+
+type entity struct {
+	ID       string
+	Title    string
+	AuthorID string
+	Pages    *int32
+}
+
+func TestDiff(t *testing.T) {
+	pages := int32(320)
+	before := entity{ID: "1", Title: "Dune", AuthorID: "a"}
+	after := entity{ID: "1", Title: "Dune Messiah", AuthorID: "a", Pages: &pages}
+
+	got, err := Diff(before, after)
+	if err != nil {
+		t.Fatalf("Diff returned error: %v", err)
+	}
+	want := map[string]Change{
+		"title": {From: "Dune", To: "Dune Messiah"},
+		"pages": {From: nil, To: float64(320)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDiffFromNothing(t *testing.T) {
+	var none *entity
+	got, err := Diff(none, entity{ID: "1", Title: "Dune"})
+	if err != nil {
+		t.Fatalf("Diff returned error: %v", err)
+	}
+	if len(got) != 3 || got["id"].To != "1" || got["authorID"].From != nil {
+		t.Fatalf("expected every non-null field to be created, got %v", got)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	cases := map[string]string{
+		"ID":        "id",
+		"AuthorID":  "authorID",
+		"ImageUrl":  "imageUrl",
+		"Isbn13":    "isbn13",
+		"ISBNCode":  "isbnCode",
+		"CreatedAt": "createdAt",
+	}
+	for in, want := range cases {
+		if got := fieldName(in); got != want {
+			t.Fatalf("fieldName(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
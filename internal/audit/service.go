@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/pagination"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is one change to record.
+type Event struct {
+	EntityType string
+	EntityID   uuid.UUID
+	Action     Action
+	Before     any
+	After      any
+}
+
+type Service struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+}
+
+func NewService(db database.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Record writes e to the audit log, attributed to the caller on ctx.
+func (s *Service) Record(ctx context.Context, e Event) error {
+	diff, err := Diff(e.Before, e.After)
+	if err != nil {
+		return fmt.Errorf("audit diff: %w", err)
+	}
+	b, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("audit diff: %w", err)
+	}
+
+	caller := CallerFrom(ctx)
+	return s.queries.CreateAuditEvent(ctx, sqlc.CreateAuditEventParams{
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Action:     string(e.Action),
+		Diff:       b,
+		Actor:      caller.Actor,
+		RequestID:  optional(caller.RequestID),
+		RemoteAddr: optional(caller.RemoteAddr),
+	})
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// Filter narrows the audit log. Zero values match everything.
+type Filter struct {
+	EntityID   uuid.UUID
+	EntityType string
+	Since      time.Time
+}
+
+// ListEventsPage returns one page of events matching filter, newest first.
+func (s *Service) ListEventsPage(ctx context.Context, filter Filter, first int32, after string) (*pagination.Page[sqlc.AuditEvent], error) {
+	cursor, err := pagination.Decode(after, "newest")
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.ListAuditEventsPageParams{
+		EntityID:   filter.EntityID,
+		EntityType: filter.EntityType,
+		Since:      filter.Since,
+		PageSize:   first + 1,
+	}
+	if cursor != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+		params.AfterCreatedAt = createdAt
+		params.AfterID = cursor.ID
+	}
+
+	rows, err := s.queries.ListAuditEventsPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(rows, first, func(row sqlc.AuditEvent) pagination.Cursor {
+		return pagination.Cursor{Sort: "newest", Key: row.CreatedAt.Format(time.RFC3339Nano), ID: row.ID}
+	}), nil
+}
@@ -1,19 +1,27 @@
 package authors
 
 import (
+	"book-nexus/internal/database"
 	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/deletion"
+	"book-nexus/internal/pagination"
+	"book-nexus/internal/revisions"
+	"book-nexus/internal/slug"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 )
 
 type Service struct {
-	db      *pgxpool.Pool
+	db      database.DBTX
 	queries *sqlc.Queries
 }
 
-func NewService(db *pgxpool.Pool) *Service {
+func NewService(db database.DBTX) *Service {
 	return &Service{
 		db:      db,
 		queries: sqlc.New(db),
@@ -28,6 +36,10 @@ func (s *Service) GetAuthor(ctx context.Context, id uuid.UUID) (*sqlc.Author, er
 	return &author, nil
 }
 
+func (s *Service) GetAuthorsByIDs(ctx context.Context, ids []uuid.UUID) ([]sqlc.Author, error) {
+	return s.queries.GetAuthorsByIDs(ctx, ids)
+}
+
 func (s *Service) GetAuthorBySlug(ctx context.Context, slug string) (*sqlc.Author, error) {
 	author, err := s.queries.GetAuthorBySlug(ctx, &slug)
 	if err != nil {
@@ -59,6 +71,32 @@ func (s *Service) SearchAuthors(ctx context.Context, query string, limit, offset
 	})
 }
 
+// ListAuthorsPage returns up to first authors ordered by name, optionally
+// filtered by search, starting after the opaque cursor after.
+func (s *Service) ListAuthorsPage(ctx context.Context, search string, first int32, after string) (*pagination.Page[sqlc.Author], error) {
+	cursor, err := pagination.Decode(after, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.ListAuthorsPageParams{
+		Search:   search,
+		PageSize: first + 1,
+	}
+	if cursor != nil {
+		params.AfterName = cursor.Key
+		params.AfterID = cursor.ID
+	}
+
+	rows, err := s.queries.ListAuthorsPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(rows, first, func(row sqlc.Author) pagination.Cursor {
+		return pagination.Cursor{Sort: "name", Key: row.Name, ID: row.ID}
+	}), nil
+}
+
 func (s *Service) CountAuthors(ctx context.Context) (int64, error) {
 	return s.queries.CountAuthors(ctx)
 }
@@ -71,6 +109,10 @@ func (s *Service) GetAuthorBookCount(ctx context.Context, authorID uuid.UUID) (i
 	return s.queries.GetAuthorBookCount(ctx, authorID)
 }
 
+func (s *Service) GetAuthorBookCounts(ctx context.Context, authorIDs []uuid.UUID) ([]sqlc.GetAuthorBookCountsRow, error) {
+	return s.queries.GetAuthorBookCounts(ctx, authorIDs)
+}
+
 type CreateAuthorInput struct {
 	Name string
 	Slug *string
@@ -89,6 +131,42 @@ func (s *Service) CreateAuthor(ctx context.Context, input CreateAuthorInput) (*s
 	return &author, nil
 }
 
+// UpsertAuthorByName returns the live author with exactly this name, creating
+// one when there is none; created reports which. Names are unique across the
+// trash too, so a trashed author of that name is an error until it is
+// restored or renamed. A new author gets a slug made from its name, with a
+// numeric suffix when that is taken.
+func (s *Service) UpsertAuthorByName(ctx context.Context, name string) (_ *sqlc.Author, created bool, err error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, errors.New("author name is required")
+	}
+
+	existing, err := s.queries.GetAuthorByName(ctx, name)
+	if err == nil {
+		return &existing, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	taken, err := s.queries.ListAuthorSlugsWithBases(ctx, []string{slug.Make(name)})
+	if err != nil {
+		return nil, false, err
+	}
+	author, err := s.queries.UpsertAuthorByName(ctx, sqlc.UpsertAuthorByNameParams{
+		Name: name,
+		Slug: slug.Unique(slug.Taken(taken), slug.Make(name)),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if author.DeletedAt != nil {
+		return nil, false, fmt.Errorf("author %q is in the trash; restore it first", name)
+	}
+	return &author, true, nil
+}
+
 type UpdateAuthorInput struct {
 	ID   uuid.UUID
 	Name string
@@ -96,8 +174,28 @@ type UpdateAuthorInput struct {
 	Bio  *string
 }
 
+// UpdateAuthor replaces a author's fields, saving the row as it was as a new
+// revision.
 func (s *Service) UpdateAuthor(ctx context.Context, input UpdateAuthorInput) (*sqlc.Author, error) {
-	author, err := s.queries.UpdateAuthor(ctx, sqlc.UpdateAuthorParams{
+	if input.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	before, err := q.GetAuthorByIDForUpdate(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := revisions.Save(ctx, q, revisions.TypeAuthor, before.ID, before); err != nil {
+		return nil, err
+	}
+	author, err := q.UpdateAuthor(ctx, sqlc.UpdateAuthorParams{
 		ID:   input.ID,
 		Name: input.Name,
 		Slug: input.Slug,
@@ -106,9 +204,85 @@ func (s *Service) UpdateAuthor(ctx context.Context, input UpdateAuthorInput) (*s
 	if err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return &author, nil
 }
 
-func (s *Service) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
-	return s.queries.DeleteAuthor(ctx, id)
+// RevertAuthor updates a author back to the fields saved in one of its
+// revisions. It goes through UpdateAuthor, so the current fields are saved as
+// a revision in turn.
+func (s *Service) RevertAuthor(ctx context.Context, id, revisionID uuid.UUID) (*sqlc.Author, error) {
+	var snapshot sqlc.Author
+	if err := revisions.Load(ctx, s.queries, revisions.TypeAuthor, id, revisionID, &snapshot); err != nil {
+		return nil, err
+	}
+	return s.UpdateAuthor(ctx, UpdateAuthorInput{
+		ID:   id,
+		Name: snapshot.Name,
+		Slug: snapshot.Slug,
+		Bio:  snapshot.Bio,
+	})
+}
+
+// DeleteAuthor moves an author to the trash, first dealing with its books as the
+// strategy says. Under Cascade the books are trashed with it, at the same
+// deleted_at, so restoring the author brings them back. The author row is locked
+// for the whole transaction, so no book can be attached to it between the
+// check and the delete. It returns the books trashed along with it, or the
+// live books moved to another author; trashed books stay with the trashed
+// author.
+func (s *Service) DeleteAuthor(ctx context.Context, id uuid.UUID, strategy deletion.Strategy) (trashed, moved []sqlc.Book, err error) {
+	if err := strategy.Validate(id); err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	deletedAt, err := q.LockAuthor(ctx, id)
+	if err := deletion.Live("author", id, deletedAt, err); err != nil {
+		return nil, nil, err
+	}
+
+	switch strategy.Mode {
+	case deletion.ReassignTo:
+		deletedAt, err := q.LockAuthor(ctx, *strategy.ReassignTo)
+		if err := deletion.Live("reassign target author", *strategy.ReassignTo, deletedAt, err); err != nil {
+			return nil, nil, err
+		}
+		moved, err = q.ReassignAuthorBooks(ctx, sqlc.ReassignAuthorBooksParams{
+			ReassignTo: *strategy.ReassignTo,
+			AuthorID:   id,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	case deletion.Cascade:
+		trashed, err = q.TrashAuthorBooks(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		bookIDs, err := q.GetAuthorBookIDs(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(bookIDs) > 0 {
+			return nil, nil, &deletion.BlockedError{Entity: "author", ID: id, BookIDs: bookIDs}
+		}
+	}
+
+	if err := q.TrashAuthor(ctx, id); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return trashed, moved, nil
 }
@@ -0,0 +1,139 @@
+package books
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// facetLimit caps the number of buckets returned per facet.
+const facetLimit = 30
+
+// FacetValue is one bucket of a facet, such as "Fantasy (132)".
+type FacetValue struct {
+	Value string // what to pass back as the filter, e.g. a slug or ID
+	Label string
+	Count int64
+}
+
+type Facets struct {
+	Genres     []FacetValue
+	Languages  []FacetValue
+	Publishers []FacetValue
+	Authors    []FacetValue
+	Decades    []FacetValue
+}
+
+// facet describes how to group the matching books for one sidebar. Each
+// facet is counted with its own filter cleared, so selecting "Fantasy" still
+// shows the counts for every other genre.
+type facet struct {
+	clear   func(*SearchInput)
+	value   string
+	label   string
+	joins   string
+	where   string
+	groupBy string
+	orderBy string
+	dest    func(*Facets) *[]FacetValue
+}
+
+var facets = []facet{
+	{
+		clear: func(in *SearchInput) { in.Genre, in.Genres = "", nil },
+		value: "fg.slug",
+		label: "fg.name",
+		joins: `
+  JOIN book_genres fbg ON fbg.book_id = b.id
+  JOIN genres fg ON fg.id = fbg.genre_id`,
+		groupBy: "fg.slug, fg.name",
+		orderBy: "COUNT(*) DESC, fg.name",
+		dest:    func(f *Facets) *[]FacetValue { return &f.Genres },
+	},
+	{
+		clear:   func(in *SearchInput) { in.Language = "" },
+		value:   "b.language",
+		label:   "b.language",
+		where:   "b.language IS NOT NULL",
+		groupBy: "b.language",
+		orderBy: "COUNT(*) DESC, b.language",
+		dest:    func(f *Facets) *[]FacetValue { return &f.Languages },
+	},
+	{
+		clear: func(in *SearchInput) { in.PublisherID = "" },
+		value: "fp.id::text",
+		label: "fp.name",
+		joins: `
+  JOIN publishers fp ON fp.id = b.publisher_id`,
+		groupBy: "fp.id, fp.name",
+		orderBy: "COUNT(*) DESC, fp.name",
+		dest:    func(f *Facets) *[]FacetValue { return &f.Publishers },
+	},
+	{
+		clear:   func(in *SearchInput) { in.AuthorID, in.AuthorName = "", "" },
+		value:   "a.id::text",
+		label:   "a.name",
+		groupBy: "a.id, a.name",
+		orderBy: "COUNT(*) DESC, a.name",
+		dest:    func(f *Facets) *[]FacetValue { return &f.Authors },
+	},
+	{
+		clear: func(in *SearchInput) { in.PublishedAfter, in.PublishedBefore = nil, nil },
+		value: "(EXTRACT(DECADE FROM b.published_date) * 10)::int::text",
+		label: "(EXTRACT(DECADE FROM b.published_date) * 10)::int || 's'",
+		where: "b.published_date IS NOT NULL",
+		// Grouped and ordered by the number, since the text value would put
+		// "800" after "1990".
+		groupBy: "EXTRACT(DECADE FROM b.published_date)",
+		orderBy: "EXTRACT(DECADE FROM b.published_date)",
+		dest:    func(f *Facets) *[]FacetValue { return &f.Decades },
+	},
+}
+
+func (f facet) query(input SearchInput, fuzzy bool) (string, []any) {
+	f.clear(&input)
+	q := newSearchQuery(input, fuzzy)
+	if f.where != "" {
+		q.where = append(q.where, f.where)
+	}
+	sql := fmt.Sprintf("SELECT %s, %s, COUNT(*)\n%s%s\n%s\nGROUP BY %s\nORDER BY %s\nLIMIT %d",
+		f.value, f.label, searchFrom, f.joins, q.whereClause(), f.groupBy, f.orderBy, facetLimit)
+	return sql, q.args
+}
+
+// SearchFacets counts the books matching input per genre, language,
+// publisher, author and publication decade. All facets are fetched in a
+// single round trip.
+func (s *Service) SearchFacets(ctx context.Context, input SearchInput) (*Facets, error) {
+	fuzzy, err := s.useFuzzy(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &pgx.Batch{}
+	for _, f := range facets {
+		sql, args := f.query(input, fuzzy)
+		batch.Queue(sql, args...)
+	}
+	results := s.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	out := &Facets{}
+	for _, f := range facets {
+		rows, err := results.Query()
+		if err != nil {
+			return nil, err
+		}
+		values, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (FacetValue, error) {
+			var v FacetValue
+			err := row.Scan(&v.Value, &v.Label, &v.Count)
+			return v, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		*f.dest(out) = values
+	}
+	return out, nil
+}
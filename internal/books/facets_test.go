@@ -0,0 +1,37 @@
+package books
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFacetQueryIgnoresOwnFilter(t *testing.T) {
+	input := SearchInput{Query: "dragons", Genre: "fantasy", PublisherID: "p", AuthorID: "a"}
+
+	for i, f := range facets {
+		sql, args := f.query(input, false)
+
+		// Every placeholder must have an argument and vice versa, or
+		// Postgres rejects the statement.
+		for n := 1; n <= len(args); n++ {
+			if !strings.Contains(sql, fmt.Sprintf("$%d", n)) {
+				t.Fatalf("facet %d: argument $%d is unused in %s", i, n, sql)
+			}
+		}
+		if strings.Contains(sql, fmt.Sprintf("$%d", len(args)+1)) {
+			t.Fatalf("facet %d: placeholder $%d has no argument", i, len(args)+1)
+		}
+
+		cleared := input
+		f.clear(&cleared)
+		for _, arg := range args {
+			if arg == "fantasy" && cleared.Genre == "" {
+				t.Fatalf("facet %d: expected the genre filter to be dropped", i)
+			}
+			if arg == "p" && cleared.PublisherID == "" {
+				t.Fatalf("facet %d: expected the publisher filter to be dropped", i)
+			}
+		}
+	}
+}
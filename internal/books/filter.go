@@ -0,0 +1,191 @@
+package books
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GenreMatch selects how SearchInput.Genres is applied.
+type GenreMatch string
+
+const (
+	GenreMatchAny GenreMatch = "ANY" // books with at least one of the genres
+	GenreMatchAll GenreMatch = "ALL" // books with every one of the genres
+)
+
+// add appends a predicate to q, binding each of args to a placeholder. The
+// predicate refers to them as %[1]s, %[2]s, ... (or %s when used once each).
+func (q *searchQuery) add(predicate string, args ...any) {
+	placeholders := make([]any, len(args))
+	for i, a := range args {
+		placeholders[i] = q.arg(a)
+	}
+	q.where = append(q.where, fmt.Sprintf(predicate, placeholders...))
+}
+
+// searchFilter adds the predicates for its own fields of SearchInput. Each
+// filter is independent, so new ones can be added without touching the
+// others or the search, count, page and facet queries that share them.
+type searchFilter func(q *searchQuery, in SearchInput)
+
+var searchFilters = []searchFilter{
+	filterAuthor,
+	filterPublisher,
+	filterSeries,
+	filterGenre,
+	filterGenres,
+	filterLanguage,
+	filterPages,
+	filterPublished,
+	filterISBN,
+}
+
+func filterAuthor(q *searchQuery, in SearchInput) {
+	if in.AuthorID != "" {
+		q.add("b.author_id::text = %s", in.AuthorID)
+	}
+	if in.AuthorName != "" {
+		q.add("a.name ILIKE '%%' || %s || '%%'", in.AuthorName)
+	}
+}
+
+func filterPublisher(q *searchQuery, in SearchInput) {
+	if in.PublisherID != "" {
+		q.add("b.publisher_id::text = %s", in.PublisherID)
+	}
+}
+
+func filterSeries(q *searchQuery, in SearchInput) {
+	if in.SeriesID != "" {
+		q.add("b.series_id::text = %s", in.SeriesID)
+	}
+	if in.InSeries != nil && *in.InSeries {
+		q.add("b.series_id IS NOT NULL")
+	} else if in.InSeries != nil {
+		q.add("b.series_id IS NULL")
+	}
+}
+
+// genreSlugs turns a bound text[] of genre names or slugs into their slugs,
+// using the same slugify() the genres table is keyed by.
+const genreSlugs = "ARRAY(SELECT slugify(TRIM(v)) FROM unnest(%[1]s::text[]) v)"
+
+func filterGenre(q *searchQuery, in SearchInput) {
+	// Matches a whole genre, so "fantasy" does not match "urban fantasy romance".
+	if in.Genre != "" {
+		q.add(`EXISTS (
+    SELECT 1 FROM book_genres bg JOIN genres g ON g.id = bg.genre_id
+    WHERE bg.book_id = b.id AND g.slug = slugify(TRIM(%s))
+  )`, in.Genre)
+	}
+}
+
+func filterGenres(q *searchQuery, in SearchInput) {
+	if len(in.Genres) == 0 {
+		return
+	}
+	if in.GenreMatch == GenreMatchAll {
+		q.add(`(
+    SELECT COUNT(*) FROM book_genres bg JOIN genres g ON g.id = bg.genre_id
+    WHERE bg.book_id = b.id AND g.slug = ANY(`+genreSlugs+`)
+  ) = (SELECT COUNT(DISTINCT s) FROM unnest(`+genreSlugs+`) s)`, in.Genres)
+		return
+	}
+	q.add(`EXISTS (
+    SELECT 1 FROM book_genres bg JOIN genres g ON g.id = bg.genre_id
+    WHERE bg.book_id = b.id AND g.slug = ANY(`+genreSlugs+`)
+  )`, in.Genres)
+}
+
+func filterLanguage(q *searchQuery, in SearchInput) {
+	if in.Language != "" {
+		q.add("LOWER(b.language) = LOWER(%s)", in.Language)
+	}
+}
+
+func filterPages(q *searchQuery, in SearchInput) {
+	if in.MinPages != nil {
+		q.add("b.pages >= %s", *in.MinPages)
+	}
+	if in.MaxPages != nil {
+		q.add("b.pages <= %s", *in.MaxPages)
+	}
+}
+
+func filterPublished(q *searchQuery, in SearchInput) {
+	if in.PublishedAfter != nil {
+		q.add("b.published_date >= %s::date", *in.PublishedAfter)
+	}
+	if in.PublishedBefore != nil {
+		q.add("b.published_date <= %s::date", *in.PublishedBefore)
+	}
+}
+
+func filterISBN(q *searchQuery, in SearchInput) {
+	if in.ISBN == "" {
+		return
+	}
+	candidates := isbnCandidates(in.ISBN)
+	if len(candidates) == 0 {
+		// Not a plausible ISBN, so nothing can match.
+		q.add("FALSE")
+		return
+	}
+	// Stored ISBNs keep whatever separators they were entered with, so they
+	// are normalized the same way; the expressions match the indexes on books.
+	q.add("(regexp_replace(upper(b.isbn10), '[^0-9X]', '', 'g') = ANY(%[1]s::text[]) OR regexp_replace(upper(b.isbn13), '[^0-9X]', '', 'g') = ANY(%[1]s::text[]))", candidates)
+}
+
+// normalizeISBN strips hyphens, spaces and any other separators, keeping
+// digits and an ISBN-10 check character of X.
+func normalizeISBN(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isbnCandidates returns the forms an ISBN may be stored under: the
+// normalized value plus its ISBN-10/ISBN-13 counterpart, so a search for
+// either matches a book that only has the other.
+func isbnCandidates(s string) []string {
+	isbn := normalizeISBN(s)
+	switch {
+	case len(isbn) == 10 && !strings.ContainsRune(isbn[:9], 'X'):
+		return []string{isbn, "978" + isbn[:9] + isbn13Check("978"+isbn[:9])}
+	case len(isbn) == 13 && strings.ContainsRune(isbn, 'X'):
+		return nil
+	case len(isbn) == 13 && strings.HasPrefix(isbn, "978"):
+		return []string{isbn, isbn[3:12] + isbn10Check(isbn[3:12])}
+	case len(isbn) == 13:
+		return []string{isbn}
+	}
+	return nil
+}
+
+func isbn10Check(first9 string) string {
+	sum := 0
+	for i, r := range first9 {
+		sum += (10 - i) * int(r-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return "X"
+	}
+	return fmt.Sprint(check)
+}
+
+func isbn13Check(first12 string) string {
+	sum := 0
+	for i, r := range first12 {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return fmt.Sprint((10 - sum%10) % 10)
+}
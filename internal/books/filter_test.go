@@ -0,0 +1,76 @@
+package books
+
+import (
+	"book-nexus/internal/database/dbtest"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestISBNCandidates(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{"0-306-40615-2", []string{"0306406152", "9780306406157"}},
+		{"978-0-306-40615-7", []string{"9780306406157", "0306406152"}},
+		{"080442957x", []string{"080442957X", "9780804429573"}},
+		{"979-10-90636-07-1", []string{"9791090636071"}},
+		{"12345", nil},
+		{"97803064061X7", nil},
+	}
+	for _, c := range cases {
+		if got := isbnCandidates(c.in); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("isbnCandidates(%q): expected %v, got %v", c.in, c.want, got)
+		}
+	}
+}
+
+func TestSearchMatchesSeparatedStoredISBN(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+
+	author, err := q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Name: "Karl Popper"})
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	isbn13 := "978-0-306-40615-7"
+	book, err := q.CreateBook(ctx, sqlc.CreateBookParams{Title: "The Logic of Scientific Discovery", AuthorID: author.ID, Isbn13: &isbn13})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+
+	s := NewService(pool)
+	for _, isbn := range []string{"9780306406157", "978 0306406157", "0-306-40615-2"} {
+		result, err := s.SearchBooks(ctx, SearchInput{ISBN: isbn, Limit: 10})
+		if err != nil {
+			t.Fatalf("search %q: %v", isbn, err)
+		}
+		if result.Total != 1 || len(result.Books) != 1 || result.Books[0].ID != book.ID {
+			t.Fatalf("search %q: expected %s, got %d books", isbn, book.ID, result.Total)
+		}
+	}
+}
+
+func TestSearchFiltersAreIndependent(t *testing.T) {
+	min, inSeries := int32(100), true
+	input := SearchInput{
+		Language: "English",
+		MinPages: &min,
+		InSeries: &inSeries,
+		Genres:   []string{"Fantasy", "Horror"},
+	}
+
+	q := newSearchQuery(input, false)
+	if len(q.where) != 5 {
+		t.Fatalf("expected 5 predicates, got %d: %v", len(q.where), q.where)
+	}
+	if q.where[0] != "b.deleted_at IS NULL" {
+		t.Fatalf("expected trashed books to be excluded first, got %q", q.where[0])
+	}
+	if len(q.args) != 3 {
+		t.Fatalf("expected 3 bound arguments, got %d: %v", len(q.args), q.args)
+	}
+}
@@ -0,0 +1,261 @@
+package books
+
+import (
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/pagination"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// bookColumns lists the books columns in sqlc.Book field order.
+const bookColumns = `b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at, b.deleted_at`
+
+const searchFrom = `FROM books b
+  LEFT JOIN authors a ON b.author_id = a.id
+  LEFT JOIN book_search_documents d ON d.book_id = b.id`
+
+// searchConfig is the text search configuration the documents in
+// book_search_documents are built with; queries must use the same one.
+const searchConfig = "english"
+
+// sortOrder describes how a SortBy option orders rows and how a cursor key
+// taken from a row is compared against the remaining rows.
+type sortOrder struct {
+	expr    string // SQL expression rows are ordered by
+	sqlType string // type the cursor key is cast back to
+	desc    bool
+	ranked  bool // expr is computed from the search query, see sortExpr
+}
+
+const defaultSortBy = "newest"
+
+var sortOrders = map[string]sortOrder{
+	defaultSortBy: {expr: "b.created_at", sqlType: "timestamptz", desc: true},
+	"title_asc":   {expr: "b.title", sqlType: "text"},
+	"title_desc":  {expr: "b.title", sqlType: "text", desc: true},
+	// NULL dates are mapped to +/-infinity so they sort last in both
+	// directions and still compare cleanly in a keyset predicate.
+	"date_asc":  {expr: "COALESCE(b.published_date, 'infinity'::date)", sqlType: "date"},
+	"date_desc": {expr: "COALESCE(b.published_date, '-infinity'::date)", sqlType: "date", desc: true},
+	"author":    {expr: "a.name", sqlType: "text"},
+	"relevance": {sqlType: "real", desc: true, ranked: true},
+}
+
+// resolveSort maps a SortBy option to its ordering, treating unknown or empty
+// values as the default newest-first order. Relevance needs a search query to
+// rank against, so it also falls back to the default without one.
+func resolveSort(input SearchInput) (string, sortOrder) {
+	if order, ok := sortOrders[input.SortBy]; ok && (!order.ranked || input.Query != "") {
+		return input.SortBy, order
+	}
+	return defaultSortBy, sortOrders[defaultSortBy]
+}
+
+// searchQuery accumulates the WHERE predicates and positional arguments
+// shared by the search, count and paginated queries.
+type searchQuery struct {
+	where []string
+	args  []any
+	rank  string // relevance of a row to input.Query, if any
+}
+
+func (q *searchQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// newSearchQuery builds the predicates for input: books in the trash are
+// always excluded, then the text query, then each of searchFilters. With
+// fuzzy set, the query matches titles and author names by trigram word
+// similarity instead of full-text search, so misspellings still find
+// something.
+func newSearchQuery(input SearchInput, fuzzy bool) *searchQuery {
+	q := &searchQuery{where: []string{"b.deleted_at IS NULL"}}
+	if input.Query != "" && fuzzy {
+		p := q.arg(input.Query)
+		q.where = append(q.where, fmt.Sprintf("(%[1]s <%% b.title OR %[1]s <%% a.name)", p))
+		q.rank = fmt.Sprintf("GREATEST(word_similarity(%[1]s, b.title), word_similarity(%[1]s, a.name))", p)
+	} else if input.Query != "" {
+		// websearch_to_tsquery accepts user input as typed: "quoted phrases",
+		// -exclusions and OR, and never fails on malformed syntax.
+		tsquery := fmt.Sprintf("websearch_to_tsquery('%s', %s)", searchConfig, q.arg(input.Query))
+		q.where = append(q.where, "d.document @@ "+tsquery)
+		q.rank = fmt.Sprintf("ts_rank(d.document, %s)", tsquery)
+	}
+	for _, filter := range searchFilters {
+		filter(q, input)
+	}
+	return q
+}
+
+// sortExpr returns the SQL expression rows are ordered by under o.
+func (q *searchQuery) sortExpr(o sortOrder) string {
+	if o.ranked {
+		return q.rank
+	}
+	return o.expr
+}
+
+func (q *searchQuery) orderBy(o sortOrder) string {
+	dir := "ASC"
+	if o.desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, b.id %s", q.sortExpr(o), dir, dir)
+}
+
+func (q *searchQuery) whereClause() string {
+	if len(q.where) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.where, "\n  AND ")
+}
+
+// bookFields returns scan destinations for bookColumns.
+func bookFields(b *sqlc.Book) []any {
+	return []any{
+		&b.ID,
+		&b.Title,
+		&b.Subtitle,
+		&b.AuthorID,
+		&b.PublisherID,
+		&b.PublishedDate,
+		&b.Isbn10,
+		&b.Isbn13,
+		&b.Pages,
+		&b.Language,
+		&b.Description,
+		&b.SeriesID,
+		&b.SeriesPosition,
+		&b.Genres,
+		&b.Tags,
+		&b.ImageUrl,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+		&b.DeletedAt,
+	}
+}
+
+// useFuzzy reports whether input should fall back to similarity matching,
+// which it does only when it asks to and the exact search finds nothing.
+func (s *Service) useFuzzy(ctx context.Context, input SearchInput) (bool, error) {
+	if !input.Fuzzy || input.Query == "" {
+		return false, nil
+	}
+	count, err := s.countSearchResults(ctx, input, false)
+	return count == 0, err
+}
+
+func (s *Service) searchBooks(ctx context.Context, input SearchInput, fuzzy bool) ([]sqlc.Book, error) {
+	q := newSearchQuery(input, fuzzy)
+	_, order := resolveSort(input)
+	sql := fmt.Sprintf("SELECT %s\n%s\n%s\n%s\nLIMIT %s OFFSET %s",
+		bookColumns, searchFrom, q.whereClause(), q.orderBy(order), q.arg(input.Limit), q.arg(input.Offset))
+
+	rows, err := s.db.Query(ctx, sql, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (sqlc.Book, error) {
+		var b sqlc.Book
+		err := row.Scan(bookFields(&b)...)
+		return b, err
+	})
+}
+
+// CountSearchResults counts the books matching input, ignoring sort and paging.
+func (s *Service) CountSearchResults(ctx context.Context, input SearchInput) (int64, error) {
+	count, _, err := s.countSearch(ctx, input)
+	return count, err
+}
+
+// countSearch counts the books matching input, falling back to fuzzy matching
+// when input allows it and the exact search finds nothing. It reports which
+// mode the count was taken in.
+func (s *Service) countSearch(ctx context.Context, input SearchInput) (int64, bool, error) {
+	count, err := s.countSearchResults(ctx, input, false)
+	if err != nil || count > 0 || !input.Fuzzy || input.Query == "" {
+		return count, false, err
+	}
+	count, err = s.countSearchResults(ctx, input, true)
+	return count, true, err
+}
+
+func (s *Service) countSearchResults(ctx context.Context, input SearchInput, fuzzy bool) (int64, error) {
+	q := newSearchQuery(input, fuzzy)
+	sql := fmt.Sprintf("SELECT COUNT(*)\n%s\n%s", searchFrom, q.whereClause())
+
+	var count int64
+	err := s.db.QueryRow(ctx, sql, q.args...).Scan(&count)
+	return count, err
+}
+
+// SearchBooksPage returns up to first books matching input, starting after
+// the row identified by the opaque cursor after. Limit and Offset on input are
+// ignored.
+func (s *Service) SearchBooksPage(ctx context.Context, input SearchInput, first int32, after string) (*pagination.Page[sqlc.Book], error) {
+	fuzzy, err := s.useFuzzy(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	sortBy, order := resolveSort(input)
+	if fuzzy {
+		// Ranks from the two match modes are not comparable.
+		sortBy += ":fuzzy"
+	}
+	cursor, err := pagination.Decode(after, sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	q := newSearchQuery(input, fuzzy)
+	expr := q.sortExpr(order)
+	if cursor != nil {
+		op := ">"
+		if order.desc {
+			op = "<"
+		}
+		q.where = append(q.where, fmt.Sprintf("(%s, b.id) %s (%s::text::%s, %s::uuid)",
+			expr, op, q.arg(cursor.Key), order.sqlType, q.arg(cursor.ID)))
+	}
+	sql := fmt.Sprintf("SELECT %s, (%s)::text\n%s\n%s\n%s\nLIMIT %s",
+		bookColumns, expr, searchFrom, q.whereClause(), q.orderBy(order), q.arg(first+1))
+
+	rows, err := s.db.Query(ctx, sql, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	type keyedBook struct {
+		book sqlc.Book
+		key  string
+	}
+	keyed, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (keyedBook, error) {
+		var kb keyedBook
+		err := row.Scan(append(bookFields(&kb.book), &kb.key)...)
+		return kb, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := pagination.NewPage(keyed, first, func(kb keyedBook) pagination.Cursor {
+		return pagination.Cursor{Sort: sortBy, Key: kb.key, ID: kb.book.ID}
+	})
+	result := &pagination.Page[sqlc.Book]{
+		Items:       make([]sqlc.Book, len(page.Items)),
+		Cursors:     page.Cursors,
+		HasNextPage: page.HasNextPage,
+	}
+	for i, kb := range page.Items {
+		result.Items[i] = kb.book
+	}
+	return result, nil
+}
+
+// ListBooksPage pages through every book in the given sort order.
+func (s *Service) ListBooksPage(ctx context.Context, sortBy string, first int32, after string) (*pagination.Page[sqlc.Book], error) {
+	return s.SearchBooksPage(ctx, SearchInput{SortBy: sortBy}, first, after)
+}
@@ -1,20 +1,24 @@
 package books
 
 import (
+	"book-nexus/internal/database"
 	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/deletion"
+	"book-nexus/internal/revisions"
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Service struct {
-	db      *pgxpool.Pool
+	db      database.DBTX
 	queries *sqlc.Queries
 }
 
-func NewService(db *pgxpool.Pool) *Service {
+func NewService(db database.DBTX) *Service {
 	return &Service{
 		db:      db,
 		queries: sqlc.New(db),
@@ -49,15 +53,25 @@ func (s *Service) CountBooks(ctx context.Context) (int64, error) {
 }
 
 type SearchInput struct {
-	Query       string
-	AuthorID    string // UUID as string, empty for no filter
-	PublisherID string // UUID as string, empty for no filter
-	SeriesID    string // UUID as string, empty for no filter
-	AuthorName  string
-	Genre       string
-	SortBy      string // Options: title_asc, title_desc, date_asc, date_desc, author
-	Limit       int32
-	Offset      int32
+	Query           string
+	AuthorID        string // UUID as string, empty for no filter
+	PublisherID     string // UUID as string, empty for no filter
+	SeriesID        string // UUID as string, empty for no filter
+	AuthorName      string
+	Genre           string
+	Genres          []string   // Genre names or slugs, matched per GenreMatch
+	GenreMatch      GenreMatch // Defaults to GenreMatchAny
+	Language        string
+	MinPages        *int32
+	MaxPages        *int32
+	PublishedAfter  *time.Time // Inclusive
+	PublishedBefore *time.Time // Inclusive
+	ISBN            string     // ISBN-10 or ISBN-13, hyphens and spaces allowed
+	InSeries        *bool
+	SortBy          string // Options: newest, title_asc, title_desc, date_asc, date_desc, author, relevance
+	Fuzzy           bool   // Fall back to similarity matching when nothing matches exactly
+	Limit           int32
+	Offset          int32
 }
 
 type SearchResult struct {
@@ -66,29 +80,12 @@ type SearchResult struct {
 }
 
 func (s *Service) SearchBooks(ctx context.Context, input SearchInput) (*SearchResult, error) {
-	books, err := s.queries.SearchBooks(ctx, sqlc.SearchBooksParams{
-		Column1: input.Query,
-		Column2: input.AuthorID,
-		Column3: input.PublisherID,
-		Column4: input.SeriesID,
-		Column5: input.AuthorName,
-		Column6: input.Genre,
-		Column7: input.SortBy,
-		Limit:   input.Limit,
-		Offset:  input.Offset,
-	})
+	count, fuzzy, err := s.countSearch(ctx, input)
 	if err != nil {
 		return nil, err
 	}
 
-	count, err := s.queries.CountSearchResults(ctx, sqlc.CountSearchResultsParams{
-		Column1: input.Query,
-		Column2: input.AuthorID,
-		Column3: input.PublisherID,
-		Column4: input.SeriesID,
-		Column5: input.AuthorName,
-		Column6: input.Genre,
-	})
+	books, err := s.searchBooks(ctx, input, fuzzy)
 	if err != nil {
 		return nil, err
 	}
@@ -111,12 +108,32 @@ func (s *Service) GetBooksBySeries(ctx context.Context, seriesID uuid.UUID) ([]s
 	return s.queries.GetBooksBySeries(ctx, pgtype.UUID{Bytes: seriesID, Valid: true})
 }
 
+func (s *Service) GetBooksByAuthorIDs(ctx context.Context, authorIDs []uuid.UUID) ([]sqlc.Book, error) {
+	return s.queries.GetBooksByAuthorIDs(ctx, authorIDs)
+}
+
+func (s *Service) GetBooksByPublisherIDs(ctx context.Context, publisherIDs []uuid.UUID) ([]sqlc.Book, error) {
+	return s.queries.GetBooksByPublisherIDs(ctx, publisherIDs)
+}
+
+func (s *Service) GetBooksBySeriesIDs(ctx context.Context, seriesIDs []uuid.UUID) ([]sqlc.Book, error) {
+	return s.queries.GetBooksBySeriesIDs(ctx, seriesIDs)
+}
+
+func (s *Service) GetBooksByGenreIDs(ctx context.Context, genreIDs []uuid.UUID) ([]sqlc.GetBooksByGenreIDsRow, error) {
+	return s.queries.GetBooksByGenreIDs(ctx, genreIDs)
+}
+
+func (s *Service) GetBooksByTagIDs(ctx context.Context, tagIDs []uuid.UUID) ([]sqlc.GetBooksByTagIDsRow, error) {
+	return s.queries.GetBooksByTagIDs(ctx, tagIDs)
+}
+
 type CreateBookInput struct {
 	Title          string
 	Subtitle       *string
 	AuthorID       uuid.UUID
 	PublisherID    *uuid.UUID
-	PublishedDate  *string
+	PublishedDate  *time.Time
 	ISBN10         *string
 	ISBN13         *string
 	Pages          *int32
@@ -129,22 +146,102 @@ type CreateBookInput struct {
 	ImageURL       *string
 }
 
+// CreateBook adds a book. The author, and the publisher and series when set,
+// must be live.
 func (s *Service) CreateBook(ctx context.Context, input CreateBookInput) (*sqlc.Book, error) {
-	var publisherID, seriesID pgtype.UUID
+	if err := validateBook(input.Title, input.Pages, input.SeriesPosition); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	publisherID, seriesID, err := lockRelations(ctx, q, input.AuthorID, input.PublisherID, input.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	book, err := q.CreateBook(ctx, sqlc.CreateBookParams{
+		Title:          input.Title,
+		Subtitle:       input.Subtitle,
+		AuthorID:       input.AuthorID,
+		PublisherID:    publisherID,
+		PublishedDate:  input.PublishedDate,
+		Isbn10:         input.ISBN10,
+		Isbn13:         input.ISBN13,
+		Pages:          input.Pages,
+		Language:       input.Language,
+		Description:    input.Description,
+		SeriesID:       seriesID,
+		SeriesPosition: input.SeriesPosition,
+		Genres:         input.Genres,
+		Tags:           input.Tags,
+		ImageUrl:       input.ImageURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+type UpdateBookInput struct {
+	ID             uuid.UUID
+	Title          string
+	Subtitle       *string
+	AuthorID       uuid.UUID
+	PublisherID    *uuid.UUID
+	PublishedDate  *time.Time
+	ISBN10         *string
+	ISBN13         *string
+	Pages          *int32
+	Language       *string
+	Description    *string
+	SeriesID       *uuid.UUID
+	SeriesPosition *int32
+	Genres         *string
+	Tags           *string
+	ImageURL       *string
+}
 
-	if input.PublisherID != nil {
-		publisherID = pgtype.UUID{Bytes: *input.PublisherID, Valid: true}
+// UpdateBook replaces a book's fields, saving the row as it was as a new
+// revision. The author, and the publisher and series when set, must be live.
+func (s *Service) UpdateBook(ctx context.Context, input UpdateBookInput) (*sqlc.Book, error) {
+	if err := validateBook(input.Title, input.Pages, input.SeriesPosition); err != nil {
+		return nil, err
 	}
-	if input.SeriesID != nil {
-		seriesID = pgtype.UUID{Bytes: *input.SeriesID, Valid: true}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	before, err := q.GetBookByIDForUpdate(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	publisherID, seriesID, err := lockRelations(ctx, q, input.AuthorID, input.PublisherID, input.SeriesID)
+	if err != nil {
+		return nil, err
 	}
 
-	book, err := s.queries.CreateBook(ctx, sqlc.CreateBookParams{
+	if err := revisions.Save(ctx, q, revisions.TypeBook, before.ID, before); err != nil {
+		return nil, err
+	}
+	book, err := q.UpdateBook(ctx, sqlc.UpdateBookParams{
+		ID:             input.ID,
 		Title:          input.Title,
 		Subtitle:       input.Subtitle,
 		AuthorID:       input.AuthorID,
 		PublisherID:    publisherID,
-		PublishedDate:  nil, // TODO: parse date string
+		PublishedDate:  input.PublishedDate,
 		Isbn10:         input.ISBN10,
 		Isbn13:         input.ISBN13,
 		Pages:          input.Pages,
@@ -159,9 +256,103 @@ func (s *Service) CreateBook(ctx context.Context, input CreateBookInput) (*sqlc.
 	if err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return &book, nil
 }
 
+// validateBook checks the fields CreateBook and UpdateBook validate before
+// any query.
+func validateBook(title string, pages, seriesPosition *int32) error {
+	if title == "" {
+		return errors.New("title is required")
+	}
+	if pages != nil && *pages <= 0 {
+		return errors.New("pages must be positive")
+	}
+	if seriesPosition != nil && *seriesPosition <= 0 {
+		return errors.New("series position must be positive")
+	}
+	return nil
+}
+
+// lockRelations locks a book's author, publisher and series, so none can be
+// trashed before the book is written, and fails unless each that is set is
+// live. It returns the optional IDs as written to the books row.
+func lockRelations(ctx context.Context, q *sqlc.Queries, authorID uuid.UUID, publisherID, seriesID *uuid.UUID) (publisher, series pgtype.UUID, err error) {
+	deletedAt, err := q.LockAuthor(ctx, authorID)
+	if err := deletion.Live("author", authorID, deletedAt, err); err != nil {
+		return publisher, series, err
+	}
+	if publisherID != nil {
+		deletedAt, err := q.LockPublisher(ctx, *publisherID)
+		if err := deletion.Live("publisher", *publisherID, deletedAt, err); err != nil {
+			return publisher, series, err
+		}
+		publisher = pgtype.UUID{Bytes: *publisherID, Valid: true}
+	}
+	if seriesID != nil {
+		deletedAt, err := q.LockSeries(ctx, *seriesID)
+		if err := deletion.Live("series", *seriesID, deletedAt, err); err != nil {
+			return publisher, series, err
+		}
+		series = pgtype.UUID{Bytes: *seriesID, Valid: true}
+	}
+	return publisher, series, nil
+}
+
+// RevertBook updates a book back to the fields saved in one of its
+// revisions. It goes through UpdateBook, so the current fields are saved as a
+// revision in turn and the snapshot is validated like any other update.
+func (s *Service) RevertBook(ctx context.Context, id, revisionID uuid.UUID) (*sqlc.Book, error) {
+	var snapshot sqlc.Book
+	if err := revisions.Load(ctx, s.queries, revisions.TypeBook, id, revisionID, &snapshot); err != nil {
+		return nil, err
+	}
+	return s.UpdateBook(ctx, UpdateBookInput{
+		ID:             id,
+		Title:          snapshot.Title,
+		Subtitle:       snapshot.Subtitle,
+		AuthorID:       snapshot.AuthorID,
+		PublisherID:    optionalUUID(snapshot.PublisherID),
+		PublishedDate:  snapshot.PublishedDate,
+		ISBN10:         snapshot.Isbn10,
+		ISBN13:         snapshot.Isbn13,
+		Pages:          snapshot.Pages,
+		Language:       snapshot.Language,
+		Description:    snapshot.Description,
+		SeriesID:       optionalUUID(snapshot.SeriesID),
+		SeriesPosition: snapshot.SeriesPosition,
+		Genres:         snapshot.Genres,
+		Tags:           snapshot.Tags,
+		ImageURL:       snapshot.ImageUrl,
+	})
+}
+
+func optionalUUID(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
+
+// DeleteBook moves a book to the trash.
 func (s *Service) DeleteBook(ctx context.Context, id uuid.UUID) error {
-	return s.queries.DeleteBook(ctx, id)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	deletedAt, err := q.LockBook(ctx, id)
+	if err := deletion.Live("book", id, deletedAt, err); err != nil {
+		return err
+	}
+	if err := q.TrashBook(ctx, id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,120 @@
+package books
+
+import (
+	"book-nexus/internal/database/dbtest"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/deletion"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+func TestUpdateBookValidatesBeforeWriting(t *testing.T) {
+	zero := int32(0)
+	cases := map[string]UpdateBookInput{
+		"empty title":     {ID: uuid.New(), AuthorID: uuid.New()},
+		"zero pages":      {ID: uuid.New(), Title: "Dune", AuthorID: uuid.New(), Pages: &zero},
+		"zero series pos": {ID: uuid.New(), Title: "Dune", AuthorID: uuid.New(), SeriesPosition: &zero},
+	}
+	// A service without a pool panics if it gets as far as a query.
+	s := &Service{}
+	for name, input := range cases {
+		if _, err := s.UpdateBook(context.Background(), input); err == nil {
+			t.Fatalf("%s: expected a validation error", name)
+		}
+		create := CreateBookInput{Title: input.Title, AuthorID: input.AuthorID, Pages: input.Pages, SeriesPosition: input.SeriesPosition}
+		if _, err := s.CreateBook(context.Background(), create); err == nil {
+			t.Fatalf("%s: expected CreateBook to fail validation", name)
+		}
+	}
+}
+
+func TestCreateBookNeedsLiveRelations(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+	s := NewService(pool)
+
+	author, err := q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Name: "Frank Herbert"})
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	publisher, err := q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Name: "Chilton Books"})
+	if err != nil {
+		t.Fatalf("create publisher: %v", err)
+	}
+	series, err := q.CreateSeries(ctx, sqlc.CreateSeriesParams{Name: "Dune Chronicles"})
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+	input := CreateBookInput{Title: "Dune", AuthorID: author.ID, PublisherID: &publisher.ID, SeriesID: &series.ID}
+	if _, err := s.CreateBook(ctx, input); err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+
+	missing := uuid.New()
+	if _, err := s.CreateBook(ctx, CreateBookInput{Title: "Dune", AuthorID: missing}); !errors.Is(err, deletion.ErrNotFound) {
+		t.Fatalf("expected an unknown author to be rejected, got %v", err)
+	}
+
+	// Each relation in turn goes to the trash; the book can be created
+	// without it but not with it.
+	if err := q.TrashSeries(ctx, series.ID); err != nil {
+		t.Fatalf("trash series: %v", err)
+	}
+	if _, err := s.CreateBook(ctx, input); !errors.Is(err, deletion.ErrNotFound) {
+		t.Fatalf("expected a book in a trashed series to be rejected, got %v", err)
+	}
+	input.SeriesID = nil
+	if err := q.TrashPublisher(ctx, publisher.ID); err != nil {
+		t.Fatalf("trash publisher: %v", err)
+	}
+	if _, err := s.CreateBook(ctx, input); !errors.Is(err, deletion.ErrNotFound) {
+		t.Fatalf("expected a book from a trashed publisher to be rejected, got %v", err)
+	}
+	input.PublisherID = nil
+	if _, err := s.CreateBook(ctx, input); err != nil {
+		t.Fatalf("create book without the trashed relations: %v", err)
+	}
+	if err := q.TrashAuthor(ctx, author.ID); err != nil {
+		t.Fatalf("trash author: %v", err)
+	}
+	if _, err := s.CreateBook(ctx, input); !errors.Is(err, deletion.ErrNotFound) {
+		t.Fatalf("expected a book by a trashed author to be rejected, got %v", err)
+	}
+}
+
+func TestRevisionSnapshotKeepsOptionalIDs(t *testing.T) {
+	publisherID := uuid.New()
+	book := sqlc.Book{
+		ID:          uuid.New(),
+		Title:       "Dune",
+		AuthorID:    uuid.New(),
+		PublisherID: pgtype.UUID{Bytes: publisherID, Valid: true},
+	}
+
+	b, err := json.Marshal(book)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var snapshot sqlc.Book
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if got := optionalUUID(snapshot.PublisherID); got == nil || *got != publisherID {
+		t.Fatalf("expected publisher %s, got %v", publisherID, got)
+	}
+	if got := optionalUUID(snapshot.SeriesID); got != nil {
+		t.Fatalf("expected no series, got %s", got)
+	}
+}
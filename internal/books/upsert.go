@@ -0,0 +1,758 @@
+package books
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/revisions"
+	"book-nexus/internal/slug"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Match is what UpsertBooks matches rows to existing books on.
+type Match string
+
+const (
+	MatchISBN13 Match = "ISBN13"
+	MatchISBN10 Match = "ISBN10"
+	// MatchTitleAuthor matches the exact title by the same author.
+	MatchTitleAuthor Match = "TITLE_AUTHOR"
+)
+
+// MaxUpsertBooks is the most books one UpsertBooks call takes.
+const MaxUpsertBooks = 500
+
+// Ref points at a related entity by ID or, when ID is nil, by exact name.
+// Names that do not exist yet are created.
+type Ref struct {
+	ID   *uuid.UUID
+	Name string
+}
+
+// UpsertBookInput is one book to create or update. When the book exists,
+// nil fields keep their current values.
+type UpsertBookInput struct {
+	Title          string
+	Subtitle       *string
+	Author         Ref
+	Publisher      *Ref
+	PublishedDate  *time.Time
+	ISBN10         *string
+	ISBN13         *string
+	Pages          *int32
+	Language       *string
+	Description    *string
+	Series         *Ref
+	SeriesPosition *int32
+	Genres         *string
+	Tags           *string
+	ImageURL       *string
+}
+
+type UpsertStatus string
+
+const (
+	UpsertCreated   UpsertStatus = "CREATED"
+	UpsertUpdated   UpsertStatus = "UPDATED"
+	UpsertUnchanged UpsertStatus = "UNCHANGED"
+	UpsertError     UpsertStatus = "ERROR"
+)
+
+// UpsertResult is the outcome for one row.
+type UpsertResult struct {
+	Status UpsertStatus
+	// Book is the book as it is now, unless the row was rejected.
+	Book *sqlc.Book
+	// Before is an updated book as it was.
+	Before *sqlc.Book
+	// Error says why the row was rejected.
+	Error string
+}
+
+// UpsertBooksResult is what UpsertBooks did, with Rows in input order.
+type UpsertBooksResult struct {
+	Rows []UpsertResult
+	// Authors, Publishers and Series were created for names that did not
+	// exist yet.
+	Authors    []sqlc.Author
+	Publishers []sqlc.Publisher
+	Series     []sqlc.Series
+}
+
+// UpsertBooks creates the rows that match no existing book and updates the
+// ones that do, saving a revision of each updated book. Rows that are
+// invalid, reference missing or trashed entities, or would clash with
+// another book are rejected with a reason while the rest are written, all in
+// one transaction. Related entities are resolved and books written with a
+// fixed number of round trips, however many rows there are.
+func (s *Service) UpsertBooks(ctx context.Context, rows []UpsertBookInput, match Match) (*UpsertBooksResult, error) {
+	if len(rows) > MaxUpsertBooks {
+		return nil, fmt.Errorf("at most %d books can be upserted at once, got %d", MaxUpsertBooks, len(rows))
+	}
+	switch match {
+	case MatchISBN13, MatchISBN10, MatchTitleAuthor:
+	default:
+		return nil, fmt.Errorf("unknown match %q", match)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	u := newUpsert(tx, rows, match)
+	for i, row := range rows {
+		if err := validateUpsert(row, match); err != nil {
+			u.fail(i, err)
+		}
+	}
+	for _, find := range []func(context.Context) error{u.authors.find, u.publishers.find, u.series.find} {
+		if err := find(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.matchBooks(ctx); err != nil {
+		return nil, err
+	}
+	// Entities are created only now, for the rows that are still going to
+	// be written.
+	for _, create := range []func(context.Context) error{u.authors.create, u.publishers.create, u.series.create} {
+		if err := create(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.write(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	u.result.Authors = u.authors.created
+	u.result.Publishers = u.publishers.created
+	u.result.Series = u.series.created
+	return u.result, nil
+}
+
+func validateUpsert(row UpsertBookInput, match Match) error {
+	if row.Title == "" {
+		return errors.New("title is required")
+	}
+	if row.Author.ID == nil && row.Author.Name == "" {
+		return errors.New("author ID or name is required")
+	}
+	if row.Publisher != nil && row.Publisher.ID == nil && row.Publisher.Name == "" {
+		return errors.New("publisher name is empty")
+	}
+	if row.Series != nil && row.Series.ID == nil && row.Series.Name == "" {
+		return errors.New("series name is empty")
+	}
+	if row.Pages != nil && *row.Pages <= 0 {
+		return errors.New("pages must be positive")
+	}
+	if row.SeriesPosition != nil && *row.SeriesPosition <= 0 {
+		return errors.New("series position must be positive")
+	}
+	if row.ISBN10 != nil && len(*row.ISBN10) > 10 {
+		return errors.New("ISBN-10 is longer than 10 characters")
+	}
+	if row.ISBN13 != nil && len(*row.ISBN13) > 13 {
+		return errors.New("ISBN-13 is longer than 13 characters")
+	}
+	switch {
+	case match == MatchISBN13 && (row.ISBN13 == nil || *row.ISBN13 == ""):
+		return errors.New("ISBN-13 is required to match on it")
+	case match == MatchISBN10 && (row.ISBN10 == nil || *row.ISBN10 == ""):
+		return errors.New("ISBN-10 is required to match on it")
+	}
+	return nil
+}
+
+// upsert is the state of one UpsertBooks call.
+type upsert struct {
+	db     database.DBTX
+	q      *sqlc.Queries
+	rows   []UpsertBookInput
+	match  Match
+	result *UpsertBooksResult
+
+	authors    *relation[sqlc.Author]
+	publishers *relation[sqlc.Publisher]
+	series     *relation[sqlc.Series]
+	// targets holds each row's existing book, nil for new books.
+	targets []*sqlc.Book
+}
+
+func newUpsert(db database.DBTX, rows []UpsertBookInput, match Match) *upsert {
+	q := sqlc.New(db)
+	u := &upsert{
+		db:      db,
+		q:       q,
+		rows:    rows,
+		match:   match,
+		result:  &UpsertBooksResult{Rows: make([]UpsertResult, len(rows))},
+		targets: make([]*sqlc.Book, len(rows)),
+	}
+
+	authorRefs := make([]*Ref, len(rows))
+	publisherRefs := make([]*Ref, len(rows))
+	seriesRefs := make([]*Ref, len(rows))
+	for i := range rows {
+		authorRefs[i] = &rows[i].Author
+		publisherRefs[i] = rows[i].Publisher
+		seriesRefs[i] = rows[i].Series
+	}
+	u.authors = &relation[sqlc.Author]{
+		u: u, noun: "author", refs: authorRefs,
+		lockByIDs: q.LockAuthorsByIDs, getByNames: q.GetAuthorsByNamesForUpdate, listSlugs: q.ListAuthorSlugsWithBases,
+		createWithSlugs: func(ctx context.Context, names, slugs []string) ([]sqlc.Author, error) {
+			return q.CreateAuthorsWithSlugs(ctx, sqlc.CreateAuthorsWithSlugsParams{Names: names, Slugs: slugs})
+		},
+		key: func(a sqlc.Author) (uuid.UUID, string, *time.Time) { return a.ID, a.Name, a.DeletedAt },
+	}
+	u.publishers = &relation[sqlc.Publisher]{
+		u: u, noun: "publisher", refs: publisherRefs,
+		lockByIDs: q.LockPublishersByIDs, getByNames: q.GetPublishersByNamesForUpdate, listSlugs: q.ListPublisherSlugsWithBases,
+		createWithSlugs: func(ctx context.Context, names, slugs []string) ([]sqlc.Publisher, error) {
+			return q.CreatePublishersWithSlugs(ctx, sqlc.CreatePublishersWithSlugsParams{Names: names, Slugs: slugs})
+		},
+		key: func(p sqlc.Publisher) (uuid.UUID, string, *time.Time) { return p.ID, p.Name, p.DeletedAt },
+	}
+	u.series = &relation[sqlc.Series]{
+		u: u, noun: "series", refs: seriesRefs,
+		lockByIDs: q.LockSeriesByIDs, getByNames: q.GetSeriesByNamesForUpdate, listSlugs: q.ListSeriesSlugsWithBases,
+		createWithSlugs: func(ctx context.Context, names, slugs []string) ([]sqlc.Series, error) {
+			return q.CreateSeriesWithSlugs(ctx, sqlc.CreateSeriesWithSlugsParams{Names: names, Slugs: slugs})
+		},
+		key: func(s sqlc.Series) (uuid.UUID, string, *time.Time) { return s.ID, s.Name, s.DeletedAt },
+	}
+	return u
+}
+
+// fail rejects row i.
+func (u *upsert) fail(i int, err error) {
+	u.result.Rows[i] = UpsertResult{Status: UpsertError, Error: err.Error()}
+}
+
+// ok reports whether row i has not been rejected.
+func (u *upsert) ok(i int) bool {
+	return u.result.Rows[i].Status != UpsertError
+}
+
+// relation resolves the author, publisher or series references of the
+// rows.
+type relation[T any] struct {
+	u    *upsert
+	noun string
+	// refs holds each row's reference, nil for none.
+	refs []*Ref
+
+	lockByIDs       func(context.Context, []uuid.UUID) ([]T, error)
+	getByNames      func(context.Context, []string) ([]T, error)
+	listSlugs       func(ctx context.Context, bases []string) ([]*string, error)
+	createWithSlugs func(ctx context.Context, names, slugs []string) ([]T, error)
+	key             func(T) (id uuid.UUID, name string, deletedAt *time.Time)
+
+	// ids holds each row's resolved ID, uuid.Nil until resolved.
+	ids []uuid.UUID
+	// missing maps names that do not exist yet to the rows using them.
+	missing map[string][]int
+	created []T
+}
+
+// find resolves the references to existing entities, locking them, and
+// rejects rows whose entity is missing or trashed. Names that do not exist
+// yet are left to create.
+func (r *relation[T]) find(ctx context.Context) error {
+	r.ids = make([]uuid.UUID, len(r.refs))
+	r.missing = make(map[string][]int)
+
+	var ids []uuid.UUID
+	var names []string
+	for i, ref := range r.refs {
+		switch {
+		case ref == nil || !r.u.ok(i):
+		case ref.ID != nil:
+			ids = append(ids, *ref.ID)
+		default:
+			names = append(names, ref.Name)
+		}
+	}
+
+	byID := make(map[uuid.UUID]T)
+	byName := make(map[string]T)
+	if len(ids) > 0 {
+		found, err := r.lockByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, e := range found {
+			id, _, _ := r.key(e)
+			byID[id] = e
+		}
+	}
+	if len(names) > 0 {
+		found, err := r.getByNames(ctx, names)
+		if err != nil {
+			return err
+		}
+		for _, e := range found {
+			_, name, _ := r.key(e)
+			byName[name] = e
+		}
+	}
+
+	for i, ref := range r.refs {
+		if ref == nil || !r.u.ok(i) {
+			continue
+		}
+		var (
+			e     T
+			found bool
+		)
+		if ref.ID != nil {
+			if e, found = byID[*ref.ID]; !found {
+				r.u.fail(i, fmt.Errorf("%s %s not found", r.noun, *ref.ID))
+				continue
+			}
+		} else if e, found = byName[ref.Name]; !found {
+			r.missing[ref.Name] = append(r.missing[ref.Name], i)
+			continue
+		}
+		id, name, deletedAt := r.key(e)
+		if deletedAt != nil {
+			r.u.fail(i, fmt.Errorf("%s %q is in the trash; restore it first", r.noun, name))
+			continue
+		}
+		r.ids[i] = id
+	}
+	return nil
+}
+
+// create inserts the missing names that rows still being written use, each
+// with a slug made from the name. A name that another transaction created
+// since find is used as it is now, unless it has been trashed, which
+// rejects its rows. Names whose slug was taken meanwhile are created without
+// one.
+func (r *relation[T]) create(ctx context.Context) error {
+	var names []string
+	for name, rows := range r.missing {
+		if slices.ContainsFunc(rows, r.u.ok) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	// A stable order keeps concurrent upserts from deadlocking.
+	sort.Strings(names)
+
+	bases := make([]string, len(names))
+	for i, name := range names {
+		bases[i] = slug.Make(name)
+	}
+	listed, err := r.listSlugs(ctx, bases)
+	if err != nil {
+		return err
+	}
+	taken := slug.Taken(listed)
+	slugs := make([]string, len(names))
+	for i, base := range bases {
+		slugs[i] = slug.Unique(taken, base)
+	}
+	created, err := r.createWithSlugs(ctx, names, slugs)
+	if err != nil {
+		return err
+	}
+	names = r.resolve(names, created)
+	r.created = created
+	if len(names) == 0 {
+		return nil
+	}
+
+	// Names left out clashed with a row committed since find.
+	existing, err := r.getByNames(ctx, names)
+	if err != nil {
+		return err
+	}
+	names = r.resolve(names, existing)
+	if len(names) == 0 {
+		return nil
+	}
+
+	// What is still missing only lost its slug.
+	created, err = r.createWithSlugs(ctx, names, make([]string, len(names)))
+	if err != nil {
+		return err
+	}
+	names = r.resolve(names, created)
+	r.created = append(r.created, created...)
+	for _, name := range names {
+		for _, i := range r.missing[name] {
+			r.u.fail(i, fmt.Errorf("%s %q was changed by another request at the same time; try again", r.noun, name))
+		}
+	}
+	return nil
+}
+
+// resolve sets the IDs of the rows using the given entities, rejecting the
+// rows of trashed ones, and returns the names that are still unresolved.
+func (r *relation[T]) resolve(names []string, entities []T) []string {
+	for _, e := range entities {
+		id, name, deletedAt := r.key(e)
+		for _, i := range r.missing[name] {
+			if deletedAt != nil {
+				r.u.fail(i, fmt.Errorf("%s %q is in the trash; restore it first", r.noun, name))
+				continue
+			}
+			r.ids[i] = id
+		}
+		names = slices.DeleteFunc(names, func(n string) bool { return n == name })
+	}
+	return names
+}
+
+// matchKey is what row i is matched on. Under MatchTitleAuthor, a book by an
+// author who does not exist yet has a key no existing book has, but still
+// catches duplicate rows.
+func (u *upsert) matchKey(i int) string {
+	row := u.rows[i]
+	switch u.match {
+	case MatchISBN13:
+		return *row.ISBN13
+	case MatchISBN10:
+		return *row.ISBN10
+	}
+	if id := u.authors.ids[i]; id != uuid.Nil {
+		return titleAuthorKey(row.Title, id)
+	}
+	return row.Title + "\x00name:" + row.Author.Name
+}
+
+func titleAuthorKey(title string, authorID uuid.UUID) string {
+	return title + "\x00" + authorID.String()
+}
+
+// matchBooks finds and locks each row's existing book, and rejects rows that
+// match several books, a trashed book or the same book as an earlier row, or
+// that would take an ISBN-13 another book has.
+func (u *upsert) matchBooks(ctx context.Context) error {
+	var isbn13s, isbn10s, titles []string
+	var authorIDs []uuid.UUID
+	for i, row := range u.rows {
+		if !u.ok(i) {
+			continue
+		}
+		if row.ISBN13 != nil {
+			isbn13s = append(isbn13s, *row.ISBN13)
+		}
+		switch {
+		case u.match == MatchISBN10:
+			isbn10s = append(isbn10s, *row.ISBN10)
+		case u.match == MatchTitleAuthor && u.authors.ids[i] != uuid.Nil:
+			titles = append(titles, row.Title)
+			authorIDs = append(authorIDs, u.authors.ids[i])
+		}
+	}
+
+	// The books holding the ISBN-13s being written, trashed or not, are
+	// needed to catch clashes, and under MatchISBN13 they are the matches.
+	byISBN13 := make(map[string]sqlc.Book)
+	if len(isbn13s) > 0 {
+		books, err := u.q.GetBooksByISBN13sForUpdate(ctx, isbn13s)
+		if err != nil {
+			return err
+		}
+		for _, b := range books {
+			byISBN13[*b.Isbn13] = b
+		}
+	}
+
+	candidates := make(map[string][]sqlc.Book)
+	switch u.match {
+	case MatchISBN13:
+		for isbn, b := range byISBN13 {
+			candidates[isbn] = []sqlc.Book{b}
+		}
+	case MatchISBN10:
+		if len(isbn10s) > 0 {
+			books, err := u.q.GetBooksByISBN10sForUpdate(ctx, isbn10s)
+			if err != nil {
+				return err
+			}
+			for _, b := range books {
+				candidates[*b.Isbn10] = append(candidates[*b.Isbn10], b)
+			}
+		}
+	case MatchTitleAuthor:
+		if len(titles) > 0 {
+			books, err := u.q.GetBooksByTitleAuthorsForUpdate(ctx, sqlc.GetBooksByTitleAuthorsForUpdateParams{
+				Titles:    titles,
+				AuthorIds: authorIDs,
+			})
+			if err != nil {
+				return err
+			}
+			for _, b := range books {
+				key := titleAuthorKey(b.Title, b.AuthorID)
+				candidates[key] = append(candidates[key], b)
+			}
+		}
+	}
+
+	seen := make(map[string]int)
+	seenISBN13 := make(map[string]int)
+	for i, row := range u.rows {
+		if !u.ok(i) {
+			continue
+		}
+		key := u.matchKey(i)
+		if j, dup := seen[key]; dup {
+			u.fail(i, fmt.Errorf("matches the same book as row %d", j))
+			continue
+		}
+		seen[key] = i
+
+		var target *sqlc.Book
+		switch matches := candidates[key]; len(matches) {
+		case 0:
+		case 1:
+			target = &matches[0]
+		default:
+			u.fail(i, fmt.Errorf("matches %d books", len(matches)))
+			continue
+		}
+		if target != nil && target.DeletedAt != nil {
+			u.fail(i, fmt.Errorf("book %s is in the trash; restore it first", target.ID))
+			continue
+		}
+
+		if row.ISBN13 != nil {
+			if j, dup := seenISBN13[*row.ISBN13]; dup {
+				u.fail(i, fmt.Errorf("has the same ISBN-13 as row %d", j))
+				continue
+			}
+			seenISBN13[*row.ISBN13] = i
+			if owner, taken := byISBN13[*row.ISBN13]; taken && (target == nil || owner.ID != target.ID) {
+				u.fail(i, fmt.Errorf("ISBN-13 %s already belongs to book %s", *row.ISBN13, owner.ID))
+				continue
+			}
+		}
+		u.targets[i] = target
+	}
+	return nil
+}
+
+// write creates and updates the books of the rows that were not rejected,
+// one batch for each kind of statement. Should a statement fail on its
+// row's data, say an ISBN-13 another request took meanwhile, the batches are
+// rolled back and the rows written one at a time instead, so that only the
+// failing ones are rejected.
+func (u *upsert) write(ctx context.Context) error {
+	var createRows, updateRows []int
+	for i := range u.rows {
+		if !u.ok(i) {
+			continue
+		}
+		target := u.targets[i]
+		if target == nil {
+			createRows = append(createRows, i)
+			continue
+		}
+		if unchanged(*target, u.updateParams(i, *target)) {
+			u.result.Rows[i] = UpsertResult{Status: UpsertUnchanged, Book: target}
+			continue
+		}
+		updateRows = append(updateRows, i)
+	}
+
+	err := u.writeRows(ctx, updateRows, createRows)
+	if err == nil || rowError(err) == nil {
+		return err
+	}
+	for _, i := range updateRows {
+		if err := u.writeRows(ctx, []int{i}, nil); rowError(err) != nil {
+			u.fail(i, rowError(err))
+		} else if err != nil {
+			return err
+		}
+	}
+	for _, i := range createRows {
+		if err := u.writeRows(ctx, nil, []int{i}); rowError(err) != nil {
+			u.fail(i, rowError(err))
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRows updates and creates the books of the given rows, saving a
+// revision of each updated book, in a savepoint that is rolled back unless
+// every statement succeeds. The rows' results are set only then.
+func (u *upsert) writeRows(ctx context.Context, updateRows, createRows []int) error {
+	sp, err := u.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer sp.Rollback(ctx)
+	q := u.q.WithTx(sp)
+
+	updates := make([]sqlc.UpdateBooksParams, len(updateRows))
+	snapshots := make(map[uuid.UUID]any, len(updateRows))
+	for j, i := range updateRows {
+		updates[j] = u.updateParams(i, *u.targets[i])
+		snapshots[u.targets[i].ID] = *u.targets[i]
+	}
+	creates := make([]sqlc.CreateBooksParams, len(createRows))
+	for j, i := range createRows {
+		creates[j] = u.createParams(i)
+	}
+
+	if err := revisions.SaveAll(ctx, q, revisions.TypeBook, snapshots); err != nil {
+		return err
+	}
+
+	results := make(map[int]UpsertResult, len(updateRows)+len(createRows))
+	var batchErr error
+	if len(updates) > 0 {
+		q.UpdateBooks(ctx, updates).QueryRow(func(j int, book sqlc.Book, err error) {
+			if err != nil {
+				batchErr = cmp.Or(batchErr, err)
+				return
+			}
+			i := updateRows[j]
+			results[i] = UpsertResult{Status: UpsertUpdated, Book: &book, Before: u.targets[i]}
+		})
+	}
+	if len(creates) > 0 && batchErr == nil {
+		q.CreateBooks(ctx, creates).QueryRow(func(j int, book sqlc.Book, err error) {
+			if err != nil {
+				batchErr = cmp.Or(batchErr, err)
+				return
+			}
+			results[createRows[j]] = UpsertResult{Status: UpsertCreated, Book: &book}
+		})
+	}
+	if batchErr != nil {
+		return batchErr
+	}
+	if err := sp.Commit(ctx); err != nil {
+		return err
+	}
+	for i, res := range results {
+		u.result.Rows[i] = res
+	}
+	return nil
+}
+
+// rowError is why a statement failed when the failure is down to the data
+// it wrote, such as a unique or check constraint, and nil otherwise.
+func rowError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || (!strings.HasPrefix(pgErr.Code, "22") && !strings.HasPrefix(pgErr.Code, "23")) {
+		return nil
+	}
+	if pgErr.Detail != "" {
+		return fmt.Errorf("%s: %s", pgErr.Message, pgErr.Detail)
+	}
+	return errors.New(pgErr.Message)
+}
+
+func (u *upsert) createParams(i int) sqlc.CreateBooksParams {
+	row := u.rows[i]
+	return sqlc.CreateBooksParams{
+		Title:          row.Title,
+		Subtitle:       row.Subtitle,
+		AuthorID:       u.authors.ids[i],
+		PublisherID:    u.publishers.id(i),
+		PublishedDate:  row.PublishedDate,
+		Isbn10:         row.ISBN10,
+		Isbn13:         row.ISBN13,
+		Pages:          row.Pages,
+		Language:       row.Language,
+		Description:    row.Description,
+		SeriesID:       u.series.id(i),
+		SeriesPosition: row.SeriesPosition,
+		Genres:         row.Genres,
+		Tags:           row.Tags,
+		ImageUrl:       row.ImageURL,
+	}
+}
+
+// updateParams merges row i into b, keeping b's values for the fields the
+// row leaves out.
+func (u *upsert) updateParams(i int, b sqlc.Book) sqlc.UpdateBooksParams {
+	row := u.rows[i]
+	params := sqlc.UpdateBooksParams{
+		ID:             b.ID,
+		Title:          row.Title,
+		Subtitle:       cmp.Or(row.Subtitle, b.Subtitle),
+		AuthorID:       u.authors.ids[i],
+		PublisherID:    b.PublisherID,
+		PublishedDate:  cmp.Or(row.PublishedDate, b.PublishedDate),
+		Isbn10:         cmp.Or(row.ISBN10, b.Isbn10),
+		Isbn13:         cmp.Or(row.ISBN13, b.Isbn13),
+		Pages:          cmp.Or(row.Pages, b.Pages),
+		Language:       cmp.Or(row.Language, b.Language),
+		Description:    cmp.Or(row.Description, b.Description),
+		SeriesID:       b.SeriesID,
+		SeriesPosition: cmp.Or(row.SeriesPosition, b.SeriesPosition),
+		Genres:         cmp.Or(row.Genres, b.Genres),
+		Tags:           cmp.Or(row.Tags, b.Tags),
+		ImageUrl:       cmp.Or(row.ImageURL, b.ImageUrl),
+	}
+	if row.Publisher != nil {
+		params.PublisherID = u.publishers.id(i)
+	}
+	if row.Series != nil {
+		params.SeriesID = u.series.id(i)
+	}
+	return params
+}
+
+// id is row i's resolved ID as a nullable column.
+func (r *relation[T]) id(i int) pgtype.UUID {
+	if r.ids[i] == uuid.Nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: r.ids[i], Valid: true}
+}
+
+// unchanged reports whether the update would leave b as it is.
+func unchanged(b sqlc.Book, p sqlc.UpdateBooksParams) bool {
+	return b.Title == p.Title &&
+		equal(b.Subtitle, p.Subtitle) &&
+		b.AuthorID == p.AuthorID &&
+		b.PublisherID == p.PublisherID &&
+		sameDate(b.PublishedDate, p.PublishedDate) &&
+		equal(b.Isbn10, p.Isbn10) &&
+		equal(b.Isbn13, p.Isbn13) &&
+		equal(b.Pages, p.Pages) &&
+		equal(b.Language, p.Language) &&
+		equal(b.Description, p.Description) &&
+		b.SeriesID == p.SeriesID &&
+		equal(b.SeriesPosition, p.SeriesPosition) &&
+		equal(b.Genres, p.Genres) &&
+		equal(b.Tags, p.Tags) &&
+		equal(b.ImageUrl, p.ImageUrl)
+}
+
+func equal[T comparable](a, b *T) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func sameDate(a, b *time.Time) bool {
+	return a == nil && b == nil || a != nil && b != nil && a.Equal(*b)
+}
@@ -0,0 +1,137 @@
+package books
+
+import (
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestValidateUpsert(t *testing.T) {
+	isbn13 := "9780441013593"
+	long := "97804410135930"
+	zero := int32(0)
+	author := Ref{Name: "Frank Herbert"}
+
+	valid := map[string]struct {
+		row   UpsertBookInput
+		match Match
+	}{
+		"isbn13":          {UpsertBookInput{Title: "Dune", Author: author, ISBN13: &isbn13}, MatchISBN13},
+		"title and ID":    {UpsertBookInput{Title: "Dune", Author: Ref{ID: &uuid.UUID{}}}, MatchTitleAuthor},
+		"named relations": {UpsertBookInput{Title: "Dune", Author: author, Series: &Ref{Name: "Dune"}}, MatchTitleAuthor},
+	}
+	for name, c := range valid {
+		if err := validateUpsert(c.row, c.match); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+	}
+
+	invalid := map[string]struct {
+		row   UpsertBookInput
+		match Match
+	}{
+		"no title":        {UpsertBookInput{Author: author, ISBN13: &isbn13}, MatchISBN13},
+		"no author":       {UpsertBookInput{Title: "Dune", ISBN13: &isbn13}, MatchISBN13},
+		"empty publisher": {UpsertBookInput{Title: "Dune", Author: author, Publisher: &Ref{}}, MatchTitleAuthor},
+		"zero pages":      {UpsertBookInput{Title: "Dune", Author: author, Pages: &zero}, MatchTitleAuthor},
+		"long isbn13":     {UpsertBookInput{Title: "Dune", Author: author, ISBN13: &long}, MatchTitleAuthor},
+		"no match isbn13": {UpsertBookInput{Title: "Dune", Author: author}, MatchISBN13},
+		"no match isbn10": {UpsertBookInput{Title: "Dune", Author: author, ISBN13: &isbn13}, MatchISBN10},
+	}
+	for name, c := range invalid {
+		if err := validateUpsert(c.row, c.match); err == nil {
+			t.Fatalf("%s: expected a validation error", name)
+		}
+	}
+}
+
+func TestUpsertBooksLimits(t *testing.T) {
+	// A service without a pool panics if it gets as far as a query.
+	s := &Service{}
+	if _, err := s.UpsertBooks(context.Background(), make([]UpsertBookInput, MaxUpsertBooks+1), MatchISBN13); err == nil {
+		t.Fatal("expected too many rows to be rejected")
+	}
+	if _, err := s.UpsertBooks(context.Background(), nil, Match("ISBN")); err == nil || !strings.Contains(err.Error(), "unknown match") {
+		t.Fatalf("expected an unknown match error, got %v", err)
+	}
+}
+
+func TestUpdateParamsMerge(t *testing.T) {
+	subtitle := "Book One"
+	description := "Desert planet"
+	newDescription := "Arrakis"
+	published := time.Date(1965, 8, 1, 0, 0, 0, 0, time.UTC)
+	seriesID := uuid.New()
+	existing := sqlc.Book{
+		ID:            uuid.New(),
+		Title:         "Dune",
+		Subtitle:      &subtitle,
+		AuthorID:      uuid.New(),
+		PublishedDate: &published,
+		Description:   &description,
+		SeriesID:      pgtype.UUID{Bytes: seriesID, Valid: true},
+	}
+
+	newUpsertFor := func(row UpsertBookInput) *upsert {
+		u := newUpsert(nil, []UpsertBookInput{row}, MatchTitleAuthor)
+		u.authors.ids = []uuid.UUID{existing.AuthorID}
+		u.publishers.ids = []uuid.UUID{uuid.Nil}
+		u.series.ids = []uuid.UUID{uuid.Nil}
+		return u
+	}
+
+	// Fields left out keep their values, so sending what is there already
+	// changes nothing, even a date parsed again.
+	again := published.In(time.FixedZone("elsewhere", 3600))
+	u := newUpsertFor(UpsertBookInput{Title: "Dune", Author: Ref{ID: &existing.AuthorID}, PublishedDate: &again})
+	params := u.updateParams(0, existing)
+	if !unchanged(existing, params) {
+		t.Fatalf("expected no change, got %+v", params)
+	}
+	if params.Subtitle != &subtitle || params.SeriesID != existing.SeriesID {
+		t.Fatalf("expected omitted fields to be kept, got %+v", params)
+	}
+
+	u = newUpsertFor(UpsertBookInput{Title: "Dune", Author: Ref{ID: &existing.AuthorID}, Description: &newDescription})
+	params = u.updateParams(0, existing)
+	if unchanged(existing, params) {
+		t.Fatal("expected the new description to be a change")
+	}
+	if *params.Description != newDescription || *params.Subtitle != subtitle {
+		t.Fatalf("unexpected merge: %+v", params)
+	}
+}
+
+func TestRelationResolveRejectsTrashed(t *testing.T) {
+	rows := []UpsertBookInput{
+		{Title: "Dune", Author: Ref{Name: "Frank Herbert"}},
+		{Title: "Dune Messiah", Author: Ref{Name: "Frank Herbert"}},
+		{Title: "The Left Hand of Darkness", Author: Ref{Name: "Ursula K. Le Guin"}},
+	}
+	u := newUpsert(nil, rows, MatchTitleAuthor)
+	r := u.authors
+	r.ids = make([]uuid.UUID, len(rows))
+	r.missing = map[string][]int{"Frank Herbert": {0, 1}, "Ursula K. Le Guin": {2}}
+
+	// A name another request created and trashed since find comes back
+	// from getByNames like any other row.
+	deletedAt := time.Now()
+	live := sqlc.Author{ID: uuid.New(), Name: "Frank Herbert"}
+	trashed := sqlc.Author{ID: uuid.New(), Name: "Ursula K. Le Guin", DeletedAt: &deletedAt}
+	left := r.resolve([]string{"Frank Herbert", "Ursula K. Le Guin", "Iain M. Banks"}, []sqlc.Author{live, trashed})
+
+	if len(left) != 1 || left[0] != "Iain M. Banks" {
+		t.Fatalf("expected only the unreturned name to be left, got %v", left)
+	}
+	if r.ids[0] != live.ID || r.ids[1] != live.ID || !u.ok(0) || !u.ok(1) {
+		t.Fatalf("expected the live author's rows to resolve, got %v", r.ids)
+	}
+	if u.ok(2) || r.ids[2] != uuid.Nil || !strings.Contains(u.result.Rows[2].Error, "in the trash") {
+		t.Fatalf("expected the trashed author's row to be rejected, got %+v", u.result.Rows[2])
+	}
+}
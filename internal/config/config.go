@@ -0,0 +1,320 @@
+// Package config loads the server's settings. Each setting has a default and
+// can be set, in increasing order of precedence, in a YAML or TOML config
+// file, an environment variable or a command-line flag. Load validates the
+// result and reports every problem at once.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// EnvProduction is the APP_ENV of production deployments.
+	EnvProduction = "production"
+
+	// MinJWTSecretLength is the shortest JWT signing secret accepted, in
+	// bytes.
+	MinJWTSecretLength = 32
+)
+
+// Config is every setting of the API server.
+type Config struct {
+	// Env is the deployment environment, like development or production.
+	Env       string    `yaml:"env" toml:"env"`
+	Server    Server    `yaml:"server" toml:"server"`
+	Database  Database  `yaml:"database" toml:"database"`
+	Auth      Auth      `yaml:"auth" toml:"auth"`
+	Trash     Trash     `yaml:"trash" toml:"trash"`
+	GraphQL   GraphQL   `yaml:"graphql" toml:"graphql"`
+	RateLimit RateLimit `yaml:"rate_limit" toml:"rate_limit"`
+	CORS      CORS      `yaml:"cors" toml:"cors"`
+}
+
+type Server struct {
+	Port         int           `yaml:"port" toml:"port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// ShutdownTimeout is how long in-flight requests get to finish on
+	// shutdown.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+type Database struct {
+	URL string `yaml:"url" toml:"url"`
+	// Schema is the schema migrations run in. Outside production it is also
+	// added to the connection's search_path.
+	Schema string `yaml:"schema" toml:"schema"`
+	// ConnectTimeout bounds each attempt to reach the database at startup,
+	// and ConnectAttempts is how many are made, with exponential backoff
+	// between them.
+	ConnectTimeout  time.Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+	ConnectAttempts int           `yaml:"connect_attempts" toml:"connect_attempts"`
+
+	// Connection pool tuning.
+	MaxConns          int           `yaml:"max_conns" toml:"max_conns"`
+	MinConns          int           `yaml:"min_conns" toml:"min_conns"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" toml:"health_check_period"`
+	// StatementTimeout aborts any statement running longer; zero disables
+	// it. Migrations are exempt.
+	StatementTimeout time.Duration `yaml:"statement_timeout" toml:"statement_timeout"`
+
+	// ReplicaURLs are read replicas that queries are spread across. A
+	// replica is used only while it answers and lags the primary by at most
+	// ReplicaMaxLag, checked every ReplicaCheckInterval.
+	ReplicaURLs          []string      `yaml:"replica_urls" toml:"replica_urls"`
+	ReplicaMaxLag        time.Duration `yaml:"replica_max_lag" toml:"replica_max_lag"`
+	ReplicaCheckInterval time.Duration `yaml:"replica_check_interval" toml:"replica_check_interval"`
+}
+
+type Auth struct {
+	// AdminPasswordHash is the bcrypt hash of the password accepted in the
+	// legacy X-Admin-Password header; without it, the header is ignored.
+	AdminPasswordHash string `yaml:"admin_password_hash" toml:"admin_password_hash"`
+	// JWTSecret signs login sessions; without it, login is disabled.
+	JWTSecret       string        `yaml:"jwt_secret" toml:"jwt_secret"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" toml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+}
+
+type Trash struct {
+	// Retention is how long deleted entities stay in the trash; zero
+	// disables the background purge.
+	Retention time.Duration `yaml:"retention" toml:"retention"`
+}
+
+type GraphQL struct {
+	MaxQueryCost  int `yaml:"max_query_cost" toml:"max_query_cost"`
+	MaxQueryDepth int `yaml:"max_query_depth" toml:"max_query_depth"`
+	MaxListLimit  int `yaml:"max_list_limit" toml:"max_list_limit"`
+	// Introspection enables schema introspection and the playground. It
+	// defaults to off in production.
+	Introspection *bool `yaml:"introspection" toml:"introspection"`
+	// PersistedQueriesFile, when set, restricts /query to the queries in
+	// that manifest.
+	PersistedQueriesFile string `yaml:"persisted_queries_file" toml:"persisted_queries_file"`
+}
+
+type RateLimit struct {
+	// Burst is how many cost points a client can spend at once, and Rate
+	// how many it gets back per second.
+	Burst int `yaml:"burst" toml:"burst"`
+	Rate  int `yaml:"rate" toml:"rate"`
+}
+
+type CORS struct {
+	// AllowedOrigins defaults to localhost outside production and to none
+	// in production.
+	AllowedOrigins   []string      `yaml:"allowed_origins" toml:"allowed_origins"`
+	AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age" toml:"max_age"`
+	// RouteOrigins overrides the allowed origins for paths under a prefix.
+	RouteOrigins map[string][]string `yaml:"route_origins" toml:"route_origins"`
+}
+
+// DevelopmentOrigins are the CORS origins allowed by default outside
+// production, so the Vite dev server can reach a local API.
+var DevelopmentOrigins = []string{"http://localhost:*", "http://127.0.0.1:*"}
+
+// Default returns the settings used when nothing else sets them.
+func Default() *Config {
+	return &Config{
+		Env: "development",
+		Server: Server{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     time.Minute,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Database: Database{
+			ConnectTimeout:    10 * time.Second,
+			ConnectAttempts:   8,
+			MaxConns:          10,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+			StatementTimeout:  30 * time.Second,
+
+			ReplicaMaxLag:        5 * time.Second,
+			ReplicaCheckInterval: 5 * time.Second,
+		},
+		Auth: Auth{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+		Trash: Trash{
+			Retention: 30 * 24 * time.Hour,
+		},
+		GraphQL: GraphQL{
+			MaxQueryCost:  5000,
+			MaxQueryDepth: 10,
+			MaxListLimit:  100,
+		},
+		RateLimit: RateLimit{
+			Burst: 20000,
+			Rate:  200,
+		},
+		CORS: CORS{
+			MaxAge: 10 * time.Minute,
+		},
+	}
+}
+
+// Production reports whether this is a production deployment.
+func (c *Config) Production() bool {
+	return c.Env == EnvProduction
+}
+
+// IntrospectionEnabled reports whether schema introspection and the
+// playground are on.
+func (c *Config) IntrospectionEnabled() bool {
+	if c.GraphQL.Introspection != nil {
+		return *c.GraphQL.Introspection
+	}
+	return !c.Production()
+}
+
+// Load reads the configuration. It registers a flag for every setting, plus
+// -config naming a config file, on fs and parses args with it; the file may
+// also be named by CONFIG_FILE. Callers add their own flags to fs first.
+//
+// The returned Config is never nil, so it can be printed even when the error
+// reports that it is invalid.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	var problems []string
+	configFile := fs.String("config", "", "path to a YAML or TOML config file (or CONFIG_FILE)")
+	// Flags are applied last, once the file and environment are read.
+	type flagValue struct {
+		setting setting
+		value   string
+	}
+	var flagValues []flagValue
+	for _, s := range settings {
+		fs.Func(s.flagName(), s.usage, func(v string) error {
+			flagValues = append(flagValues, flagValue{s, v})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	// The plaintext password was replaced by its hash; say so rather than
+	// silently ignoring it.
+	if os.Getenv("ADMIN_PASSWORD") != "" {
+		problems = append(problems, "ADMIN_PASSWORD is no longer read; set ADMIN_PASSWORD_HASH to its bcrypt hash, as printed by `go run ./cmd/hashpassword`")
+	}
+	for _, s := range settings {
+		if v := os.Getenv(s.env); v != "" {
+			if err := s.set(v); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", s.env, err))
+			}
+		}
+	}
+	for _, f := range flagValues {
+		if err := f.setting.set(f.value); err != nil {
+			problems = append(problems, fmt.Sprintf("-%s: %v", f.setting.flagName(), err))
+		}
+	}
+
+	if cfg.CORS.AllowedOrigins == nil && !cfg.Production() {
+		cfg.CORS.AllowedOrigins = DevelopmentOrigins
+	}
+
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// ValidationError lists everything wrong with a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+func (c *Config) validate() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Env != "", "APP_ENV must not be empty")
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ReadTimeout > 0, "HTTP_READ_TIMEOUT must be positive")
+	check(c.Server.WriteTimeout > 0, "HTTP_WRITE_TIMEOUT must be positive")
+	check(c.Server.IdleTimeout > 0, "HTTP_IDLE_TIMEOUT must be positive")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+
+	check(c.Database.URL != "", "DATABASE_URL is required")
+	check(c.Database.ConnectTimeout > 0, "DATABASE_CONNECT_TIMEOUT must be positive")
+	check(c.Database.ConnectAttempts > 0, "DATABASE_CONNECT_ATTEMPTS must be positive")
+	check(c.Database.MaxConns > 0, "DATABASE_MAX_CONNS must be positive")
+	check(c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns,
+		"DATABASE_MIN_CONNS must be between 0 and DATABASE_MAX_CONNS (%d), got %d", c.Database.MaxConns, c.Database.MinConns)
+	check(c.Database.MaxConnLifetime > 0, "DATABASE_MAX_CONN_LIFETIME must be positive")
+	check(c.Database.MaxConnIdleTime > 0, "DATABASE_MAX_CONN_IDLE_TIME must be positive")
+	check(c.Database.HealthCheckPeriod > 0, "DATABASE_HEALTH_CHECK_PERIOD must be positive")
+	check(c.Database.StatementTimeout >= 0, "DATABASE_STATEMENT_TIMEOUT must not be negative")
+	check(c.Database.ReplicaMaxLag > 0, "DATABASE_REPLICA_MAX_LAG must be positive")
+	check(c.Database.ReplicaCheckInterval > 0, "DATABASE_REPLICA_CHECK_INTERVAL must be positive")
+
+	if c.Auth.AdminPasswordHash != "" {
+		_, err := bcrypt.Cost([]byte(c.Auth.AdminPasswordHash))
+		check(err == nil, "ADMIN_PASSWORD_HASH must be a bcrypt hash, as printed by `go run ./cmd/hashpassword`: %v", err)
+	}
+	check(c.Auth.JWTSecret == "" || len(c.Auth.JWTSecret) >= MinJWTSecretLength,
+		"JWT_SECRET must be at least %d characters", MinJWTSecretLength)
+	check(c.Auth.AccessTokenTTL > 0, "ACCESS_TOKEN_TTL must be positive")
+	check(c.Auth.RefreshTokenTTL > 0, "REFRESH_TOKEN_TTL must be positive")
+	check(c.Auth.AccessTokenTTL <= c.Auth.RefreshTokenTTL, "ACCESS_TOKEN_TTL must not be longer than REFRESH_TOKEN_TTL")
+
+	check(c.GraphQL.MaxQueryCost > 0, "QUERY_MAX_COST must be positive")
+	check(c.GraphQL.MaxQueryDepth > 0, "QUERY_MAX_DEPTH must be positive")
+	check(c.GraphQL.MaxListLimit > 0, "QUERY_MAX_LIMIT must be positive")
+	check(c.RateLimit.Burst > 0, "RATE_LIMIT_BURST must be positive")
+	check(c.RateLimit.Rate > 0, "RATE_LIMIT_RATE must be positive")
+	check(c.GraphQL.MaxQueryCost <= c.RateLimit.Burst,
+		"QUERY_MAX_COST (%d) must not be above RATE_LIMIT_BURST (%d), or the most expensive queries can never run",
+		c.GraphQL.MaxQueryCost, c.RateLimit.Burst)
+
+	check(c.CORS.MaxAge >= 0, "CORS_MAX_AGE must not be negative")
+	for _, origin := range c.CORS.AllowedOrigins {
+		check(origin == "*" || strings.Contains(origin, "://"), "CORS_ALLOWED_ORIGINS: %q is not an origin like https://example.com", origin)
+		check(!(origin == "*" && c.CORS.AllowCredentials), "CORS_ALLOW_CREDENTIALS cannot be combined with a * origin")
+	}
+	for prefix := range c.CORS.RouteOrigins {
+		check(strings.HasPrefix(prefix, "/"), "CORS_ROUTE_ORIGINS: route %q must start with /", prefix)
+	}
+
+	return problems
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"io"
+	"net/url"
+
+	"github.com/goccy/go-yaml"
+)
+
+// redacted replaces secrets in printed configurations.
+const redacted = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with its secrets replaced:
+// the admin password hash, the JWT secret and the database passwords. Settings
+// that default according to the environment are filled in.
+func (c *Config) Redacted() *Config {
+	r := *c
+	introspection := c.IntrospectionEnabled()
+	r.GraphQL.Introspection = &introspection
+	if r.Auth.AdminPasswordHash != "" {
+		r.Auth.AdminPasswordHash = redacted
+	}
+	if r.Auth.JWTSecret != "" {
+		r.Auth.JWTSecret = redacted
+	}
+	r.Database.URL = redactURL(r.Database.URL)
+	r.Database.ReplicaURLs = make([]string, len(c.Database.ReplicaURLs))
+	for i, u := range c.Database.ReplicaURLs {
+		r.Database.ReplicaURLs[i] = redactURL(u)
+	}
+	return &r
+}
+
+// redactURL hides the password in a postgres:// URL, the way url.URL.Redacted
+// does. Connection strings in key=value form may hold a password anywhere,
+// so they are hidden entirely.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return redacted
+	}
+	if q := u.Query(); q.Has("password") {
+		q.Set("password", "xxxxx")
+		u.RawQuery = q.Encode()
+	}
+	return u.Redacted()
+}
+
+// Print writes the configuration, with secrets redacted, as YAML that can
+// be used as a config file.
+func (c *Config) Print(w io.Writer) error {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
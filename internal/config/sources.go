@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goccy/go-yaml"
+)
+
+// setting is one value that can be set from the environment or a flag.
+type setting struct {
+	// env is the environment variable; the flag is its lowercase form with
+	// dashes, like -database-url for DATABASE_URL.
+	env   string
+	usage string
+	// ptr points into the Config being loaded.
+	ptr any
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"APP_ENV", "deployment environment, like development or production", &c.Env},
+
+		{"PORT", "port to listen on", &c.Server.Port},
+		{"HTTP_READ_TIMEOUT", "maximum time to read a request", &c.Server.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", "maximum time to write a response", &c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", "how long idle keep-alive connections stay open", &c.Server.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", "how long in-flight requests get to finish on shutdown", &c.Server.ShutdownTimeout},
+
+		{"DATABASE_URL", "PostgreSQL connection string", &c.Database.URL},
+		{"DATABASE_SCHEMA", "schema to run migrations in and, outside production, to search", &c.Database.Schema},
+		{"DATABASE_CONNECT_TIMEOUT", "how long each attempt to reach the database at startup may take", &c.Database.ConnectTimeout},
+		{"DATABASE_CONNECT_ATTEMPTS", "how many times to try reaching the database at startup", &c.Database.ConnectAttempts},
+		{"DATABASE_MAX_CONNS", "maximum open database connections", &c.Database.MaxConns},
+		{"DATABASE_MIN_CONNS", "database connections kept open when idle", &c.Database.MinConns},
+		{"DATABASE_MAX_CONN_LIFETIME", "how long a database connection is reused before being replaced", &c.Database.MaxConnLifetime},
+		{"DATABASE_MAX_CONN_IDLE_TIME", "how long an idle database connection is kept", &c.Database.MaxConnIdleTime},
+		{"DATABASE_HEALTH_CHECK_PERIOD", "how often idle database connections are checked", &c.Database.HealthCheckPeriod},
+		{"DATABASE_STATEMENT_TIMEOUT", "longest a SQL statement may run; 0 disables the limit", &c.Database.StatementTimeout},
+		{"DATABASE_REPLICA_URLS", "comma-separated read replica connection strings", &c.Database.ReplicaURLs},
+		{"DATABASE_REPLICA_MAX_LAG", "replication lag above which a replica is not used", &c.Database.ReplicaMaxLag},
+		{"DATABASE_REPLICA_CHECK_INTERVAL", "how often replica health and lag are checked", &c.Database.ReplicaCheckInterval},
+
+		{"ADMIN_PASSWORD_HASH", "bcrypt hash of the password for the legacy X-Admin-Password header", &c.Auth.AdminPasswordHash},
+		{"JWT_SECRET", "secret signing login sessions; login is disabled without it", &c.Auth.JWTSecret},
+		{"ACCESS_TOKEN_TTL", "lifetime of session access tokens", &c.Auth.AccessTokenTTL},
+		{"REFRESH_TOKEN_TTL", "lifetime of session refresh tokens", &c.Auth.RefreshTokenTTL},
+
+		{"TRASH_RETENTION", "how long deleted entities stay in the trash; 0 keeps them forever", &c.Trash.Retention},
+
+		{"QUERY_MAX_COST", "most a single operation may cost", &c.GraphQL.MaxQueryCost},
+		{"QUERY_MAX_DEPTH", "how deeply selections may nest", &c.GraphQL.MaxQueryDepth},
+		{"QUERY_MAX_LIMIT", "largest limit or first argument accepted", &c.GraphQL.MaxListLimit},
+		{"GRAPHQL_INTROSPECTION", "enable introspection and the playground (default: off in production)", &c.GraphQL.Introspection},
+		{"PERSISTED_QUERIES_FILE", "persisted query manifest; only its queries are executed", &c.GraphQL.PersistedQueriesFile},
+
+		{"RATE_LIMIT_BURST", "cost points a client can spend at once", &c.RateLimit.Burst},
+		{"RATE_LIMIT_RATE", "cost points a client gets back per second", &c.RateLimit.Rate},
+
+		{"CORS_ALLOWED_ORIGINS", "comma-separated origins browsers may call the API from", &c.CORS.AllowedOrigins},
+		{"CORS_ALLOW_CREDENTIALS", "let browsers send cookies and HTTP authentication", &c.CORS.AllowCredentials},
+		{"CORS_MAX_AGE", "how long browsers may cache preflight responses", &c.CORS.MaxAge},
+		{"CORS_ROUTE_ORIGINS", "per-route origins as prefix=origin,origin entries separated by ;", &c.CORS.RouteOrigins},
+	}
+}
+
+func (s setting) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(s.env), "_", "-")
+}
+
+// set parses v into the setting.
+func (s setting) set(v string) error {
+	switch p := s.ptr.(type) {
+	case *string:
+		*p = v
+	case *int:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", v)
+		}
+		*p = n
+	case *bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+		*p = b
+	case **bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+		*p = &b
+	case *time.Duration:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration like 30s or 15m", v)
+		}
+		*p = d
+	case *[]string:
+		*p = splitList(v, ",")
+	case *map[string][]string:
+		routes := make(map[string][]string)
+		for _, entry := range splitList(v, ";") {
+			prefix, origins, ok := strings.Cut(entry, "=")
+			if !ok {
+				return fmt.Errorf("%q is not a prefix=origin,origin entry", entry)
+			}
+			routes[strings.TrimSpace(prefix)] = splitList(origins, ",")
+		}
+		*p = routes
+	default:
+		panic(fmt.Sprintf("config: unsupported setting type %T", s.ptr))
+	}
+	return nil
+}
+
+// splitList splits v on sep, trimming spaces and dropping empty items.
+func splitList(v, sep string) []string {
+	items := []string{}
+	for _, item := range strings.Split(v, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// loadFile reads a config file over cfg. The format follows the extension:
+// .yaml or .yml for YAML, .toml for TOML. Unknown keys are errors, so typos
+// do not go unnoticed.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.UnmarshalWithOptions(data, cfg, yaml.Strict()); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(cfg)
+		if err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("config file %s: unknown keys %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	return nil
+}
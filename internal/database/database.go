@@ -4,45 +4,47 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"book-nexus/internal/config"
 	"book-nexus/internal/database/sqlc"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	_ "github.com/joho/godotenv/autoload"
 )
 
 type Service interface {
 	Health() map[string]string
 	Close() error
+	// DB is the primary, for writes and anything that must see them.
 	DB() *pgxpool.Pool
+	// Reader is the pool to read from: a healthy replica when ctx is marked
+	// with WithReadOnly and replicas are configured, otherwise the primary.
+	Reader(ctx context.Context) *pgxpool.Pool
 	Queries() *sqlc.Queries
+	// WithTx runs fn in a transaction on the primary. Services built on tx
+	// take part in it, so their writes commit or roll back together.
+	WithTx(ctx context.Context, fn func(tx Tx) error) error
 }
 
 type service struct {
-	db      *pgxpool.Pool
-	queries *sqlc.Queries
+	db       *pgxpool.Pool
+	queries  *sqlc.Queries
+	replicas *replicaSet
 }
 
 var dbInstance *service
 
-func getConnectionString() string {
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		log.Fatal("FATAL: DATABASE_URL is not set")
-	}
-
+func getConnectionString(cfg *config.Config, dbURL string) string {
 	// Production: Use URL exactly as provided
-	if os.Getenv("APP_ENV") == "production" {
+	if cfg.Production() {
 		log.Println("Production mode: Using DATABASE_URL without modification")
 		return dbURL
 	}
 
 	// Development: Add search_path if schema provided
-	if schema := os.Getenv("DATABASE_SCHEMA"); schema != "" {
+	if schema := cfg.Database.Schema; schema != "" {
 		separator := "?"
 		if strings.Contains(dbURL, "?") {
 			separator = "&"
@@ -54,62 +56,167 @@ func getConnectionString() string {
 	return dbURL
 }
 
-func New() Service {
+// Startup retries wait connectRetryBase, doubling up to connectRetryMax.
+const (
+	connectRetryBase = 500 * time.Millisecond
+	connectRetryMax  = 10 * time.Second
+)
+
+// New opens the connection pool. Postgres may still be starting, as when it
+// comes up alongside the API in docker-compose, so the first ping is retried
+// with exponential backoff up to cfg.Database.ConnectAttempts times.
+func New(ctx context.Context, cfg *config.Config) (Service, error) {
 	if dbInstance != nil {
-		return dbInstance
+		return dbInstance, nil
 	}
 
-	connStr := getConnectionString()
+	poolConfig, err := newPoolConfig(cfg, cfg.Database.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
 	log.Printf("Attempting to connect to database...")
 
-	db, err := pgxpool.New(context.Background(), connStr)
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
-		log.Fatalf("FATAL: Failed to create connection pool: %v", err)
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
 
-	// Verify connection
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	if err := db.Ping(ctx); err != nil {
-		log.Fatalf("FATAL: Database ping failed: %v", err)
+	delay := connectRetryBase
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, cfg.Database.ConnectTimeout)
+		err = db.Ping(pingCtx)
+		cancel()
+		if err == nil {
+			break
+		}
+		if attempt >= cfg.Database.ConnectAttempts {
+			db.Close()
+			return nil, fmt.Errorf("database ping failed after %d attempts: %w", attempt, err)
+		}
+		log.Printf("Database not ready (attempt %d of %d), retrying in %v: %v",
+			attempt, cfg.Database.ConnectAttempts, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			db.Close()
+			return nil, ctx.Err()
+		}
+		delay = min(delay*2, connectRetryMax)
 	}
 
 	log.Println("Database connected successfully")
+
+	replicas, err := openReplicas(ctx, cfg)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
 	dbInstance = &service{
-		db:      db,
-		queries: sqlc.New(db),
+		db:       db,
+		queries:  sqlc.New(db),
+		replicas: replicas,
+	}
+	return dbInstance, nil
+}
+
+// openReplicas opens a pool per replica and starts checking them. Replicas
+// that are down are not an error: reads go to the primary until they
+// recover.
+func openReplicas(ctx context.Context, cfg *config.Config) (*replicaSet, error) {
+	rs := &replicaSet{
+		maxLag:       cfg.Database.ReplicaMaxLag,
+		checkTimeout: cfg.Database.ConnectTimeout,
+		measure:      measureLag,
+	}
+	for i, url := range cfg.Database.ReplicaURLs {
+		poolConfig, err := newPoolConfig(cfg, url)
+		if err != nil {
+			rs.close()
+			return nil, fmt.Errorf("invalid DATABASE_REPLICA_URLS entry %d: %w", i, err)
+		}
+		pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+		if err != nil {
+			rs.close()
+			return nil, fmt.Errorf("failed to create replica %d connection pool: %w", i, err)
+		}
+		rs.replicas = append(rs.replicas, &replica{pool: pool})
+	}
+	if len(rs.replicas) == 0 {
+		return rs, nil
+	}
+
+	rs.check(ctx)
+	log.Printf("Reading from %d of %d replicas", rs.healthyCount(), len(rs.replicas))
+	checkCtx, stop := context.WithCancel(context.Background())
+	rs.stop = stop
+	go rs.run(checkCtx, cfg.Database.ReplicaCheckInterval)
+	return rs, nil
+}
+
+// newPoolConfig applies the pool settings and statement timeout to a
+// connection string.
+func newPoolConfig(cfg *config.Config, dbURL string) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(getConnectionString(cfg, dbURL))
+	if err != nil {
+		return nil, err
 	}
-	return dbInstance
+	poolConfig.MaxConns = int32(cfg.Database.MaxConns)
+	poolConfig.MinConns = int32(cfg.Database.MinConns)
+	poolConfig.MaxConnLifetime = cfg.Database.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.Database.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = cfg.Database.HealthCheckPeriod
+	if timeout := cfg.Database.StatementTimeout; timeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(timeout.Milliseconds(), 10)
+	}
+	return poolConfig, nil
 }
 
+// Health pings the database and reports the pool's state. The status is
+// "up", or "degraded" either when every connection is in use, so requests
+// are queueing, or when the database does not answer, which sets "error".
 func (s *service) Health() map[string]string {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	stats := make(map[string]string)
 
+	// The ping needs a free connection, so a saturated pool can fail it
+	// without the database being down.
+	dbStats := s.db.Stat()
+	saturated := dbStats.AcquiredConns() >= dbStats.MaxConns()
+
 	err := s.db.Ping(ctx)
-	if err != nil {
-		stats["status"] = "down"
+	if err != nil && !saturated {
+		stats["status"] = "degraded"
 		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err)
+		log.Printf("db down: %v", err)
 		return stats
 	}
 
-	stats["status"] = "up"
-	stats["message"] = "It's healthy"
-
-	dbStats := s.db.Stat()
 	stats["open_connections"] = strconv.Itoa(int(dbStats.TotalConns()))
 	stats["in_use"] = strconv.Itoa(int(dbStats.AcquiredConns()))
 	stats["idle"] = strconv.Itoa(int(dbStats.IdleConns()))
+	stats["max_connections"] = strconv.Itoa(int(dbStats.MaxConns()))
 
+	if n := len(s.replicas.replicas); n > 0 {
+		stats["replicas"] = strconv.Itoa(n)
+		stats["healthy_replicas"] = strconv.Itoa(s.replicas.healthyCount())
+	}
+
+	if saturated {
+		stats["status"] = "degraded"
+		stats["message"] = "All connections are in use"
+		return stats
+	}
+
+	stats["status"] = "up"
+	stats["message"] = "It's healthy"
 	return stats
 }
 
 func (s *service) Close() error {
 	log.Printf("Disconnected from database")
+	s.replicas.close()
 	s.db.Close()
 	return nil
 }
@@ -118,6 +225,15 @@ func (s *service) DB() *pgxpool.Pool {
 	return s.db
 }
 
+func (s *service) Reader(ctx context.Context) *pgxpool.Pool {
+	if IsReadOnly(ctx) {
+		if pool := s.replicas.pick(); pool != nil {
+			return pool
+		}
+	}
+	return s.db
+}
+
 func (s *service) Queries() *sqlc.Queries {
 	return s.queries
 }
@@ -0,0 +1,132 @@
+// Package dbtest runs the tests of packages that need a database against a
+// throwaway Postgres container. The schema is migrated once; each test gets
+// a database of its own, copied from the migrated one, so tests neither see
+// nor clean up after each other.
+//
+// A package opts in from its TestMain:
+//
+//	func TestMain(m *testing.M) { os.Exit(dbtest.Main(m)) }
+package dbtest
+
+import (
+	migration "book-nexus/internal/database/migrations"
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	user     = "user"
+	password = "password"
+	// template is the migrated database every test's database is copied
+	// from.
+	template = "migrated"
+)
+
+var (
+	// hostPort is where the container listens, empty without Docker.
+	hostPort string
+	created  atomic.Int64
+)
+
+// Main starts the container and migrates the template database, runs the
+// tests and removes the container. Without Docker the tests still run, and
+// New skips the ones that need a database.
+func Main(m *testing.M) int {
+	teardown, err := start()
+	if err != nil {
+		log.Printf("Warning: could not start postgres container: %v", err)
+		log.Printf("Tests requiring Docker will be skipped")
+		hostPort = ""
+	}
+	code := m.Run()
+	if teardown != nil {
+		if err := teardown(context.Background()); err != nil {
+			log.Printf("Warning: could not teardown postgres container: %v", err)
+		}
+	}
+	return code
+}
+
+func start() (teardown func(context.Context, ...testcontainers.TerminateOption) error, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("docker initialization panicked: %v", r)
+		}
+	}()
+
+	ctx := context.Background()
+	container, err := postgres.Run(ctx,
+		"postgres:latest",
+		postgres.WithDatabase(template),
+		postgres.WithUsername(user),
+		postgres.WithPassword(password),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	host, err := container.Host(ctx)
+	if err != nil {
+		return container.Terminate, err
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		return container.Terminate, err
+	}
+	hostPort = fmt.Sprintf("%s:%s", host, port.Port())
+
+	pool, err := pgxpool.New(ctx, url(template))
+	if err != nil {
+		return container.Terminate, err
+	}
+	// The template must have no connections left when it is copied.
+	defer pool.Close()
+	if err := migration.RunMigrations(pool, ""); err != nil {
+		return container.Terminate, err
+	}
+	return container.Terminate, nil
+}
+
+func url(database string) string {
+	return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", user, password, hostPort, database)
+}
+
+// New returns a pool on a fresh, migrated database, closed when t ends. It
+// skips t when Docker is not available.
+func New(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	if hostPort == "" {
+		t.Skip("Skipping test: Docker not available")
+	}
+
+	ctx := context.Background()
+	name := fmt.Sprintf("test_%d", created.Add(1))
+	admin, err := pgx.Connect(ctx, url("postgres"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer admin.Close(ctx)
+	if _, err := admin.Exec(ctx, fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, template)); err != nil {
+		t.Fatalf("create database: %v", err)
+	}
+
+	pool, err := pgxpool.New(ctx, url(name))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
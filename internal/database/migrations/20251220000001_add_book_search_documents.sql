@@ -0,0 +1,92 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Weighted full-text document per book. Author and series names live in
+-- other tables, so the document is kept in its own table and maintained by
+-- triggers rather than as a generated column on books.
+CREATE TABLE book_search_documents (
+    book_id UUID PRIMARY KEY REFERENCES books(id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL
+);
+
+CREATE INDEX idx_book_search_documents_document ON book_search_documents USING GIN (document);
+
+-- Weights: title A, author name B, series name, genres and tags C,
+-- description D.
+CREATE OR REPLACE FUNCTION refresh_book_search_documents(book_ids UUID[])
+RETURNS VOID AS $$
+BEGIN
+    INSERT INTO book_search_documents (book_id, document)
+    SELECT
+        b.id,
+        setweight(to_tsvector('english', b.title), 'A') ||
+        setweight(to_tsvector('english', COALESCE(a.name, '')), 'B') ||
+        setweight(to_tsvector('english', concat_ws(' ', s.name, b.genres, b.tags)), 'C') ||
+        setweight(to_tsvector('english', COALESCE(b.description, '')), 'D')
+    FROM books b
+    LEFT JOIN authors a ON b.author_id = a.id
+    LEFT JOIN series s ON b.series_id = s.id
+    WHERE b.id = ANY(book_ids)
+    ON CONFLICT (book_id) DO UPDATE SET document = EXCLUDED.document;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION books_search_document_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_book_search_documents(ARRAY[NEW.id]);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refresh_books_search_document
+    AFTER INSERT OR UPDATE OF title, author_id, series_id, genres, tags, description ON books
+    FOR EACH ROW
+    EXECUTE FUNCTION books_search_document_trigger();
+
+CREATE OR REPLACE FUNCTION authors_search_document_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_book_search_documents(ARRAY(SELECT id FROM books WHERE author_id = NEW.id));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refresh_authors_search_document
+    AFTER UPDATE OF name ON authors
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION authors_search_document_trigger();
+
+CREATE OR REPLACE FUNCTION series_search_document_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_book_search_documents(ARRAY(SELECT id FROM books WHERE series_id = NEW.id));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refresh_series_search_document
+    AFTER UPDATE OF name ON series
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION series_search_document_trigger();
+
+-- Backfill existing books
+SELECT refresh_book_search_documents(ARRAY(SELECT id FROM books));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS refresh_series_search_document ON series;
+DROP TRIGGER IF EXISTS refresh_authors_search_document ON authors;
+DROP TRIGGER IF EXISTS refresh_books_search_document ON books;
+DROP FUNCTION IF EXISTS series_search_document_trigger();
+DROP FUNCTION IF EXISTS authors_search_document_trigger();
+DROP FUNCTION IF EXISTS books_search_document_trigger();
+DROP FUNCTION IF EXISTS refresh_book_search_documents(UUID[]);
+DROP TABLE IF EXISTS book_search_documents;
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes back typo-tolerant matching (%, <%) and prefix/substring
+-- ILIKE on the names users type into search and autocomplete.
+CREATE INDEX idx_books_title_trgm ON books USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_authors_name_trgm ON authors USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_publishers_name_trgm ON publishers USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_series_name_trgm ON series USING GIN (name gin_trgm_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_series_name_trgm;
+DROP INDEX IF EXISTS idx_publishers_name_trgm;
+DROP INDEX IF EXISTS idx_authors_name_trgm;
+DROP INDEX IF EXISTS idx_books_title_trgm;
+
+-- +goose StatementEnd
@@ -0,0 +1,126 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Same slug rules as the authors/publishers/series backfill
+CREATE OR REPLACE FUNCTION slugify(value TEXT)
+RETURNS TEXT AS $$
+    SELECT LOWER(REGEXP_REPLACE(REGEXP_REPLACE(value, '[^a-zA-Z0-9\s-]', '', 'g'), '\s+', '-', 'g'));
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Create genres table. The slug identifies a genre, so "Sci Fi" and "sci-fi"
+-- in the comma-separated column resolve to the same row.
+CREATE TABLE genres (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create tags table
+CREATE TABLE tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE book_genres (
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    genre_id UUID NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, genre_id)
+);
+
+CREATE INDEX idx_book_genres_genre_id ON book_genres(genre_id);
+
+CREATE TABLE book_tags (
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, tag_id)
+);
+
+CREATE INDEX idx_book_tags_tag_id ON book_tags(tag_id);
+
+-- books.genres and books.tags stay the write path (mutations, the CSV seed);
+-- these functions rebuild the link rows from them.
+CREATE OR REPLACE FUNCTION sync_book_genres(book_ids UUID[])
+RETURNS VOID AS $$
+BEGIN
+    DELETE FROM book_genres WHERE book_id = ANY(book_ids);
+
+    INSERT INTO genres (name, slug)
+    SELECT DISTINCT ON (slugify(TRIM(g.name))) TRIM(g.name), slugify(TRIM(g.name))
+    FROM books b
+    CROSS JOIN LATERAL unnest(string_to_array(b.genres, ',')) AS g(name)
+    WHERE b.id = ANY(book_ids) AND slugify(TRIM(g.name)) != ''
+    ORDER BY slugify(TRIM(g.name)), TRIM(g.name)
+    ON CONFLICT (slug) DO NOTHING;
+
+    INSERT INTO book_genres (book_id, genre_id)
+    SELECT DISTINCT b.id, ge.id
+    FROM books b
+    CROSS JOIN LATERAL unnest(string_to_array(b.genres, ',')) AS g(name)
+    JOIN genres ge ON ge.slug = slugify(TRIM(g.name))
+    WHERE b.id = ANY(book_ids);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_book_tags(book_ids UUID[])
+RETURNS VOID AS $$
+BEGIN
+    DELETE FROM book_tags WHERE book_id = ANY(book_ids);
+
+    INSERT INTO tags (name, slug)
+    SELECT DISTINCT ON (slugify(TRIM(t.name))) TRIM(t.name), slugify(TRIM(t.name))
+    FROM books b
+    CROSS JOIN LATERAL unnest(string_to_array(b.tags, ',')) AS t(name)
+    WHERE b.id = ANY(book_ids) AND slugify(TRIM(t.name)) != ''
+    ORDER BY slugify(TRIM(t.name)), TRIM(t.name)
+    ON CONFLICT (slug) DO NOTHING;
+
+    INSERT INTO book_tags (book_id, tag_id)
+    SELECT DISTINCT b.id, tg.id
+    FROM books b
+    CROSS JOIN LATERAL unnest(string_to_array(b.tags, ',')) AS t(name)
+    JOIN tags tg ON tg.slug = slugify(TRIM(t.name))
+    WHERE b.id = ANY(book_ids);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION books_genres_and_tags_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.genres IS DISTINCT FROM OLD.genres THEN
+        PERFORM sync_book_genres(ARRAY[NEW.id]);
+    END IF;
+    IF TG_OP = 'INSERT' OR NEW.tags IS DISTINCT FROM OLD.tags THEN
+        PERFORM sync_book_tags(ARRAY[NEW.id]);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_books_genres_and_tags
+    AFTER INSERT OR UPDATE OF genres, tags ON books
+    FOR EACH ROW
+    EXECUTE FUNCTION books_genres_and_tags_trigger();
+
+-- Backfill from existing books
+SELECT sync_book_genres(ARRAY(SELECT id FROM books WHERE genres IS NOT NULL));
+SELECT sync_book_tags(ARRAY(SELECT id FROM books WHERE tags IS NOT NULL));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS sync_books_genres_and_tags ON books;
+DROP FUNCTION IF EXISTS books_genres_and_tags_trigger();
+DROP FUNCTION IF EXISTS sync_book_tags(UUID[]);
+DROP FUNCTION IF EXISTS sync_book_genres(UUID[]);
+DROP TABLE IF EXISTS book_tags;
+DROP TABLE IF EXISTS book_genres;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS genres;
+DROP FUNCTION IF EXISTS slugify(TEXT);
+
+-- +goose StatementEnd
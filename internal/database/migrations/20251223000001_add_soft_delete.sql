@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Deleting a catalog entity moves it to the trash by setting deleted_at;
+-- every read query filters on deleted_at IS NULL. Rows are only removed when
+-- purged, by an admin or once they are older than the trash retention.
+ALTER TABLE authors ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE publishers ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE series ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE books ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial indexes keep the trash listing and the expiry purge cheap without
+-- growing with the live catalog.
+CREATE INDEX idx_authors_deleted_at ON authors(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_publishers_deleted_at ON publishers(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_series_deleted_at ON series(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_books_deleted_at ON books(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_books_deleted_at;
+DROP INDEX IF EXISTS idx_series_deleted_at;
+DROP INDEX IF EXISTS idx_publishers_deleted_at;
+DROP INDEX IF EXISTS idx_authors_deleted_at;
+
+ALTER TABLE books DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE series DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE publishers DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE authors DROP COLUMN IF EXISTS deleted_at;
+
+-- +goose StatementEnd
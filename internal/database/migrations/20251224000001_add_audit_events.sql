@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per admin mutation. diff maps each changed field to its
+-- {"from", "to"} values; actor, request_id and remote_addr identify the caller.
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}'::jsonb,
+    actor TEXT NOT NULL,
+    request_id TEXT,
+    remote_addr TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at DESC, id DESC);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_id, created_at DESC, id DESC);
+CREATE INDEX idx_audit_events_entity_type ON audit_events(entity_type, created_at DESC, id DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS audit_events;
+
+-- +goose StatementEnd
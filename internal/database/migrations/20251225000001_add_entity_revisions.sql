@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Full snapshots of catalog rows, taken just before each update so earlier
+-- versions can be inspected and reverted to. revision counts up from 1 per
+-- entity.
+CREATE TABLE entity_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    revision INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    actor TEXT NOT NULL,
+    request_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_id, revision)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS entity_revisions;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Responses of mutations sent with an idempotency key, stored in the same
+-- transaction as their changes so a retried request gets the first response
+-- instead of being applied again. Keys belong to the actor that sent them.
+CREATE TABLE idempotency_keys (
+    actor TEXT NOT NULL,
+    key TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    request_hash BYTEA NOT NULL,
+    response JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (actor, key)
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS idempotency_keys;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- How far cmd/seed got through a CSV file, saved in the same transaction as
+-- each batch so an interrupted import carries on after the last committed
+-- batch. The file's size and modification time tell whether it is still the
+-- file the checkpoint was taken from.
+CREATE TABLE seed_checkpoints (
+    source TEXT PRIMARY KEY,
+    file_size BIGINT NOT NULL,
+    file_modified_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    byte_offset BIGINT NOT NULL,
+    rows_read BIGINT NOT NULL,
+    books_inserted BIGINT NOT NULL,
+    rows_skipped BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS seed_checkpoints;
+
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ISBNs are stored as entered, hyphens and spaces included, so the columns
+-- are wide enough for the separated forms. Searches compare the values with
+-- separators stripped, which these indexes cover.
+ALTER TABLE books ALTER COLUMN isbn10 TYPE VARCHAR(13);
+ALTER TABLE books ALTER COLUMN isbn13 TYPE VARCHAR(17);
+
+CREATE INDEX idx_books_isbn10_normalized ON books (regexp_replace(upper(isbn10), '[^0-9X]', '', 'g'));
+CREATE INDEX idx_books_isbn13_normalized ON books (regexp_replace(upper(isbn13), '[^0-9X]', '', 'g'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_books_isbn13_normalized;
+DROP INDEX IF EXISTS idx_books_isbn10_normalized;
+
+UPDATE books SET
+    isbn10 = regexp_replace(upper(isbn10), '[^0-9X]', '', 'g'),
+    isbn13 = regexp_replace(upper(isbn13), '[^0-9X]', '', 'g')
+WHERE isbn10 ~ '[^0-9X]' OR isbn13 ~ '[^0-9X]';
+
+ALTER TABLE books ALTER COLUMN isbn13 TYPE VARCHAR(13);
+ALTER TABLE books ALTER COLUMN isbn10 TYPE VARCHAR(10);
+
+-- +goose StatementEnd
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type readOnlyKey struct{}
+
+// WithReadOnly marks ctx as serving a read-only operation, such as a GraphQL
+// query, which Reader may route to a replica.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether ctx was marked by WithReadOnly.
+func IsReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// replicaLagSQL is how far a replica's replay lags the primary, in seconds,
+// and whether it is streaming from the primary. A replica that has replayed
+// everything it received is caught up, even if its last replayed transaction
+// is old because the primary is idle. That only holds while it receives: one
+// that lost its WAL receiver has replayed everything too, forever, so the
+// second column says whether a receiver is streaming. Its status is only
+// visible to pg_read_all_stats, so a receiver whose status is hidden counts
+// as streaming. A server that is not in recovery is a primary and never lags.
+const replicaLagSQL = `
+SELECT
+  CASE
+    WHEN NOT pg_is_in_recovery() THEN 0
+    WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+    ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+  END::float8,
+  NOT pg_is_in_recovery() OR EXISTS (
+    SELECT 1 FROM pg_stat_wal_receiver WHERE COALESCE(status, 'streaming') = 'streaming'
+  )`
+
+// errNotStreaming fails the health check of a replica with no WAL receiver
+// streaming from the primary, whose reads grow staler however caught up its
+// replay looks.
+var errNotStreaming = errors.New("no WAL receiver is streaming")
+
+// measureLag runs replicaLagSQL on a replica.
+func measureLag(ctx context.Context, pool *pgxpool.Pool) (time.Duration, error) {
+	var seconds float64
+	var streaming bool
+	if err := pool.QueryRow(ctx, replicaLagSQL).Scan(&seconds, &streaming); err != nil {
+		return 0, err
+	}
+	if !streaming {
+		return 0, errNotStreaming
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+type replica struct {
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+	// lag is the last measured lag, in nanoseconds.
+	lag atomic.Int64
+}
+
+// replicaSet spreads reads across the healthy replicas in turn.
+type replicaSet struct {
+	replicas []*replica
+	maxLag   time.Duration
+	// checkTimeout bounds each health check.
+	checkTimeout time.Duration
+	// measure is measureLag, replaced in tests.
+	measure func(context.Context, *pgxpool.Pool) (time.Duration, error)
+	next    atomic.Uint64
+	stop    context.CancelFunc
+}
+
+// pick returns a healthy replica's pool, or nil when there is none.
+func (rs *replicaSet) pick() *pgxpool.Pool {
+	n := len(rs.replicas)
+	if n == 0 {
+		return nil
+	}
+	start := rs.next.Add(1)
+	for i := range n {
+		r := rs.replicas[(start+uint64(i))%uint64(n)]
+		if r.healthy.Load() {
+			return r.pool
+		}
+	}
+	return nil
+}
+
+// check measures every replica's lag and marks those that do not answer or
+// lag more than maxLag unhealthy, logging each change.
+func (rs *replicaSet) check(ctx context.Context) {
+	for i, r := range rs.replicas {
+		checkCtx, cancel := context.WithTimeout(ctx, rs.checkTimeout)
+		lag, err := rs.measure(checkCtx, r.pool)
+		cancel()
+
+		r.lag.Store(int64(lag))
+		healthy := err == nil && lag <= rs.maxLag
+		if was := r.healthy.Swap(healthy); was != healthy {
+			switch {
+			case healthy:
+				log.Printf("Replica %d is healthy (lag %v)", i, lag)
+			case err != nil:
+				log.Printf("Replica %d is unhealthy, reading from the primary: %v", i, err)
+			default:
+				log.Printf("Replica %d lags by %v (max %v), reading from the primary", i, lag, rs.maxLag)
+			}
+		}
+	}
+}
+
+// run checks the replicas every interval until ctx is done.
+func (rs *replicaSet) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rs.check(ctx)
+		}
+	}
+}
+
+func (rs *replicaSet) healthyCount() int {
+	n := 0
+	for _, r := range rs.replicas {
+		if r.healthy.Load() {
+			n++
+		}
+	}
+	return n
+}
+
+func (rs *replicaSet) close() {
+	if rs.stop != nil {
+		rs.stop()
+	}
+	for _, r := range rs.replicas {
+		r.pool.Close()
+	}
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// lazyPool returns a pool that never connects, to tell pools apart.
+func lazyPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/unused")
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestReaderRouting(t *testing.T) {
+	primary := lazyPool(t)
+	a, b := &replica{pool: lazyPool(t)}, &replica{pool: lazyPool(t)}
+	a.healthy.Store(true)
+	b.healthy.Store(true)
+	s := &service{db: primary, replicas: &replicaSet{replicas: []*replica{a, b}}}
+
+	ctx := context.Background()
+	if s.Reader(ctx) != primary {
+		t.Fatalf("expected reads outside a read-only operation to use the primary")
+	}
+
+	readCtx := WithReadOnly(ctx)
+	seen := map[*pgxpool.Pool]int{}
+	for range 4 {
+		seen[s.Reader(readCtx)]++
+	}
+	if seen[a.pool] != 2 || seen[b.pool] != 2 {
+		t.Fatalf("expected reads to alternate between replicas, got %v", seen)
+	}
+
+	b.healthy.Store(false)
+	for range 3 {
+		if s.Reader(readCtx) != a.pool {
+			t.Fatalf("expected reads to skip the unhealthy replica")
+		}
+	}
+
+	a.healthy.Store(false)
+	if s.Reader(readCtx) != primary {
+		t.Fatalf("expected reads to fall back to the primary without healthy replicas")
+	}
+}
+
+func TestReaderWithoutReplicas(t *testing.T) {
+	primary := lazyPool(t)
+	s := &service{db: primary, replicas: &replicaSet{}}
+	if s.Reader(WithReadOnly(context.Background())) != primary {
+		t.Fatalf("expected reads to use the primary without replicas")
+	}
+}
+
+func TestReplicaCheckFallsBack(t *testing.T) {
+	primary := lazyPool(t)
+	a, b := &replica{pool: lazyPool(t)}, &replica{pool: lazyPool(t)}
+	type measurement struct {
+		lag time.Duration
+		err error
+	}
+	measured := map[*pgxpool.Pool]measurement{}
+	rs := &replicaSet{
+		replicas:     []*replica{a, b},
+		maxLag:       5 * time.Second,
+		checkTimeout: time.Second,
+		measure: func(_ context.Context, pool *pgxpool.Pool) (time.Duration, error) {
+			m := measured[pool]
+			return m.lag, m.err
+		},
+	}
+	s := &service{db: primary, replicas: rs}
+	readCtx := WithReadOnly(context.Background())
+
+	rs.check(context.Background())
+	if rs.healthyCount() != 2 {
+		t.Fatalf("expected both caught up replicas to be healthy")
+	}
+
+	measured[a.pool] = measurement{lag: 6 * time.Second}
+	rs.check(context.Background())
+	if rs.healthyCount() != 1 || s.Reader(readCtx) != b.pool {
+		t.Fatalf("expected reads to skip the lagging replica")
+	}
+	if time.Duration(a.lag.Load()) != 6*time.Second {
+		t.Fatalf("expected the lag to be recorded, got %v", time.Duration(a.lag.Load()))
+	}
+
+	measured[b.pool] = measurement{err: errNotStreaming}
+	rs.check(context.Background())
+	if rs.healthyCount() != 0 || s.Reader(readCtx) != primary {
+		t.Fatalf("expected reads to fall back to the primary once no replica is healthy")
+	}
+
+	clear(measured)
+	rs.check(context.Background())
+	if rs.healthyCount() != 2 {
+		t.Fatalf("expected recovered replicas back in rotation")
+	}
+}
+
+// TestReplicaHealthCheck uses a second Postgres container as the replica. It
+// is not in recovery, so it never lags; stopping it must take it out of
+// rotation.
+func TestReplicaHealthCheck(t *testing.T) {
+	if !dockerAvailable {
+		t.Skip("Skipping test: Docker not available")
+	}
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx,
+		"postgres:latest",
+		postgres.WithDatabase("replica"),
+		postgres.WithUsername("user"),
+		postgres.WithPassword("password"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second)),
+	)
+	if err != nil {
+		t.Fatalf("could not start replica container: %v", err)
+	}
+	defer container.Terminate(ctx)
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("replica host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		t.Fatalf("replica port: %v", err)
+	}
+
+	cfg := *testConfig
+	cfg.Database.ReplicaURLs = []string{
+		fmt.Sprintf("postgres://user:password@%s:%s/replica?sslmode=disable", host, port.Port()),
+	}
+	cfg.Database.ConnectTimeout = time.Second
+	rs, err := openReplicas(ctx, &cfg)
+	if err != nil {
+		t.Fatalf("openReplicas: %v", err)
+	}
+	defer rs.close()
+
+	if rs.healthyCount() != 1 || rs.pick() == nil {
+		t.Fatalf("expected the replica to be healthy")
+	}
+
+	if err := container.Stop(ctx, nil); err != nil {
+		t.Fatalf("could not stop replica container: %v", err)
+	}
+	rs.check(ctx)
+	if rs.healthyCount() != 0 || rs.pick() != nil {
+		t.Fatalf("expected a stopped replica to be unhealthy")
+	}
+}
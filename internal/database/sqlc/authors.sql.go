@@ -7,12 +7,13 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const countAuthors = `-- name: CountAuthors :one
-SELECT COUNT(*) FROM authors
+SELECT COUNT(*) FROM authors WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountAuthors(ctx context.Context) (int64, error) {
@@ -24,7 +25,8 @@ func (q *Queries) CountAuthors(ctx context.Context) (int64, error) {
 
 const countAuthorsSearch = `-- name: CountAuthorsSearch :one
 SELECT COUNT(*) FROM authors
-WHERE name ILIKE '%' || $1 || '%'
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
 `
 
 func (q *Queries) CountAuthorsSearch(ctx context.Context, dollar_1 *string) (int64, error) {
@@ -37,7 +39,7 @@ func (q *Queries) CountAuthorsSearch(ctx context.Context, dollar_1 *string) (int
 const createAuthor = `-- name: CreateAuthor :one
 INSERT INTO authors (name, slug, bio)
 VALUES ($1, $2, $3)
-RETURNING id, name, slug, bio, created_at, updated_at
+RETURNING id, name, slug, bio, created_at, updated_at, deleted_at
 `
 
 type CreateAuthorParams struct {
@@ -56,21 +58,53 @@ func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Aut
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteAuthor = `-- name: DeleteAuthor :exec
-DELETE FROM authors WHERE id = $1
+const createAuthorsWithSlugs = `-- name: CreateAuthorsWithSlugs :many
+INSERT INTO authors (name, slug)
+SELECT name, NULLIF(slug, '') FROM unnest($1::text[], $2::text[]) AS t(name, slug)
+ON CONFLICT DO NOTHING
+RETURNING id, name, slug, bio, created_at, updated_at, deleted_at;
 `
 
-func (q *Queries) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteAuthor, id)
-	return err
+type CreateAuthorsWithSlugsParams struct {
+	Names []string
+	Slugs []string
+}
+
+func (q *Queries) CreateAuthorsWithSlugs(ctx context.Context, arg CreateAuthorsWithSlugsParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, createAuthorsWithSlugs, arg.Names, arg.Slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getAuthorBookCount = `-- name: GetAuthorBookCount :one
-SELECT COUNT(*) FROM books WHERE author_id = $1
+SELECT COUNT(*) FROM books WHERE author_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetAuthorBookCount(ctx context.Context, authorID uuid.UUID) (int64, error) {
@@ -80,8 +114,67 @@ func (q *Queries) GetAuthorBookCount(ctx context.Context, authorID uuid.UUID) (i
 	return count, err
 }
 
+const getAuthorBookCounts = `-- name: GetAuthorBookCounts :many
+SELECT author_id, COUNT(*) AS book_count FROM books
+WHERE author_id = ANY($1::uuid[])
+  AND deleted_at IS NULL
+GROUP BY author_id
+`
+
+type GetAuthorBookCountsRow struct {
+	AuthorID  uuid.UUID
+	BookCount int64
+}
+
+func (q *Queries) GetAuthorBookCounts(ctx context.Context, authorIDs []uuid.UUID) ([]GetAuthorBookCountsRow, error) {
+	rows, err := q.db.Query(ctx, getAuthorBookCounts, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAuthorBookCountsRow
+	for rows.Next() {
+		var i GetAuthorBookCountsRow
+		if err := rows.Scan(
+			&i.AuthorID,
+			&i.BookCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuthorBookIDs = `-- name: GetAuthorBookIDs :many
+SELECT id FROM books WHERE author_id = $1 AND deleted_at IS NULL ORDER BY id
+`
+
+func (q *Queries) GetAuthorBookIDs(ctx context.Context, authorID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getAuthorBookIDs, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAuthorByID = `-- name: GetAuthorByID :one
-SELECT id, name, slug, bio, created_at, updated_at FROM authors WHERE id = $1
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetAuthorByID(ctx context.Context, id uuid.UUID) (Author, error) {
@@ -94,12 +187,32 @@ func (q *Queries) GetAuthorByID(ctx context.Context, id uuid.UUID) (Author, erro
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getAuthorByIDForUpdate = `-- name: GetAuthorByIDForUpdate :one
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetAuthorByIDForUpdate(ctx context.Context, id uuid.UUID) (Author, error) {
+	row := q.db.QueryRow(ctx, getAuthorByIDForUpdate, id)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getAuthorByName = `-- name: GetAuthorByName :one
-SELECT id, name, slug, bio, created_at, updated_at FROM authors WHERE name = $1
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE name = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetAuthorByName(ctx context.Context, name string) (Author, error) {
@@ -112,12 +225,13 @@ func (q *Queries) GetAuthorByName(ctx context.Context, name string) (Author, err
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getAuthorBySlug = `-- name: GetAuthorBySlug :one
-SELECT id, name, slug, bio, created_at, updated_at FROM authors WHERE slug = $1
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE slug = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetAuthorBySlug(ctx context.Context, slug *string) (Author, error) {
@@ -130,12 +244,160 @@ func (q *Queries) GetAuthorBySlug(ctx context.Context, slug *string) (Author, er
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getAuthorsByIDs = `-- name: GetAuthorsByIDs :many
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetAuthorsByIDs(ctx context.Context, ids []uuid.UUID) ([]Author, error) {
+	rows, err := q.db.Query(ctx, getAuthorsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuthorsByNames = `-- name: GetAuthorsByNames :many
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE name = ANY($1::text[]);
+`
+
+func (q *Queries) GetAuthorsByNames(ctx context.Context, names []string) ([]Author, error) {
+	rows, err := q.db.Query(ctx, getAuthorsByNames, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuthorsByNamesForUpdate = `-- name: GetAuthorsByNamesForUpdate :many
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE name = ANY($1::text[]) FOR UPDATE
+`
+
+func (q *Queries) GetAuthorsByNamesForUpdate(ctx context.Context, names []string) ([]Author, error) {
+	rows, err := q.db.Query(ctx, getAuthorsByNamesForUpdate, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorSlugs = `-- name: ListAuthorSlugs :many
+SELECT slug FROM authors WHERE slug IS NOT NULL;
+`
+
+func (q *Queries) ListAuthorSlugs(ctx context.Context) ([]*string, error) {
+	rows, err := q.db.Query(ctx, listAuthorSlugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*string
+	for rows.Next() {
+		var slug *string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorSlugsWithBases = `-- name: ListAuthorSlugsWithBases :many
+-- The slugs that any of @bases, as is or with a suffix, would collide with.
+SELECT slug FROM authors t
+WHERE EXISTS (SELECT 1 FROM unnest($1::text[]) AS b(base) WHERE t.slug = b.base OR t.slug LIKE b.base || '-%')
+`
+
+func (q *Queries) ListAuthorSlugsWithBases(ctx context.Context, bases []string) ([]*string, error) {
+	rows, err := q.db.Query(ctx, listAuthorSlugsWithBases, bases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*string
+	for rows.Next() {
+		var slug *string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAuthors = `-- name: ListAuthors :many
-SELECT id, name, slug, bio, created_at, updated_at FROM authors
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1 OFFSET $2
 `
@@ -161,6 +423,239 @@ func (q *Queries) ListAuthors(ctx context.Context, arg ListAuthorsParams) ([]Aut
 			&i.Bio,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsPage = `-- name: ListAuthorsPage :many
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors
+WHERE deleted_at IS NULL
+  AND ($1::text = '' OR name ILIKE '%' || $1 || '%')
+  AND ($2::text = '' OR (name, id) > ($2, $3::uuid))
+ORDER BY name, id
+LIMIT $4
+`
+
+type ListAuthorsPageParams struct {
+	Search    string
+	AfterName string
+	AfterID   uuid.UUID
+	PageSize  int32
+}
+
+func (q *Queries) ListAuthorsPage(ctx context.Context, arg ListAuthorsPageParams) ([]Author, error) {
+	rows, err := q.db.Query(ctx, listAuthorsPage,
+		arg.Search,
+		arg.AfterName,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAuthor = `-- name: LockAuthor :one
+SELECT deleted_at FROM authors WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockAuthor(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	row := q.db.QueryRow(ctx, lockAuthor, id)
+	var deleted_at *time.Time
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
+const lockAuthorsByIDs = `-- name: LockAuthorsByIDs :many
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors WHERE id = ANY($1::uuid[]) FOR UPDATE
+`
+
+func (q *Queries) LockAuthorsByIDs(ctx context.Context, ids []uuid.UUID) ([]Author, error) {
+	rows, err := q.db.Query(ctx, lockAuthorsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeAuthor = `-- name: PurgeAuthor :exec
+DELETE FROM authors WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgeAuthor(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, purgeAuthor, id)
+	return err
+}
+
+const purgeAuthorBooks = `-- name: PurgeAuthorBooks :execrows
+DELETE FROM books WHERE author_id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgeAuthorBooks(ctx context.Context, authorID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeAuthorBooks, authorID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reassignAuthorBooks = `-- name: ReassignAuthorBooks :many
+UPDATE books SET author_id = $1::uuid, updated_at = CURRENT_TIMESTAMP
+WHERE author_id = $2::uuid AND deleted_at IS NULL
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+`
+
+type ReassignAuthorBooksParams struct {
+	ReassignTo uuid.UUID
+	AuthorID   uuid.UUID
+}
+
+func (q *Queries) ReassignAuthorBooks(ctx context.Context, arg ReassignAuthorBooksParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, reassignAuthorBooks, arg.ReassignTo, arg.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreAuthor = `-- name: RestoreAuthor :one
+UPDATE authors SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, slug, bio, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) RestoreAuthor(ctx context.Context, id uuid.UUID) (Author, error) {
+	row := q.db.QueryRow(ctx, restoreAuthor, id)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restoreAuthorBooks = `-- name: RestoreAuthorBooks :many
+UPDATE books b SET deleted_at = NULL
+FROM authors a
+WHERE a.id = $1::uuid AND b.author_id = a.id AND b.deleted_at = a.deleted_at
+RETURNING b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at, b.deleted_at
+`
+
+func (q *Queries) RestoreAuthorBooks(ctx context.Context, authorID uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, restoreAuthorBooks, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -173,8 +668,9 @@ func (q *Queries) ListAuthors(ctx context.Context, arg ListAuthorsParams) ([]Aut
 }
 
 const searchAuthors = `-- name: SearchAuthors :many
-SELECT id, name, slug, bio, created_at, updated_at FROM authors
-WHERE name ILIKE '%' || $1 || '%'
+SELECT id, name, slug, bio, created_at, updated_at, deleted_at FROM authors
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
 ORDER BY name
 LIMIT $2 OFFSET $3
 `
@@ -201,6 +697,62 @@ func (q *Queries) SearchAuthors(ctx context.Context, arg SearchAuthorsParams) ([
 			&i.Bio,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trashAuthor = `-- name: TrashAuthor :exec
+UPDATE authors SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+func (q *Queries) TrashAuthor(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, trashAuthor, id)
+	return err
+}
+
+const trashAuthorBooks = `-- name: TrashAuthorBooks :many
+UPDATE books SET deleted_at = CURRENT_TIMESTAMP
+WHERE author_id = $1 AND deleted_at IS NULL
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) TrashAuthorBooks(ctx context.Context, authorID uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, trashAuthorBooks, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -215,8 +767,8 @@ func (q *Queries) SearchAuthors(ctx context.Context, arg SearchAuthorsParams) ([
 const updateAuthor = `-- name: UpdateAuthor :one
 UPDATE authors
 SET name = $2, slug = $3, bio = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, name, slug, bio, created_at, updated_at
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, slug, bio, created_at, updated_at, deleted_at
 `
 
 type UpdateAuthorParams struct {
@@ -241,6 +793,70 @@ func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Aut
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const upsertAuthorByName = `-- name: UpsertAuthorByName :one
+-- Returns the row named @name, inserting it with @slug if there is none.
+-- Trashed rows match too, since names are unique across the trash. An empty
+-- slug is stored as NULL.
+INSERT INTO authors (name, slug) VALUES ($1, NULLIF($2::text, ''))
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name, slug, bio, created_at, updated_at, deleted_at
+`
+
+type UpsertAuthorByNameParams struct {
+	Name string
+	Slug string
+}
+
+func (q *Queries) UpsertAuthorByName(ctx context.Context, arg UpsertAuthorByNameParams) (Author, error) {
+	row := q.db.QueryRow(ctx, upsertAuthorByName, arg.Name, arg.Slug)
+	var i Author
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const upsertAuthorsByName = `-- name: UpsertAuthorsByName :many
+INSERT INTO authors (name) SELECT unnest($1::text[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name, slug, bio, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) UpsertAuthorsByName(ctx context.Context, names []string) ([]Author, error) {
+	rows, err := q.db.Query(ctx, upsertAuthorsByName, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Bio,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
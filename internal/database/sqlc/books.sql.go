@@ -15,6 +15,7 @@ import (
 
 const countBooks = `-- name: CountBooks :one
 SELECT COUNT(*) FROM books
+WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountBooks(ctx context.Context) (int64, error) {
@@ -24,41 +25,6 @@ func (q *Queries) CountBooks(ctx context.Context) (int64, error) {
 	return count, err
 }
 
-const countSearchResults = `-- name: CountSearchResults :one
-SELECT COUNT(*) FROM books b
-LEFT JOIN authors a ON b.author_id = a.id
-WHERE
-  ($1::text = '' OR (b.title ILIKE '%' || $1 || '%' OR a.name ILIKE '%' || $1 || '%' OR b.genres ILIKE '%' || $1 || '%' OR b.tags ILIKE '%' || $1 || '%'))
-  AND ($2::text = '' OR b.author_id::text = $2)
-  AND ($3::text = '' OR b.publisher_id::text = $3)
-  AND ($4::text = '' OR b.series_id::text = $4)
-  AND ($5::text = '' OR a.name ILIKE '%' || $5 || '%')
-  AND ($6::text = '' OR b.genres ILIKE '%' || $6 || '%')
-`
-
-type CountSearchResultsParams struct {
-	Column1 string
-	Column2 string
-	Column3 string
-	Column4 string
-	Column5 string
-	Column6 string
-}
-
-func (q *Queries) CountSearchResults(ctx context.Context, arg CountSearchResultsParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countSearchResults,
-		arg.Column1,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Column5,
-		arg.Column6,
-	)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
 const createBook = `-- name: CreateBook :one
 INSERT INTO books (
   title, subtitle, author_id, publisher_id, published_date,
@@ -66,7 +32,7 @@ INSERT INTO books (
   series_id, series_position, genres, tags, image_url
 )
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
 `
 
 type CreateBookParams struct {
@@ -125,25 +91,52 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, e
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteBook = `-- name: DeleteBook :exec
-DELETE FROM books WHERE id = $1
+const getBookByID = `-- name: GetBookByID :one
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books WHERE id = $1
+  AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteBook(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteBook, id)
-	return err
+func (q *Queries) GetBookByID(ctx context.Context, id uuid.UUID) (Book, error) {
+	row := q.db.QueryRow(ctx, getBookByID, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Subtitle,
+		&i.AuthorID,
+		&i.PublisherID,
+		&i.PublishedDate,
+		&i.Isbn10,
+		&i.Isbn13,
+		&i.Pages,
+		&i.Language,
+		&i.Description,
+		&i.SeriesID,
+		&i.SeriesPosition,
+		&i.Genres,
+		&i.Tags,
+		&i.ImageUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
-const getBookByID = `-- name: GetBookByID :one
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books WHERE id = $1
+const getBookByIDForUpdate = `-- name: GetBookByIDForUpdate :one
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+FROM books
+WHERE id = $1
+  AND deleted_at IS NULL FOR UPDATE
 `
 
-func (q *Queries) GetBookByID(ctx context.Context, id uuid.UUID) (Book, error) {
-	row := q.db.QueryRow(ctx, getBookByID, id)
+func (q *Queries) GetBookByIDForUpdate(ctx context.Context, id uuid.UUID) (Book, error) {
+	row := q.db.QueryRow(ctx, getBookByIDForUpdate, id)
 	var i Book
 	err := row.Scan(
 		&i.ID,
@@ -164,12 +157,14 @@ func (q *Queries) GetBookByID(ctx context.Context, id uuid.UUID) (Book, error) {
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getBookByISBN10 = `-- name: GetBookByISBN10 :one
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books WHERE isbn10 = $1
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books WHERE isbn10 = $1
+  AND deleted_at IS NULL
 `
 
 func (q *Queries) GetBookByISBN10(ctx context.Context, isbn10 *string) (Book, error) {
@@ -194,12 +189,14 @@ func (q *Queries) GetBookByISBN10(ctx context.Context, isbn10 *string) (Book, er
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getBookByISBN13 = `-- name: GetBookByISBN13 :one
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books WHERE isbn13 = $1
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books WHERE isbn13 = $1
+  AND deleted_at IS NULL
 `
 
 func (q *Queries) GetBookByISBN13(ctx context.Context, isbn13 *string) (Book, error) {
@@ -224,13 +221,14 @@ func (q *Queries) GetBookByISBN13(ctx context.Context, isbn13 *string) (Book, er
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getBookWithRelations = `-- name: GetBookWithRelations :one
 SELECT
-  b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at,
+  b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at, b.deleted_at,
   a.name as author_name,
   a.slug as author_slug,
   p.name as publisher_name,
@@ -242,6 +240,7 @@ JOIN authors a ON b.author_id = a.id
 LEFT JOIN publishers p ON b.publisher_id = p.id
 LEFT JOIN series s ON b.series_id = s.id
 WHERE b.id = $1
+  AND b.deleted_at IS NULL
 `
 
 type GetBookWithRelationsRow struct {
@@ -263,6 +262,7 @@ type GetBookWithRelationsRow struct {
 	ImageUrl       *string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	DeletedAt      *time.Time
 	AuthorName     string
 	AuthorSlug     *string
 	PublisherName  *string
@@ -293,6 +293,7 @@ func (q *Queries) GetBookWithRelations(ctx context.Context, id uuid.UUID) (GetBo
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 		&i.AuthorName,
 		&i.AuthorSlug,
 		&i.PublisherName,
@@ -304,8 +305,9 @@ func (q *Queries) GetBookWithRelations(ctx context.Context, id uuid.UUID) (GetBo
 }
 
 const getBooksByAuthor = `-- name: GetBooksByAuthor :many
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books
 WHERE author_id = $1
+  AND deleted_at IS NULL
 ORDER BY published_date DESC NULLS LAST
 `
 
@@ -337,6 +339,244 @@ func (q *Queries) GetBooksByAuthor(ctx context.Context, authorID uuid.UUID) ([]B
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByAuthorIDs = `-- name: GetBooksByAuthorIDs :many
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+FROM books
+WHERE author_id = ANY($1::uuid[])
+  AND deleted_at IS NULL
+ORDER BY author_id,
+  published_date DESC NULLS LAST
+`
+
+func (q *Queries) GetBooksByAuthorIDs(ctx context.Context, authorIDs []uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, getBooksByAuthorIDs, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByGenreIDs = `-- name: GetBooksByGenreIDs :many
+SELECT bg.genre_id, b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at, b.deleted_at
+FROM books b
+  JOIN book_genres bg ON bg.book_id = b.id
+WHERE bg.genre_id = ANY($1::uuid[])
+  AND b.deleted_at IS NULL
+ORDER BY bg.genre_id,
+  b.title
+`
+
+type GetBooksByGenreIDsRow struct {
+	GenreID uuid.UUID
+	Book    Book
+}
+
+func (q *Queries) GetBooksByGenreIDs(ctx context.Context, genreIDs []uuid.UUID) ([]GetBooksByGenreIDsRow, error) {
+	rows, err := q.db.Query(ctx, getBooksByGenreIDs, genreIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBooksByGenreIDsRow
+	for rows.Next() {
+		var i GetBooksByGenreIDsRow
+		if err := rows.Scan(
+			&i.GenreID,
+			&i.Book.ID,
+			&i.Book.Title,
+			&i.Book.Subtitle,
+			&i.Book.AuthorID,
+			&i.Book.PublisherID,
+			&i.Book.PublishedDate,
+			&i.Book.Isbn10,
+			&i.Book.Isbn13,
+			&i.Book.Pages,
+			&i.Book.Language,
+			&i.Book.Description,
+			&i.Book.SeriesID,
+			&i.Book.SeriesPosition,
+			&i.Book.Genres,
+			&i.Book.Tags,
+			&i.Book.ImageUrl,
+			&i.Book.CreatedAt,
+			&i.Book.UpdatedAt,
+			&i.Book.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByIDs = `-- name: GetBooksByIDs :many
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetBooksByIDs(ctx context.Context, ids []uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, getBooksByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByISBN10sForUpdate = `-- name: GetBooksByISBN10sForUpdate :many
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books WHERE isbn10 = ANY($1::text[]) AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetBooksByISBN10sForUpdate(ctx context.Context, isbns []string) ([]Book, error) {
+	rows, err := q.db.Query(ctx, getBooksByISBN10sForUpdate, isbns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByISBN13sForUpdate = `-- name: GetBooksByISBN13sForUpdate :many
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books WHERE isbn13 = ANY($1::text[]) FOR UPDATE
+`
+
+func (q *Queries) GetBooksByISBN13sForUpdate(ctx context.Context, isbns []string) ([]Book, error) {
+	rows, err := q.db.Query(ctx, getBooksByISBN13sForUpdate, isbns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -349,8 +589,9 @@ func (q *Queries) GetBooksByAuthor(ctx context.Context, authorID uuid.UUID) ([]B
 }
 
 const getBooksByPublisher = `-- name: GetBooksByPublisher :many
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books
 WHERE publisher_id = $1
+  AND deleted_at IS NULL
 ORDER BY published_date DESC NULLS LAST
 `
 
@@ -382,6 +623,56 @@ func (q *Queries) GetBooksByPublisher(ctx context.Context, publisherID pgtype.UU
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByPublisherIDs = `-- name: GetBooksByPublisherIDs :many
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+FROM books
+WHERE publisher_id = ANY($1::uuid[])
+  AND deleted_at IS NULL
+ORDER BY publisher_id,
+  published_date DESC NULLS LAST
+`
+
+func (q *Queries) GetBooksByPublisherIDs(ctx context.Context, publisherIDs []uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, getBooksByPublisherIDs, publisherIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -394,8 +685,9 @@ func (q *Queries) GetBooksByPublisher(ctx context.Context, publisherID pgtype.UU
 }
 
 const getBooksBySeries = `-- name: GetBooksBySeries :many
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books
 WHERE series_id = $1
+  AND deleted_at IS NULL
 ORDER BY series_position ASC NULLS LAST
 `
 
@@ -427,6 +719,164 @@ func (q *Queries) GetBooksBySeries(ctx context.Context, seriesID pgtype.UUID) ([
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksBySeriesIDs = `-- name: GetBooksBySeriesIDs :many
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+FROM books
+WHERE series_id = ANY($1::uuid[])
+  AND deleted_at IS NULL
+ORDER BY series_id,
+  series_position ASC NULLS LAST
+`
+
+func (q *Queries) GetBooksBySeriesIDs(ctx context.Context, seriesIDs []uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, getBooksBySeriesIDs, seriesIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByTagIDs = `-- name: GetBooksByTagIDs :many
+SELECT bt.tag_id, b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at, b.deleted_at
+FROM books b
+  JOIN book_tags bt ON bt.book_id = b.id
+WHERE bt.tag_id = ANY($1::uuid[])
+  AND b.deleted_at IS NULL
+ORDER BY bt.tag_id,
+  b.title
+`
+
+type GetBooksByTagIDsRow struct {
+	TagID uuid.UUID
+	Book  Book
+}
+
+func (q *Queries) GetBooksByTagIDs(ctx context.Context, tagIDs []uuid.UUID) ([]GetBooksByTagIDsRow, error) {
+	rows, err := q.db.Query(ctx, getBooksByTagIDs, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBooksByTagIDsRow
+	for rows.Next() {
+		var i GetBooksByTagIDsRow
+		if err := rows.Scan(
+			&i.TagID,
+			&i.Book.ID,
+			&i.Book.Title,
+			&i.Book.Subtitle,
+			&i.Book.AuthorID,
+			&i.Book.PublisherID,
+			&i.Book.PublishedDate,
+			&i.Book.Isbn10,
+			&i.Book.Isbn13,
+			&i.Book.Pages,
+			&i.Book.Language,
+			&i.Book.Description,
+			&i.Book.SeriesID,
+			&i.Book.SeriesPosition,
+			&i.Book.Genres,
+			&i.Book.Tags,
+			&i.Book.ImageUrl,
+			&i.Book.CreatedAt,
+			&i.Book.UpdatedAt,
+			&i.Book.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBooksByTitleAuthorsForUpdate = `-- name: GetBooksByTitleAuthorsForUpdate :many
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books
+WHERE deleted_at IS NULL
+  AND (title, author_id) IN (SELECT unnest($1::text[]), unnest($2::uuid[]))
+FOR UPDATE
+`
+
+type GetBooksByTitleAuthorsForUpdateParams struct {
+	Titles    []string
+	AuthorIds []uuid.UUID
+}
+
+func (q *Queries) GetBooksByTitleAuthorsForUpdate(ctx context.Context, arg GetBooksByTitleAuthorsForUpdateParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, getBooksByTitleAuthorsForUpdate, arg.Titles, arg.AuthorIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -439,8 +889,9 @@ func (q *Queries) GetBooksBySeries(ctx context.Context, seriesID pgtype.UUID) ([
 }
 
 const getRecommendationsByAuthor = `-- name: GetRecommendationsByAuthor :many
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books
 WHERE author_id = $1 AND id != $2
+  AND deleted_at IS NULL
 ORDER BY published_date DESC NULLS LAST
 LIMIT $3
 `
@@ -479,6 +930,7 @@ func (q *Queries) GetRecommendationsByAuthor(ctx context.Context, arg GetRecomme
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -491,8 +943,9 @@ func (q *Queries) GetRecommendationsByAuthor(ctx context.Context, arg GetRecomme
 }
 
 const getRecommendationsBySeries = `-- name: GetRecommendationsBySeries :many
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books
 WHERE series_id = $1 AND id != $2
+  AND deleted_at IS NULL
 ORDER BY series_position ASC NULLS LAST
 LIMIT $3
 `
@@ -531,6 +984,7 @@ func (q *Queries) GetRecommendationsBySeries(ctx context.Context, arg GetRecomme
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -543,27 +997,23 @@ func (q *Queries) GetRecommendationsBySeries(ctx context.Context, arg GetRecomme
 }
 
 const getRecommendationsByTags = `-- name: GetRecommendationsByTags :many
-SELECT b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at,
-  (
-    SELECT COUNT(*)
-    FROM unnest(string_to_array($2::text, ',')) AS t(tag)
-    WHERE b.tags ILIKE '%' || trim(t.tag) || '%'
-  ) as tag_matches
+SELECT b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at, b.deleted_at,
+  COUNT(*) as tag_matches
 FROM books b
+  JOIN book_tags bt ON bt.book_id = b.id
+  JOIN book_tags source ON source.tag_id = bt.tag_id
 WHERE b.id != $1
-  AND b.tags IS NOT NULL
-  AND EXISTS (
-    SELECT 1 FROM unnest(string_to_array($2::text, ',')) AS t(tag)
-    WHERE b.tags ILIKE '%' || trim(t.tag) || '%'
-  )
-ORDER BY tag_matches DESC, created_at DESC
-LIMIT $3
+  AND source.book_id = $1
+  AND b.deleted_at IS NULL
+GROUP BY b.id
+ORDER BY tag_matches DESC,
+  b.created_at DESC
+LIMIT $2
 `
 
 type GetRecommendationsByTagsParams struct {
-	ID      uuid.UUID
-	Column2 string
-	Limit   int32
+	ID    uuid.UUID
+	Limit int32
 }
 
 type GetRecommendationsByTagsRow struct {
@@ -585,11 +1035,12 @@ type GetRecommendationsByTagsRow struct {
 	ImageUrl       *string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	DeletedAt      *time.Time
 	TagMatches     int64
 }
 
 func (q *Queries) GetRecommendationsByTags(ctx context.Context, arg GetRecommendationsByTagsParams) ([]GetRecommendationsByTagsRow, error) {
-	rows, err := q.db.Query(ctx, getRecommendationsByTags, arg.ID, arg.Column2, arg.Limit)
+	rows, err := q.db.Query(ctx, getRecommendationsByTags, arg.ID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -616,6 +1067,7 @@ func (q *Queries) GetRecommendationsByTags(ctx context.Context, arg GetRecommend
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 			&i.TagMatches,
 		); err != nil {
 			return nil, err
@@ -629,7 +1081,8 @@ func (q *Queries) GetRecommendationsByTags(ctx context.Context, arg GetRecommend
 }
 
 const listBooks = `-- name: ListBooks :many
-SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at FROM books
+SELECT id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at FROM books
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -667,6 +1120,7 @@ func (q *Queries) ListBooks(ctx context.Context, arg ListBooksParams) ([]Book, e
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -678,87 +1132,74 @@ func (q *Queries) ListBooks(ctx context.Context, arg ListBooksParams) ([]Book, e
 	return items, nil
 }
 
-const searchBooks = `-- name: SearchBooks :many
-SELECT b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at FROM books b
-LEFT JOIN authors a ON b.author_id = a.id
-LEFT JOIN publishers p ON b.publisher_id = p.id
-LEFT JOIN series s ON b.series_id = s.id
-WHERE
-  ($1::text = '' OR (b.title ILIKE '%' || $1 || '%' OR a.name ILIKE '%' || $1 || '%' OR b.genres ILIKE '%' || $1 || '%' OR b.tags ILIKE '%' || $1 || '%'))
-  AND ($2::text = '' OR b.author_id::text = $2)
-  AND ($3::text = '' OR b.publisher_id::text = $3)
-  AND ($4::text = '' OR b.series_id::text = $4)
-  AND ($5::text = '' OR a.name ILIKE '%' || $5 || '%')
-  AND ($6::text = '' OR b.genres ILIKE '%' || $6 || '%')
-ORDER BY
-  CASE WHEN $7 = 'title_asc' THEN b.title END ASC,
-  CASE WHEN $7 = 'title_desc' THEN b.title END DESC,
-  CASE WHEN $7 = 'date_asc' THEN b.published_date END ASC NULLS LAST,
-  CASE WHEN $7 = 'date_desc' THEN b.published_date END DESC NULLS LAST,
-  CASE WHEN $7 = 'author' THEN a.name END ASC,
-  b.created_at DESC
-LIMIT $8 OFFSET $9
+const lockBook = `-- name: LockBook :one
+SELECT deleted_at
+FROM books
+WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockBook(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	row := q.db.QueryRow(ctx, lockBook, id)
+	var deleted_at *time.Time
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
+const purgeBook = `-- name: PurgeBook :exec
+DELETE FROM books
+WHERE id = $1
+  AND deleted_at IS NOT NULL
 `
 
-type SearchBooksParams struct {
-	Column1 string
-	Column2 string
-	Column3 string
-	Column4 string
-	Column5 string
-	Column6 string
-	Column7 interface{}
-	Limit   int32
-	Offset  int32
-}
-
-func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]Book, error) {
-	rows, err := q.db.Query(ctx, searchBooks,
-		arg.Column1,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Column5,
-		arg.Column6,
-		arg.Column7,
-		arg.Limit,
-		arg.Offset,
+func (q *Queries) PurgeBook(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, purgeBook, id)
+	return err
+}
+
+const restoreBook = `-- name: RestoreBook :one
+UPDATE books
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) RestoreBook(ctx context.Context, id uuid.UUID) (Book, error) {
+	row := q.db.QueryRow(ctx, restoreBook, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Subtitle,
+		&i.AuthorID,
+		&i.PublisherID,
+		&i.PublishedDate,
+		&i.Isbn10,
+		&i.Isbn13,
+		&i.Pages,
+		&i.Language,
+		&i.Description,
+		&i.SeriesID,
+		&i.SeriesPosition,
+		&i.Genres,
+		&i.Tags,
+		&i.ImageUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Book
-	for rows.Next() {
-		var i Book
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Subtitle,
-			&i.AuthorID,
-			&i.PublisherID,
-			&i.PublishedDate,
-			&i.Isbn10,
-			&i.Isbn13,
-			&i.Pages,
-			&i.Language,
-			&i.Description,
-			&i.SeriesID,
-			&i.SeriesPosition,
-			&i.Genres,
-			&i.Tags,
-			&i.ImageUrl,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return i, err
+}
+
+const trashBook = `-- name: TrashBook :exec
+UPDATE books
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) TrashBook(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, trashBook, id)
+	return err
 }
 
 const updateBook = `-- name: UpdateBook :one
@@ -769,7 +1210,8 @@ SET
   language = $10, description = $11, series_id = $12, series_position = $13,
   genres = $14, tags = $15, image_url = $16, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at
+  AND deleted_at IS NULL
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
 `
 
 type UpdateBookParams struct {
@@ -830,6 +1272,7 @@ func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (Book, e
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
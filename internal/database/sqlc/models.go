@@ -11,6 +11,29 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	TokenHash  []byte
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+type AuditEvent struct {
+	ID         uuid.UUID
+	EntityType string
+	EntityID   uuid.UUID
+	Action     string
+	Diff       []byte
+	Actor      string
+	RequestID  *string
+	RemoteAddr *string
+	CreatedAt  time.Time
+}
+
 type Author struct {
 	ID        uuid.UUID
 	Name      string
@@ -18,6 +41,7 @@ type Author struct {
 	Bio       *string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time
 }
 
 type Book struct {
@@ -39,6 +63,49 @@ type Book struct {
 	ImageUrl       *string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	DeletedAt      *time.Time
+}
+
+type BookGenre struct {
+	BookID  uuid.UUID
+	GenreID uuid.UUID
+}
+
+type BookSearchDocument struct {
+	BookID   uuid.UUID
+	Document interface{}
+}
+
+type BookTag struct {
+	BookID uuid.UUID
+	TagID  uuid.UUID
+}
+
+type EntityRevision struct {
+	ID         uuid.UUID
+	EntityType string
+	EntityID   uuid.UUID
+	Revision   int32
+	Snapshot   []byte
+	Actor      string
+	RequestID  *string
+	CreatedAt  time.Time
+}
+
+type Genre struct {
+	ID        uuid.UUID
+	Name      string
+	Slug      string
+	CreatedAt time.Time
+}
+
+type IdempotencyKey struct {
+	Actor       string
+	Key         string
+	Operation   string
+	RequestHash []byte
+	Response    []byte
+	CreatedAt   time.Time
 }
 
 type Publisher struct {
@@ -48,6 +115,28 @@ type Publisher struct {
 	Website   *string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash []byte
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+type SeedCheckpoint struct {
+	Source         string
+	FileSize       int64
+	FileModifiedAt time.Time
+	ByteOffset     int64
+	RowsRead       int64
+	BooksInserted  int64
+	RowsSkipped    int64
+	UpdatedAt      time.Time
 }
 
 type Series struct {
@@ -57,4 +146,22 @@ type Series struct {
 	Description *string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+}
+
+type Tag struct {
+	ID        uuid.UUID
+	Name      string
+	Slug      string
+	CreatedAt time.Time
+}
+
+type User struct {
+	ID           uuid.UUID
+	Email        string
+	Name         string
+	PasswordHash string
+	Role         string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
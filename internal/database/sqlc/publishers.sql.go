@@ -7,13 +7,14 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countPublishers = `-- name: CountPublishers :one
-SELECT COUNT(*) FROM publishers
+SELECT COUNT(*) FROM publishers WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountPublishers(ctx context.Context) (int64, error) {
@@ -23,10 +24,23 @@ func (q *Queries) CountPublishers(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countPublishersSearch = `-- name: CountPublishersSearch :one
+SELECT COUNT(*) FROM publishers
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
+`
+
+func (q *Queries) CountPublishersSearch(ctx context.Context, dollar_1 *string) (int64, error) {
+	row := q.db.QueryRow(ctx, countPublishersSearch, dollar_1)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPublisher = `-- name: CreatePublisher :one
 INSERT INTO publishers (name, slug, website)
 VALUES ($1, $2, $3)
-RETURNING id, name, slug, website, created_at, updated_at
+RETURNING id, name, slug, website, created_at, updated_at, deleted_at
 `
 
 type CreatePublisherParams struct {
@@ -45,21 +59,53 @@ func (q *Queries) CreatePublisher(ctx context.Context, arg CreatePublisherParams
 		&i.Website,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deletePublisher = `-- name: DeletePublisher :exec
-DELETE FROM publishers WHERE id = $1
+const createPublishersWithSlugs = `-- name: CreatePublishersWithSlugs :many
+INSERT INTO publishers (name, slug)
+SELECT name, NULLIF(slug, '') FROM unnest($1::text[], $2::text[]) AS t(name, slug)
+ON CONFLICT DO NOTHING
+RETURNING id, name, slug, website, created_at, updated_at, deleted_at;
 `
 
-func (q *Queries) DeletePublisher(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deletePublisher, id)
-	return err
+type CreatePublishersWithSlugsParams struct {
+	Names []string
+	Slugs []string
+}
+
+func (q *Queries) CreatePublishersWithSlugs(ctx context.Context, arg CreatePublishersWithSlugsParams) ([]Publisher, error) {
+	rows, err := q.db.Query(ctx, createPublishersWithSlugs, arg.Names, arg.Slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Website,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getPublisherBookCount = `-- name: GetPublisherBookCount :one
-SELECT COUNT(*) FROM books WHERE publisher_id = $1
+SELECT COUNT(*) FROM books WHERE publisher_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPublisherBookCount(ctx context.Context, publisherID pgtype.UUID) (int64, error) {
@@ -69,8 +115,67 @@ func (q *Queries) GetPublisherBookCount(ctx context.Context, publisherID pgtype.
 	return count, err
 }
 
+const getPublisherBookCounts = `-- name: GetPublisherBookCounts :many
+SELECT publisher_id, COUNT(*) AS book_count FROM books
+WHERE publisher_id = ANY($1::uuid[])
+  AND deleted_at IS NULL
+GROUP BY publisher_id
+`
+
+type GetPublisherBookCountsRow struct {
+	PublisherID pgtype.UUID
+	BookCount   int64
+}
+
+func (q *Queries) GetPublisherBookCounts(ctx context.Context, publisherIDs []uuid.UUID) ([]GetPublisherBookCountsRow, error) {
+	rows, err := q.db.Query(ctx, getPublisherBookCounts, publisherIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublisherBookCountsRow
+	for rows.Next() {
+		var i GetPublisherBookCountsRow
+		if err := rows.Scan(
+			&i.PublisherID,
+			&i.BookCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublisherBookIDs = `-- name: GetPublisherBookIDs :many
+SELECT id FROM books WHERE publisher_id = $1::uuid AND deleted_at IS NULL ORDER BY id
+`
+
+func (q *Queries) GetPublisherBookIDs(ctx context.Context, publisherID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getPublisherBookIDs, publisherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPublisherByID = `-- name: GetPublisherByID :one
-SELECT id, name, slug, website, created_at, updated_at FROM publishers WHERE id = $1
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPublisherByID(ctx context.Context, id uuid.UUID) (Publisher, error) {
@@ -83,12 +188,32 @@ func (q *Queries) GetPublisherByID(ctx context.Context, id uuid.UUID) (Publisher
 		&i.Website,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getPublisherByIDForUpdate = `-- name: GetPublisherByIDForUpdate :one
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetPublisherByIDForUpdate(ctx context.Context, id uuid.UUID) (Publisher, error) {
+	row := q.db.QueryRow(ctx, getPublisherByIDForUpdate, id)
+	var i Publisher
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Website,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getPublisherByName = `-- name: GetPublisherByName :one
-SELECT id, name, slug, website, created_at, updated_at FROM publishers WHERE name = $1
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE name = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPublisherByName(ctx context.Context, name string) (Publisher, error) {
@@ -101,12 +226,13 @@ func (q *Queries) GetPublisherByName(ctx context.Context, name string) (Publishe
 		&i.Website,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getPublisherBySlug = `-- name: GetPublisherBySlug :one
-SELECT id, name, slug, website, created_at, updated_at FROM publishers WHERE slug = $1
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE slug = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPublisherBySlug(ctx context.Context, slug *string) (Publisher, error) {
@@ -119,12 +245,160 @@ func (q *Queries) GetPublisherBySlug(ctx context.Context, slug *string) (Publish
 		&i.Website,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getPublishersByIDs = `-- name: GetPublishersByIDs :many
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetPublishersByIDs(ctx context.Context, ids []uuid.UUID) ([]Publisher, error) {
+	rows, err := q.db.Query(ctx, getPublishersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Website,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishersByNames = `-- name: GetPublishersByNames :many
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE name = ANY($1::text[]);
+`
+
+func (q *Queries) GetPublishersByNames(ctx context.Context, names []string) ([]Publisher, error) {
+	rows, err := q.db.Query(ctx, getPublishersByNames, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Website,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishersByNamesForUpdate = `-- name: GetPublishersByNamesForUpdate :many
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE name = ANY($1::text[]) FOR UPDATE
+`
+
+func (q *Queries) GetPublishersByNamesForUpdate(ctx context.Context, names []string) ([]Publisher, error) {
+	rows, err := q.db.Query(ctx, getPublishersByNamesForUpdate, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Website,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublisherSlugs = `-- name: ListPublisherSlugs :many
+SELECT slug FROM publishers WHERE slug IS NOT NULL;
+`
+
+func (q *Queries) ListPublisherSlugs(ctx context.Context) ([]*string, error) {
+	rows, err := q.db.Query(ctx, listPublisherSlugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*string
+	for rows.Next() {
+		var slug *string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublisherSlugsWithBases = `-- name: ListPublisherSlugsWithBases :many
+-- The slugs that any of @bases, as is or with a suffix, would collide with.
+SELECT slug FROM publishers t
+WHERE EXISTS (SELECT 1 FROM unnest($1::text[]) AS b(base) WHERE t.slug = b.base OR t.slug LIKE b.base || '-%')
+`
+
+func (q *Queries) ListPublisherSlugsWithBases(ctx context.Context, bases []string) ([]*string, error) {
+	rows, err := q.db.Query(ctx, listPublisherSlugsWithBases, bases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*string
+	for rows.Next() {
+		var slug *string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPublishers = `-- name: ListPublishers :many
-SELECT id, name, slug, website, created_at, updated_at FROM publishers
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1 OFFSET $2
 `
@@ -150,6 +424,145 @@ func (q *Queries) ListPublishers(ctx context.Context, arg ListPublishersParams)
 			&i.Website,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublishersPage = `-- name: ListPublishersPage :many
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers
+WHERE deleted_at IS NULL
+  AND ($1::text = '' OR name ILIKE '%' || $1 || '%')
+  AND ($2::text = '' OR (name, id) > ($2, $3::uuid))
+ORDER BY name, id
+LIMIT $4
+`
+
+type ListPublishersPageParams struct {
+	Search    string
+	AfterName string
+	AfterID   uuid.UUID
+	PageSize  int32
+}
+
+func (q *Queries) ListPublishersPage(ctx context.Context, arg ListPublishersPageParams) ([]Publisher, error) {
+	rows, err := q.db.Query(ctx, listPublishersPage,
+		arg.Search,
+		arg.AfterName,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Website,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPublisher = `-- name: LockPublisher :one
+SELECT deleted_at FROM publishers WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockPublisher(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	row := q.db.QueryRow(ctx, lockPublisher, id)
+	var deleted_at *time.Time
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
+const lockPublishersByIDs = `-- name: LockPublishersByIDs :many
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers WHERE id = ANY($1::uuid[]) FOR UPDATE
+`
+
+func (q *Queries) LockPublishersByIDs(ctx context.Context, ids []uuid.UUID) ([]Publisher, error) {
+	rows, err := q.db.Query(ctx, lockPublishersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Website,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nullifyPublisherBooks = `-- name: NullifyPublisherBooks :many
+UPDATE books SET publisher_id = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE publisher_id = $1::uuid
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) NullifyPublisherBooks(ctx context.Context, publisherID uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, nullifyPublisherBooks, publisherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -161,9 +574,91 @@ func (q *Queries) ListPublishers(ctx context.Context, arg ListPublishersParams)
 	return items, nil
 }
 
+const purgePublisher = `-- name: PurgePublisher :exec
+DELETE FROM publishers WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgePublisher(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, purgePublisher, id)
+	return err
+}
+
+const reassignPublisherBooks = `-- name: ReassignPublisherBooks :many
+UPDATE books SET publisher_id = $1::uuid, updated_at = CURRENT_TIMESTAMP
+WHERE publisher_id = $2::uuid AND deleted_at IS NULL
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+`
+
+type ReassignPublisherBooksParams struct {
+	ReassignTo  uuid.UUID
+	PublisherID uuid.UUID
+}
+
+func (q *Queries) ReassignPublisherBooks(ctx context.Context, arg ReassignPublisherBooksParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, reassignPublisherBooks, arg.ReassignTo, arg.PublisherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restorePublisher = `-- name: RestorePublisher :one
+UPDATE publishers SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, slug, website, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) RestorePublisher(ctx context.Context, id uuid.UUID) (Publisher, error) {
+	row := q.db.QueryRow(ctx, restorePublisher, id)
+	var i Publisher
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Website,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const searchPublishers = `-- name: SearchPublishers :many
-SELECT id, name, slug, website, created_at, updated_at FROM publishers
-WHERE name ILIKE '%' || $1 || '%'
+SELECT id, name, slug, website, created_at, updated_at, deleted_at FROM publishers
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
 ORDER BY name
 LIMIT $2 OFFSET $3
 `
@@ -190,6 +685,7 @@ func (q *Queries) SearchPublishers(ctx context.Context, arg SearchPublishersPara
 			&i.Website,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -201,11 +697,20 @@ func (q *Queries) SearchPublishers(ctx context.Context, arg SearchPublishersPara
 	return items, nil
 }
 
+const trashPublisher = `-- name: TrashPublisher :exec
+UPDATE publishers SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+func (q *Queries) TrashPublisher(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, trashPublisher, id)
+	return err
+}
+
 const updatePublisher = `-- name: UpdatePublisher :one
 UPDATE publishers
 SET name = $2, slug = $3, website = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, name, slug, website, created_at, updated_at
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, slug, website, created_at, updated_at, deleted_at
 `
 
 type UpdatePublisherParams struct {
@@ -230,6 +735,70 @@ func (q *Queries) UpdatePublisher(ctx context.Context, arg UpdatePublisherParams
 		&i.Website,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const upsertPublisherByName = `-- name: UpsertPublisherByName :one
+-- Returns the row named @name, inserting it with @slug if there is none.
+-- Trashed rows match too, since names are unique across the trash. An empty
+-- slug is stored as NULL.
+INSERT INTO publishers (name, slug) VALUES ($1, NULLIF($2::text, ''))
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name, slug, website, created_at, updated_at, deleted_at
+`
+
+type UpsertPublisherByNameParams struct {
+	Name string
+	Slug string
+}
+
+func (q *Queries) UpsertPublisherByName(ctx context.Context, arg UpsertPublisherByNameParams) (Publisher, error) {
+	row := q.db.QueryRow(ctx, upsertPublisherByName, arg.Name, arg.Slug)
+	var i Publisher
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Website,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const upsertPublishersByName = `-- name: UpsertPublishersByName :many
+INSERT INTO publishers (name) SELECT unnest($1::text[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name, slug, website, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) UpsertPublishersByName(ctx context.Context, names []string) ([]Publisher, error) {
+	rows, err := q.db.Query(ctx, upsertPublishersByName, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Website,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,31 @@
+-- name: CreateApiToken :one
+INSERT INTO api_tokens (user_id, name, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetApiTokenByID :one
+SELECT * FROM api_tokens WHERE id = $1;
+
+-- name: GetApiTokenByIDForUpdate :one
+SELECT * FROM api_tokens WHERE id = $1 FOR UPDATE;
+
+-- name: ListApiTokensByUser :many
+SELECT * FROM api_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: RevokeApiToken :execrows
+UPDATE api_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: AuthenticateApiToken :one
+SELECT t.id AS token_id, t.last_used_at, u.id AS user_id, u.email, u.name, u.role
+FROM api_tokens t
+JOIN users u ON u.id = t.user_id
+WHERE t.token_hash = $1
+  AND t.revoked_at IS NULL
+  AND (t.expires_at IS NULL OR t.expires_at > CURRENT_TIMESTAMP);
+
+-- name: TouchApiToken :exec
+UPDATE api_tokens SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1;
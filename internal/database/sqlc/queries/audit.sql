@@ -0,0 +1,13 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (entity_type, entity_id, action, diff, actor, request_id, remote_addr)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListAuditEventsPage :many
+SELECT * FROM audit_events
+WHERE (@entity_id::uuid = '00000000-0000-0000-0000-000000000000' OR entity_id = @entity_id)
+  AND (@entity_type::text = '' OR entity_type = @entity_type)
+  AND created_at >= @since::timestamptz
+  AND (@after_id::uuid = '00000000-0000-0000-0000-000000000000'
+    OR (created_at, id) < (@after_created_at::timestamptz, @after_id))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_size;
@@ -1,29 +1,43 @@
 -- name: GetAuthorByID :one
-SELECT * FROM authors WHERE id = $1;
+SELECT * FROM authors WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetAuthorsByIDs :many
+SELECT * FROM authors WHERE id = ANY(@ids::uuid[]) AND deleted_at IS NULL;
 
 -- name: GetAuthorBySlug :one
-SELECT * FROM authors WHERE slug = $1;
+SELECT * FROM authors WHERE slug = $1 AND deleted_at IS NULL;
 
 -- name: GetAuthorByName :one
-SELECT * FROM authors WHERE name = $1;
+SELECT * FROM authors WHERE name = $1 AND deleted_at IS NULL;
 
 -- name: ListAuthors :many
 SELECT * FROM authors
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1 OFFSET $2;
 
+-- name: ListAuthorsPage :many
+SELECT * FROM authors
+WHERE deleted_at IS NULL
+  AND (@search::text = '' OR name ILIKE '%' || @search || '%')
+  AND (@after_name::text = '' OR (name, id) > (@after_name, @after_id::uuid))
+ORDER BY name, id
+LIMIT @page_size;
+
 -- name: SearchAuthors :many
 SELECT * FROM authors
-WHERE name ILIKE '%' || $1 || '%'
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
 ORDER BY name
 LIMIT $2 OFFSET $3;
 
 -- name: CountAuthors :one
-SELECT COUNT(*) FROM authors;
+SELECT COUNT(*) FROM authors WHERE deleted_at IS NULL;
 
 -- name: CountAuthorsSearch :one
 SELECT COUNT(*) FROM authors
-WHERE name ILIKE '%' || $1 || '%';
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%';
 
 -- name: CreateAuthor :one
 INSERT INTO authors (name, slug, bio)
@@ -33,11 +47,91 @@ RETURNING *;
 -- name: UpdateAuthor :one
 UPDATE authors
 SET name = $2, slug = $3, bio = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteAuthor :exec
-DELETE FROM authors WHERE id = $1;
+-- name: TrashAuthor :exec
+UPDATE authors SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: RestoreAuthor :one
+UPDATE authors SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeAuthor :exec
+DELETE FROM authors WHERE id = $1 AND deleted_at IS NOT NULL;
 
 -- name: GetAuthorBookCount :one
-SELECT COUNT(*) FROM books WHERE author_id = $1;
+SELECT COUNT(*) FROM books WHERE author_id = $1 AND deleted_at IS NULL;
+
+-- name: GetAuthorBookCounts :many
+SELECT author_id, COUNT(*) AS book_count FROM books
+WHERE author_id = ANY(@author_ids::uuid[])
+  AND deleted_at IS NULL
+GROUP BY author_id;
+
+-- name: LockAuthor :one
+SELECT deleted_at FROM authors WHERE id = $1 FOR UPDATE;
+
+-- name: GetAuthorBookIDs :many
+SELECT id FROM books WHERE author_id = $1 AND deleted_at IS NULL ORDER BY id;
+
+-- name: ReassignAuthorBooks :many
+UPDATE books SET author_id = @reassign_to::uuid, updated_at = CURRENT_TIMESTAMP
+WHERE author_id = @author_id::uuid AND deleted_at IS NULL
+RETURNING *;
+
+-- name: TrashAuthorBooks :many
+UPDATE books SET deleted_at = CURRENT_TIMESTAMP
+WHERE author_id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreAuthorBooks :many
+UPDATE books b SET deleted_at = NULL
+FROM authors a
+WHERE a.id = @author_id::uuid AND b.author_id = a.id AND b.deleted_at = a.deleted_at
+RETURNING b.*;
+
+-- name: PurgeAuthorBooks :execrows
+DELETE FROM books WHERE author_id = $1 AND deleted_at IS NOT NULL;
+
+-- name: GetAuthorByIDForUpdate :one
+SELECT * FROM authors WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- name: UpsertAuthorByName :one
+-- Returns the row named @name, inserting it with @slug if there is none.
+-- Trashed rows match too, since names are unique across the trash. An empty
+-- slug is stored as NULL.
+INSERT INTO authors (name, slug) VALUES (@name, NULLIF(@slug::text, ''))
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: LockAuthorsByIDs :many
+SELECT * FROM authors WHERE id = ANY(@ids::uuid[]) FOR UPDATE;
+
+-- name: GetAuthorsByNamesForUpdate :many
+SELECT * FROM authors WHERE name = ANY(@names::text[]) FOR UPDATE;
+
+-- name: UpsertAuthorsByName :many
+INSERT INTO authors (name) SELECT unnest(@names::text[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetAuthorsByNames :many
+SELECT * FROM authors WHERE name = ANY(@names::text[]);
+
+-- name: ListAuthorSlugs :many
+SELECT slug FROM authors WHERE slug IS NOT NULL;
+
+-- name: ListAuthorSlugsWithBases :many
+-- The slugs that any of @bases, as is or with a suffix, would collide with.
+SELECT slug FROM authors t
+WHERE EXISTS (SELECT 1 FROM unnest(@bases::text[]) AS b(base) WHERE t.slug = b.base OR t.slug LIKE b.base || '-%');
+
+-- name: CreateAuthorsWithSlugs :many
+-- Rows whose name or slug is taken by then are left out of the result.
+-- An empty slug is stored as NULL.
+INSERT INTO authors (name, slug)
+SELECT name, NULLIF(slug, '') FROM unnest(@names::text[], @slugs::text[]) AS t(name, slug)
+ON CONFLICT DO NOTHING
+RETURNING *;
@@ -1,123 +1,88 @@
 -- name: GetBookByID :one
 SELECT *
 FROM books
-WHERE id = $1;
+WHERE id = $1
+  AND deleted_at IS NULL;
+-- name: GetBookByIDForUpdate :one
+SELECT *
+FROM books
+WHERE id = $1
+  AND deleted_at IS NULL FOR UPDATE;
 -- name: ListBooks :many
 SELECT *
 FROM books
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 -- name: CountBooks :one
 SELECT COUNT(*)
-FROM books;
--- name: SearchBooks :many
-SELECT b.*
-FROM books b
-  LEFT JOIN authors a ON b.author_id = a.id
-  LEFT JOIN publishers p ON b.publisher_id = p.id
-  LEFT JOIN series s ON b.series_id = s.id
-WHERE (
-    $1::text = ''
-    OR (
-      b.title ILIKE '%' || $1 || '%'
-      OR a.name ILIKE '%' || $1 || '%'
-      OR b.genres ILIKE '%' || $1 || '%'
-      OR b.tags ILIKE '%' || $1 || '%'
-    )
-  )
-  AND (
-    $2::text = ''
-    OR b.author_id::text = $2
-  )
-  AND (
-    $3::text = ''
-    OR b.publisher_id::text = $3
-  )
-  AND (
-    $4::text = ''
-    OR b.series_id::text = $4
-  )
-  AND (
-    $5::text = ''
-    OR a.name ILIKE '%' || $5 || '%'
-  )
-  AND (
-    $6::text = ''
-    OR b.genres ILIKE '%' || $6 || '%'
-  )
-ORDER BY CASE
-    WHEN $7 = 'title_asc' THEN b.title
-  END ASC,
-  CASE
-    WHEN $7 = 'title_desc' THEN b.title
-  END DESC,
-  CASE
-    WHEN $7 = 'date_asc' THEN b.published_date
-  END ASC NULLS LAST,
-  CASE
-    WHEN $7 = 'date_desc' THEN b.published_date
-  END DESC NULLS LAST,
-  CASE
-    WHEN $7 = 'author' THEN a.name
-  END ASC,
-  b.created_at DESC
-LIMIT $8 OFFSET $9;
--- name: CountSearchResults :one
-SELECT COUNT(*)
-FROM books b
-  LEFT JOIN authors a ON b.author_id = a.id
-WHERE (
-    $1::text = ''
-    OR (
-      b.title ILIKE '%' || $1 || '%'
-      OR a.name ILIKE '%' || $1 || '%'
-      OR b.genres ILIKE '%' || $1 || '%'
-      OR b.tags ILIKE '%' || $1 || '%'
-    )
-  )
-  AND (
-    $2::text = ''
-    OR b.author_id::text = $2
-  )
-  AND (
-    $3::text = ''
-    OR b.publisher_id::text = $3
-  )
-  AND (
-    $4::text = ''
-    OR b.series_id::text = $4
-  )
-  AND (
-    $5::text = ''
-    OR a.name ILIKE '%' || $5 || '%'
-  )
-  AND (
-    $6::text = ''
-    OR b.genres ILIKE '%' || $6 || '%'
-  );
+FROM books
+WHERE deleted_at IS NULL;
 -- name: GetBooksByAuthor :many
 SELECT *
 FROM books
 WHERE author_id = $1
+  AND deleted_at IS NULL
 ORDER BY published_date DESC NULLS LAST;
+-- name: GetBooksByAuthorIDs :many
+SELECT *
+FROM books
+WHERE author_id = ANY(@author_ids::uuid[])
+  AND deleted_at IS NULL
+ORDER BY author_id,
+  published_date DESC NULLS LAST;
+-- name: GetBooksByGenreIDs :many
+SELECT bg.genre_id, sqlc.embed(b)
+FROM books b
+  JOIN book_genres bg ON bg.book_id = b.id
+WHERE bg.genre_id = ANY(@genre_ids::uuid[])
+  AND b.deleted_at IS NULL
+ORDER BY bg.genre_id,
+  b.title;
 -- name: GetBooksByPublisher :many
 SELECT *
 FROM books
 WHERE publisher_id = $1
+  AND deleted_at IS NULL
 ORDER BY published_date DESC NULLS LAST;
+-- name: GetBooksByPublisherIDs :many
+SELECT *
+FROM books
+WHERE publisher_id = ANY(@publisher_ids::uuid[])
+  AND deleted_at IS NULL
+ORDER BY publisher_id,
+  published_date DESC NULLS LAST;
+-- name: GetBooksByTagIDs :many
+SELECT bt.tag_id, sqlc.embed(b)
+FROM books b
+  JOIN book_tags bt ON bt.book_id = b.id
+WHERE bt.tag_id = ANY(@tag_ids::uuid[])
+  AND b.deleted_at IS NULL
+ORDER BY bt.tag_id,
+  b.title;
 -- name: GetBooksBySeries :many
 SELECT *
 FROM books
 WHERE series_id = $1
+  AND deleted_at IS NULL
 ORDER BY series_position ASC NULLS LAST;
+-- name: GetBooksBySeriesIDs :many
+SELECT *
+FROM books
+WHERE series_id = ANY(@series_ids::uuid[])
+  AND deleted_at IS NULL
+ORDER BY series_id,
+  series_position ASC NULLS LAST;
 -- name: GetBookByISBN13 :one
 SELECT *
 FROM books
-WHERE isbn13 = $1;
+WHERE isbn13 = $1
+  AND deleted_at IS NULL;
 -- name: GetBookByISBN10 :one
 SELECT *
 FROM books
-WHERE isbn10 = $1;
+WHERE isbn10 = $1
+  AND deleted_at IS NULL;
 -- name: CreateBook :one
 INSERT INTO books (
     title,
@@ -173,10 +138,26 @@ SET title = $2,
   image_url = $16,
   updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
+  AND deleted_at IS NULL
 RETURNING *;
--- name: DeleteBook :exec
-DELETE FROM books
+-- name: LockBook :one
+SELECT deleted_at
+FROM books
+WHERE id = $1 FOR UPDATE;
+-- name: TrashBook :exec
+UPDATE books
+SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1;
+-- name: RestoreBook :one
+UPDATE books
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+RETURNING *;
+-- name: PurgeBook :exec
+DELETE FROM books
+WHERE id = $1
+  AND deleted_at IS NOT NULL;
 -- name: GetBookWithRelations :one
 SELECT b.*,
   a.name as author_name,
@@ -189,12 +170,14 @@ FROM books b
   JOIN authors a ON b.author_id = a.id
   LEFT JOIN publishers p ON b.publisher_id = p.id
   LEFT JOIN series s ON b.series_id = s.id
-WHERE b.id = $1;
+WHERE b.id = $1
+  AND b.deleted_at IS NULL;
 -- name: GetRecommendationsByAuthor :many
 SELECT *
 FROM books
 WHERE author_id = $1
   AND id != $2
+  AND deleted_at IS NULL
 ORDER BY published_date DESC NULLS LAST
 LIMIT $3;
 -- name: GetRecommendationsBySeries :many
@@ -202,23 +185,89 @@ SELECT *
 FROM books
 WHERE series_id = $1
   AND id != $2
+  AND deleted_at IS NULL
 ORDER BY series_position ASC NULLS LAST
 LIMIT $3;
 -- name: GetRecommendationsByTags :many
 SELECT b.*,
-  (
-    SELECT COUNT(*)
-    FROM unnest(string_to_array($2::text, ',')) AS t(tag)
-    WHERE b.tags ILIKE '%' || trim(t.tag) || '%'
-  ) as tag_matches
+  COUNT(*) as tag_matches
 FROM books b
+  JOIN book_tags bt ON bt.book_id = b.id
+  JOIN book_tags source ON source.tag_id = bt.tag_id
 WHERE b.id != $1
-  AND b.tags IS NOT NULL
-  AND EXISTS (
-    SELECT 1
-    FROM unnest(string_to_array($2::text, ',')) AS t(tag)
-    WHERE b.tags ILIKE '%' || trim(t.tag) || '%'
-  )
+  AND source.book_id = $1
+  AND b.deleted_at IS NULL
+GROUP BY b.id
 ORDER BY tag_matches DESC,
-  created_at DESC
-LIMIT $3;
\ No newline at end of file
+  b.created_at DESC
+LIMIT $2;
+-- name: GetBooksByIDs :many
+SELECT * FROM books WHERE id = ANY(@ids::uuid[]);
+-- name: GetBooksByISBN13sForUpdate :many
+-- Trashed books are included, as ISBN-13s are unique across the trash.
+SELECT * FROM books WHERE isbn13 = ANY(@isbns::text[]) FOR UPDATE;
+-- name: GetBooksByISBN10sForUpdate :many
+SELECT * FROM books WHERE isbn10 = ANY(@isbns::text[]) AND deleted_at IS NULL FOR UPDATE;
+-- name: GetBooksByTitleAuthorsForUpdate :many
+-- Matches each title with the author ID at the same position.
+SELECT * FROM books
+WHERE deleted_at IS NULL
+  AND (title, author_id) IN (SELECT unnest(@titles::text[]), unnest(@author_ids::uuid[]))
+FOR UPDATE;
+-- name: CreateBooks :batchone
+INSERT INTO books (
+    title,
+    subtitle,
+    author_id,
+    publisher_id,
+    published_date,
+    isbn10,
+    isbn13,
+    pages,
+    language,
+    description,
+    series_id,
+    series_position,
+    genres,
+    tags,
+    image_url
+  )
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14,
+    $15
+  )
+RETURNING *;
+-- name: UpdateBooks :batchone
+UPDATE books
+SET title = $2,
+  subtitle = $3,
+  author_id = $4,
+  publisher_id = $5,
+  published_date = $6,
+  isbn10 = $7,
+  isbn13 = $8,
+  pages = $9,
+  language = $10,
+  description = $11,
+  series_id = $12,
+  series_position = $13,
+  genres = $14,
+  tags = $15,
+  image_url = $16,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND deleted_at IS NULL
+RETURNING *;
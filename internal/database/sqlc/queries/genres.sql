@@ -0,0 +1,29 @@
+-- name: GetGenreByID :one
+SELECT * FROM genres WHERE id = $1;
+
+-- name: GetGenreBySlug :one
+SELECT * FROM genres WHERE slug = $1;
+
+-- name: ListGenres :many
+SELECT * FROM genres
+ORDER BY name
+LIMIT $1 OFFSET $2;
+
+-- name: SearchGenres :many
+SELECT * FROM genres
+WHERE name ILIKE '%' || $1 || '%'
+ORDER BY name
+LIMIT $2 OFFSET $3;
+
+-- name: GetGenresByBookIDs :many
+SELECT bg.book_id, sqlc.embed(g) FROM book_genres bg
+JOIN genres g ON g.id = bg.genre_id
+WHERE bg.book_id = ANY(@book_ids::uuid[])
+ORDER BY g.name;
+
+-- name: GetGenreBookCounts :many
+SELECT bg.genre_id, COUNT(*) AS book_count FROM book_genres bg
+JOIN books b ON b.id = bg.book_id
+WHERE bg.genre_id = ANY(@genre_ids::uuid[])
+  AND b.deleted_at IS NULL
+GROUP BY bg.genre_id;
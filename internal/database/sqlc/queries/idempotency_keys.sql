@@ -0,0 +1,13 @@
+-- name: ClaimIdempotencyKey :execrows
+INSERT INTO idempotency_keys (actor, key, operation, request_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (actor, key) DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE actor = $1 AND key = $2;
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys SET response = $3 WHERE actor = $1 AND key = $2;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE created_at < @before::timestamptz;
@@ -1,25 +1,43 @@
 -- name: GetPublisherByID :one
-SELECT * FROM publishers WHERE id = $1;
+SELECT * FROM publishers WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetPublishersByIDs :many
+SELECT * FROM publishers WHERE id = ANY(@ids::uuid[]) AND deleted_at IS NULL;
 
 -- name: GetPublisherBySlug :one
-SELECT * FROM publishers WHERE slug = $1;
+SELECT * FROM publishers WHERE slug = $1 AND deleted_at IS NULL;
 
 -- name: GetPublisherByName :one
-SELECT * FROM publishers WHERE name = $1;
+SELECT * FROM publishers WHERE name = $1 AND deleted_at IS NULL;
 
 -- name: ListPublishers :many
 SELECT * FROM publishers
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1 OFFSET $2;
 
+-- name: ListPublishersPage :many
+SELECT * FROM publishers
+WHERE deleted_at IS NULL
+  AND (@search::text = '' OR name ILIKE '%' || @search || '%')
+  AND (@after_name::text = '' OR (name, id) > (@after_name, @after_id::uuid))
+ORDER BY name, id
+LIMIT @page_size;
+
 -- name: SearchPublishers :many
 SELECT * FROM publishers
-WHERE name ILIKE '%' || $1 || '%'
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
 ORDER BY name
 LIMIT $2 OFFSET $3;
 
 -- name: CountPublishers :one
-SELECT COUNT(*) FROM publishers;
+SELECT COUNT(*) FROM publishers WHERE deleted_at IS NULL;
+
+-- name: CountPublishersSearch :one
+SELECT COUNT(*) FROM publishers
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%';
 
 -- name: CreatePublisher :one
 INSERT INTO publishers (name, slug, website)
@@ -29,11 +47,82 @@ RETURNING *;
 -- name: UpdatePublisher :one
 UPDATE publishers
 SET name = $2, slug = $3, website = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: TrashPublisher :exec
+UPDATE publishers SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: RestorePublisher :one
+UPDATE publishers SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
 RETURNING *;
 
--- name: DeletePublisher :exec
-DELETE FROM publishers WHERE id = $1;
+-- name: PurgePublisher :exec
+DELETE FROM publishers WHERE id = $1 AND deleted_at IS NOT NULL;
 
 -- name: GetPublisherBookCount :one
-SELECT COUNT(*) FROM books WHERE publisher_id = $1;
+SELECT COUNT(*) FROM books WHERE publisher_id = $1 AND deleted_at IS NULL;
+
+-- name: GetPublisherBookCounts :many
+SELECT publisher_id, COUNT(*) AS book_count FROM books
+WHERE publisher_id = ANY(@publisher_ids::uuid[])
+  AND deleted_at IS NULL
+GROUP BY publisher_id;
+
+-- name: ReassignPublisherBooks :many
+UPDATE books SET publisher_id = @reassign_to::uuid, updated_at = CURRENT_TIMESTAMP
+WHERE publisher_id = @publisher_id::uuid AND deleted_at IS NULL
+RETURNING *;
+
+-- name: NullifyPublisherBooks :many
+UPDATE books SET publisher_id = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE publisher_id = @publisher_id::uuid
+RETURNING *;
+
+-- name: LockPublisher :one
+SELECT deleted_at FROM publishers WHERE id = $1 FOR UPDATE;
+
+-- name: GetPublisherBookIDs :many
+SELECT id FROM books WHERE publisher_id = @publisher_id::uuid AND deleted_at IS NULL ORDER BY id;
+
+-- name: GetPublisherByIDForUpdate :one
+SELECT * FROM publishers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- name: UpsertPublisherByName :one
+-- Returns the row named @name, inserting it with @slug if there is none.
+-- Trashed rows match too, since names are unique across the trash. An empty
+-- slug is stored as NULL.
+INSERT INTO publishers (name, slug) VALUES (@name, NULLIF(@slug::text, ''))
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: LockPublishersByIDs :many
+SELECT * FROM publishers WHERE id = ANY(@ids::uuid[]) FOR UPDATE;
+
+-- name: GetPublishersByNamesForUpdate :many
+SELECT * FROM publishers WHERE name = ANY(@names::text[]) FOR UPDATE;
+
+-- name: UpsertPublishersByName :many
+INSERT INTO publishers (name) SELECT unnest(@names::text[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetPublishersByNames :many
+SELECT * FROM publishers WHERE name = ANY(@names::text[]);
+
+-- name: ListPublisherSlugs :many
+SELECT slug FROM publishers WHERE slug IS NOT NULL;
+
+-- name: ListPublisherSlugsWithBases :many
+-- The slugs that any of @bases, as is or with a suffix, would collide with.
+SELECT slug FROM publishers t
+WHERE EXISTS (SELECT 1 FROM unnest(@bases::text[]) AS b(base) WHERE t.slug = b.base OR t.slug LIKE b.base || '-%');
+
+-- name: CreatePublishersWithSlugs :many
+-- Rows whose name or slug is taken by then are left out of the result.
+-- An empty slug is stored as NULL.
+INSERT INTO publishers (name, slug)
+SELECT name, NULLIF(slug, '') FROM unnest(@names::text[], @slugs::text[]) AS t(name, slug)
+ON CONFLICT DO NOTHING
+RETURNING *;
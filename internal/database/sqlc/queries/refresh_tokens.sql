@@ -0,0 +1,17 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetRefreshTokenByHashForUpdate :one
+SELECT * FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE;
+
+-- name: RevokeRefreshToken :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = $1 AND revoked_at IS NULL;
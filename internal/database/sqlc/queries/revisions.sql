@@ -0,0 +1,19 @@
+-- name: CreateEntityRevision :exec
+INSERT INTO entity_revisions (entity_type, entity_id, revision, snapshot, actor, request_id)
+SELECT @entity_type::text, @entity_id::uuid, COALESCE(MAX(revision), 0) + 1, @snapshot::jsonb, @actor::text, sqlc.narg(request_id)::text
+FROM entity_revisions
+WHERE entity_id = @entity_id::uuid;
+
+-- name: GetEntityRevision :one
+SELECT * FROM entity_revisions WHERE id = $1;
+
+-- name: ListEntityRevisions :many
+SELECT * FROM entity_revisions
+WHERE entity_id = $1
+ORDER BY revision DESC;
+
+-- name: CreateEntityRevisions :batchexec
+INSERT INTO entity_revisions (entity_type, entity_id, revision, snapshot, actor, request_id)
+SELECT @entity_type::text, @entity_id::uuid, COALESCE(MAX(revision), 0) + 1, @snapshot::jsonb, @actor::text, sqlc.narg(request_id)::text
+FROM entity_revisions
+WHERE entity_id = @entity_id::uuid;
@@ -0,0 +1,29 @@
+-- name: Suggest :many
+SELECT entity_type, id, name, slug, score FROM (
+    SELECT 'BOOK' AS entity_type, id, title AS name, NULL::text AS slug,
+        word_similarity(@prefix::text, title)::float8 AS score
+    FROM books
+    WHERE deleted_at IS NULL
+      AND 'BOOK' = ANY(@types::text[])
+      AND (@prefix <% title OR title ILIKE @prefix || '%')
+    UNION ALL
+    SELECT 'AUTHOR', id, name, slug, word_similarity(@prefix, name)::float8
+    FROM authors
+    WHERE deleted_at IS NULL
+      AND 'AUTHOR' = ANY(@types::text[])
+      AND (@prefix <% name OR name ILIKE @prefix || '%')
+    UNION ALL
+    SELECT 'SERIES', id, name, slug, word_similarity(@prefix, name)::float8
+    FROM series
+    WHERE deleted_at IS NULL
+      AND 'SERIES' = ANY(@types::text[])
+      AND (@prefix <% name OR name ILIKE @prefix || '%')
+    UNION ALL
+    SELECT 'PUBLISHER', id, name, slug, word_similarity(@prefix, name)::float8
+    FROM publishers
+    WHERE deleted_at IS NULL
+      AND 'PUBLISHER' = ANY(@types::text[])
+      AND (@prefix <% name OR name ILIKE @prefix || '%')
+) hits
+ORDER BY score DESC, name
+LIMIT @max_results;
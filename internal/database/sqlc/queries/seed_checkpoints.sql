@@ -0,0 +1,18 @@
+-- name: GetSeedCheckpoint :one
+SELECT * FROM seed_checkpoints WHERE source = $1;
+
+-- name: SaveSeedCheckpoint :exec
+INSERT INTO seed_checkpoints (
+    source, file_size, file_modified_at, byte_offset, rows_read, books_inserted, rows_skipped
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (source) DO UPDATE SET
+    file_size = EXCLUDED.file_size,
+    file_modified_at = EXCLUDED.file_modified_at,
+    byte_offset = EXCLUDED.byte_offset,
+    rows_read = EXCLUDED.rows_read,
+    books_inserted = EXCLUDED.books_inserted,
+    rows_skipped = EXCLUDED.rows_skipped,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteSeedCheckpoint :exec
+DELETE FROM seed_checkpoints WHERE source = $1;
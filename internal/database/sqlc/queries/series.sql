@@ -1,25 +1,43 @@
 -- name: GetSeriesByID :one
-SELECT * FROM series WHERE id = $1;
+SELECT * FROM series WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetSeriesByIDs :many
+SELECT * FROM series WHERE id = ANY(@ids::uuid[]) AND deleted_at IS NULL;
 
 -- name: GetSeriesBySlug :one
-SELECT * FROM series WHERE slug = $1;
+SELECT * FROM series WHERE slug = $1 AND deleted_at IS NULL;
 
 -- name: GetSeriesByName :one
-SELECT * FROM series WHERE name = $1;
+SELECT * FROM series WHERE name = $1 AND deleted_at IS NULL;
 
 -- name: ListSeries :many
 SELECT * FROM series
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1 OFFSET $2;
 
+-- name: ListSeriesPage :many
+SELECT * FROM series
+WHERE deleted_at IS NULL
+  AND (@search::text = '' OR name ILIKE '%' || @search || '%')
+  AND (@after_name::text = '' OR (name, id) > (@after_name, @after_id::uuid))
+ORDER BY name, id
+LIMIT @page_size;
+
 -- name: SearchSeries :many
 SELECT * FROM series
-WHERE name ILIKE '%' || $1 || '%'
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
 ORDER BY name
 LIMIT $2 OFFSET $3;
 
 -- name: CountSeries :one
-SELECT COUNT(*) FROM series;
+SELECT COUNT(*) FROM series WHERE deleted_at IS NULL;
+
+-- name: CountSeriesSearch :one
+SELECT COUNT(*) FROM series
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%';
 
 -- name: CreateSeries :one
 INSERT INTO series (name, slug, description)
@@ -29,11 +47,91 @@ RETURNING *;
 -- name: UpdateSeries :one
 UPDATE series
 SET name = $2, slug = $3, description = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: TrashSeries :exec
+UPDATE series SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: RestoreSeries :one
+UPDATE series SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
 RETURNING *;
 
--- name: DeleteSeries :exec
-DELETE FROM series WHERE id = $1;
+-- name: PurgeSeries :exec
+DELETE FROM series WHERE id = $1 AND deleted_at IS NOT NULL;
 
 -- name: GetSeriesBookCount :one
-SELECT COUNT(*) FROM books WHERE series_id = $1;
+SELECT COUNT(*) FROM books WHERE series_id = $1 AND deleted_at IS NULL;
+
+-- name: GetSeriesBookCounts :many
+SELECT series_id, COUNT(*) AS book_count FROM books
+WHERE series_id = ANY(@series_ids::uuid[])
+  AND deleted_at IS NULL
+GROUP BY series_id;
+
+-- name: LockSeries :one
+SELECT deleted_at FROM series WHERE id = $1 FOR UPDATE;
+
+-- name: GetSeriesBookIDs :many
+SELECT id FROM books WHERE series_id = @series_id::uuid AND deleted_at IS NULL ORDER BY id;
+
+-- name: ReassignSeriesBooks :many
+UPDATE books SET series_id = @reassign_to::uuid, updated_at = CURRENT_TIMESTAMP
+WHERE series_id = @series_id::uuid AND deleted_at IS NULL
+RETURNING *;
+
+-- name: TrashSeriesBooks :many
+UPDATE books SET deleted_at = CURRENT_TIMESTAMP
+WHERE series_id = @series_id::uuid AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreSeriesBooks :many
+UPDATE books b SET deleted_at = NULL
+FROM series s
+WHERE s.id = @series_id::uuid AND b.series_id = s.id AND b.deleted_at = s.deleted_at
+RETURNING b.*;
+
+-- name: PurgeSeriesBooks :execrows
+DELETE FROM books WHERE series_id = @series_id::uuid AND deleted_at IS NOT NULL;
+
+-- name: GetSeriesByIDForUpdate :one
+SELECT * FROM series WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- name: UpsertSeriesByName :one
+-- Returns the row named @name, inserting it with @slug if there is none.
+-- Trashed rows match too, since names are unique across the trash. An empty
+-- slug is stored as NULL.
+INSERT INTO series (name, slug) VALUES (@name, NULLIF(@slug::text, ''))
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: LockSeriesByIDs :many
+SELECT * FROM series WHERE id = ANY(@ids::uuid[]) FOR UPDATE;
+
+-- name: GetSeriesByNamesForUpdate :many
+SELECT * FROM series WHERE name = ANY(@names::text[]) FOR UPDATE;
+
+-- name: UpsertSeriesByNames :many
+INSERT INTO series (name) SELECT unnest(@names::text[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetSeriesByNames :many
+SELECT * FROM series WHERE name = ANY(@names::text[]);
+
+-- name: ListSeriesSlugs :many
+SELECT slug FROM series WHERE slug IS NOT NULL;
+
+-- name: ListSeriesSlugsWithBases :many
+-- The slugs that any of @bases, as is or with a suffix, would collide with.
+SELECT slug FROM series t
+WHERE EXISTS (SELECT 1 FROM unnest(@bases::text[]) AS b(base) WHERE t.slug = b.base OR t.slug LIKE b.base || '-%');
+
+-- name: CreateSeriesWithSlugs :many
+-- Rows whose name or slug is taken by then are left out of the result.
+-- An empty slug is stored as NULL.
+INSERT INTO series (name, slug)
+SELECT name, NULLIF(slug, '') FROM unnest(@names::text[], @slugs::text[]) AS t(name, slug)
+ON CONFLICT DO NOTHING
+RETURNING *;
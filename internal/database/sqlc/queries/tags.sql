@@ -0,0 +1,29 @@
+-- name: GetTagByID :one
+SELECT * FROM tags WHERE id = $1;
+
+-- name: GetTagBySlug :one
+SELECT * FROM tags WHERE slug = $1;
+
+-- name: ListTags :many
+SELECT * FROM tags
+ORDER BY name
+LIMIT $1 OFFSET $2;
+
+-- name: SearchTags :many
+SELECT * FROM tags
+WHERE name ILIKE '%' || $1 || '%'
+ORDER BY name
+LIMIT $2 OFFSET $3;
+
+-- name: GetTagsByBookIDs :many
+SELECT bt.book_id, sqlc.embed(t) FROM book_tags bt
+JOIN tags t ON t.id = bt.tag_id
+WHERE bt.book_id = ANY(@book_ids::uuid[])
+ORDER BY t.name;
+
+-- name: GetTagBookCounts :many
+SELECT bt.tag_id, COUNT(*) AS book_count FROM book_tags bt
+JOIN books b ON b.id = bt.book_id
+WHERE bt.tag_id = ANY(@tag_ids::uuid[])
+  AND b.deleted_at IS NULL
+GROUP BY bt.tag_id;
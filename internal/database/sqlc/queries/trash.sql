@@ -0,0 +1,44 @@
+-- name: ListTrash :many
+SELECT entity_type, id, name, deleted_at FROM (
+    SELECT 'BOOK' AS entity_type, id, title AS name, deleted_at
+    FROM books
+    WHERE deleted_at IS NOT NULL AND 'BOOK' = ANY(@types::text[])
+    UNION ALL
+    SELECT 'AUTHOR', id, name, deleted_at
+    FROM authors
+    WHERE deleted_at IS NOT NULL AND 'AUTHOR' = ANY(@types::text[])
+    UNION ALL
+    SELECT 'SERIES', id, name, deleted_at
+    FROM series
+    WHERE deleted_at IS NOT NULL AND 'SERIES' = ANY(@types::text[])
+    UNION ALL
+    SELECT 'PUBLISHER', id, name, deleted_at
+    FROM publishers
+    WHERE deleted_at IS NOT NULL AND 'PUBLISHER' = ANY(@types::text[])
+) trashed
+ORDER BY deleted_at DESC, id
+LIMIT @max_results;
+
+-- name: GetTrashedEntityType :one
+SELECT entity_type FROM (
+    SELECT 'BOOK' AS entity_type FROM books WHERE id = $1 AND deleted_at IS NOT NULL
+    UNION ALL
+    SELECT 'AUTHOR' FROM authors WHERE id = $1 AND deleted_at IS NOT NULL
+    UNION ALL
+    SELECT 'SERIES' FROM series WHERE id = $1 AND deleted_at IS NOT NULL
+    UNION ALL
+    SELECT 'PUBLISHER' FROM publishers WHERE id = $1 AND deleted_at IS NOT NULL
+) trashed
+LIMIT 1;
+
+-- name: ListExpiredTrash :many
+SELECT entity_type, id FROM (
+    SELECT 'BOOK' AS entity_type, id, deleted_at FROM books WHERE deleted_at < @before::timestamptz
+    UNION ALL
+    SELECT 'AUTHOR', id, deleted_at FROM authors WHERE deleted_at < @before::timestamptz
+    UNION ALL
+    SELECT 'SERIES', id, deleted_at FROM series WHERE deleted_at < @before::timestamptz
+    UNION ALL
+    SELECT 'PUBLISHER', id, deleted_at FROM publishers WHERE deleted_at < @before::timestamptz
+) expired
+ORDER BY entity_type = 'BOOK' DESC, deleted_at;
@@ -0,0 +1,22 @@
+-- name: CreateUser :one
+INSERT INTO users (email, name, password_hash, role)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetUserByID :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: GetUserByIDForUpdate :one
+SELECT * FROM users WHERE id = $1 FOR UPDATE;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users WHERE email = $1;
+
+-- name: ListUsers :many
+SELECT * FROM users ORDER BY email;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
@@ -1,3 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 -- Authors table
 CREATE TABLE authors (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -5,11 +7,14 @@ CREATE TABLE authors (
     slug TEXT UNIQUE,
     bio TEXT,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
 );
 
 CREATE UNIQUE INDEX idx_authors_name ON authors(name);
 CREATE INDEX idx_authors_slug ON authors(slug) WHERE slug IS NOT NULL;
+CREATE INDEX idx_authors_name_trgm ON authors USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_authors_deleted_at ON authors(deleted_at) WHERE deleted_at IS NOT NULL;
 
 -- Publishers table
 CREATE TABLE publishers (
@@ -18,11 +23,14 @@ CREATE TABLE publishers (
     slug TEXT UNIQUE,
     website TEXT,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
 );
 
 CREATE UNIQUE INDEX idx_publishers_name ON publishers(name);
 CREATE INDEX idx_publishers_slug ON publishers(slug) WHERE slug IS NOT NULL;
+CREATE INDEX idx_publishers_name_trgm ON publishers USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_publishers_deleted_at ON publishers(deleted_at) WHERE deleted_at IS NOT NULL;
 
 -- Series table
 CREATE TABLE series (
@@ -31,11 +39,14 @@ CREATE TABLE series (
     slug TEXT UNIQUE,
     description TEXT,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
 );
 
 CREATE UNIQUE INDEX idx_series_name ON series(name);
 CREATE INDEX idx_series_slug ON series(slug) WHERE slug IS NOT NULL;
+CREATE INDEX idx_series_name_trgm ON series USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_series_deleted_at ON series(deleted_at) WHERE deleted_at IS NOT NULL;
 
 -- Books table
 CREATE TABLE books (
@@ -45,8 +56,8 @@ CREATE TABLE books (
     author_id UUID NOT NULL REFERENCES authors(id),
     publisher_id UUID REFERENCES publishers(id),
     published_date DATE,
-    isbn10 VARCHAR(10),
-    isbn13 VARCHAR(13) UNIQUE,
+    isbn10 VARCHAR(13),
+    isbn13 VARCHAR(17) UNIQUE,
     pages INTEGER CHECK (pages IS NULL OR pages > 0),
     language TEXT,
     description TEXT,
@@ -56,11 +67,148 @@ CREATE TABLE books (
     tags TEXT,
     image_url TEXT,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
 );
 
 CREATE INDEX idx_books_title ON books(title);
+CREATE INDEX idx_books_title_trgm ON books USING GIN (title gin_trgm_ops);
 CREATE INDEX idx_books_author_id ON books(author_id);
 CREATE INDEX idx_books_publisher_id ON books(publisher_id);
 CREATE INDEX idx_books_series_id ON books(series_id);
 CREATE INDEX idx_books_published_date ON books(published_date) WHERE published_date IS NOT NULL;
+CREATE INDEX idx_books_deleted_at ON books(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_books_isbn10_normalized ON books (regexp_replace(upper(isbn10), '[^0-9X]', '', 'g'));
+CREATE INDEX idx_books_isbn13_normalized ON books (regexp_replace(upper(isbn13), '[^0-9X]', '', 'g'));
+
+-- Full-text search documents, maintained by triggers (see migrations)
+CREATE TABLE book_search_documents (
+    book_id UUID PRIMARY KEY REFERENCES books(id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL
+);
+
+CREATE INDEX idx_book_search_documents_document ON book_search_documents USING GIN (document);
+
+-- Genres and tags, linked to books through book_genres and book_tags. The
+-- link rows are maintained by triggers from books.genres and books.tags.
+CREATE TABLE genres (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE book_genres (
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    genre_id UUID NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, genre_id)
+);
+
+CREATE INDEX idx_book_genres_genre_id ON book_genres(genre_id);
+
+CREATE TABLE book_tags (
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, tag_id)
+);
+
+CREATE INDEX idx_book_tags_tag_id ON book_tags(tag_id);
+
+-- Audit log of admin mutations
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}'::jsonb,
+    actor TEXT NOT NULL,
+    request_id TEXT,
+    remote_addr TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at DESC, id DESC);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_id, created_at DESC, id DESC);
+CREATE INDEX idx_audit_events_entity_type ON audit_events(entity_type, created_at DESC, id DESC);
+
+-- Row snapshots taken before each update, for revision history and revert
+CREATE TABLE entity_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    revision INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    actor TEXT NOT NULL,
+    request_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_id, revision)
+);
+
+-- Accounts and their API tokens (tokens are stored as SHA-256 hashes)
+CREATE TABLE users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'viewer' CHECK (role IN ('viewer', 'editor', 'admin')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE api_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash BYTEA NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+-- Rotating refresh tokens for login sessions, grouped into families
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash BYTEA NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+-- Responses of mutations sent with an idempotency key
+CREATE TABLE idempotency_keys (
+    actor TEXT NOT NULL,
+    key TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    request_hash BYTEA NOT NULL,
+    response JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (actor, key)
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+
+-- Progress of CSV imports, so an interrupted one can resume
+CREATE TABLE seed_checkpoints (
+    source TEXT PRIMARY KEY,
+    file_size BIGINT NOT NULL,
+    file_modified_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    byte_offset BIGINT NOT NULL,
+    rows_read BIGINT NOT NULL,
+    books_inserted BIGINT NOT NULL,
+    rows_skipped BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
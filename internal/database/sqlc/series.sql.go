@@ -7,13 +7,14 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countSeries = `-- name: CountSeries :one
-SELECT COUNT(*) FROM series
+SELECT COUNT(*) FROM series WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountSeries(ctx context.Context) (int64, error) {
@@ -23,10 +24,23 @@ func (q *Queries) CountSeries(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countSeriesSearch = `-- name: CountSeriesSearch :one
+SELECT COUNT(*) FROM series
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
+`
+
+func (q *Queries) CountSeriesSearch(ctx context.Context, dollar_1 *string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSeriesSearch, dollar_1)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSeries = `-- name: CreateSeries :one
 INSERT INTO series (name, slug, description)
 VALUES ($1, $2, $3)
-RETURNING id, name, slug, description, created_at, updated_at
+RETURNING id, name, slug, description, created_at, updated_at, deleted_at
 `
 
 type CreateSeriesParams struct {
@@ -45,21 +59,53 @@ func (q *Queries) CreateSeries(ctx context.Context, arg CreateSeriesParams) (Ser
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteSeries = `-- name: DeleteSeries :exec
-DELETE FROM series WHERE id = $1
+const createSeriesWithSlugs = `-- name: CreateSeriesWithSlugs :many
+INSERT INTO series (name, slug)
+SELECT name, NULLIF(slug, '') FROM unnest($1::text[], $2::text[]) AS t(name, slug)
+ON CONFLICT DO NOTHING
+RETURNING id, name, slug, description, created_at, updated_at, deleted_at;
 `
 
-func (q *Queries) DeleteSeries(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteSeries, id)
-	return err
+type CreateSeriesWithSlugsParams struct {
+	Names []string
+	Slugs []string
+}
+
+func (q *Queries) CreateSeriesWithSlugs(ctx context.Context, arg CreateSeriesWithSlugsParams) ([]Series, error) {
+	rows, err := q.db.Query(ctx, createSeriesWithSlugs, arg.Names, arg.Slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getSeriesBookCount = `-- name: GetSeriesBookCount :one
-SELECT COUNT(*) FROM books WHERE series_id = $1
+SELECT COUNT(*) FROM books WHERE series_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetSeriesBookCount(ctx context.Context, seriesID pgtype.UUID) (int64, error) {
@@ -69,8 +115,67 @@ func (q *Queries) GetSeriesBookCount(ctx context.Context, seriesID pgtype.UUID)
 	return count, err
 }
 
+const getSeriesBookCounts = `-- name: GetSeriesBookCounts :many
+SELECT series_id, COUNT(*) AS book_count FROM books
+WHERE series_id = ANY($1::uuid[])
+  AND deleted_at IS NULL
+GROUP BY series_id
+`
+
+type GetSeriesBookCountsRow struct {
+	SeriesID  pgtype.UUID
+	BookCount int64
+}
+
+func (q *Queries) GetSeriesBookCounts(ctx context.Context, seriesIDs []uuid.UUID) ([]GetSeriesBookCountsRow, error) {
+	rows, err := q.db.Query(ctx, getSeriesBookCounts, seriesIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSeriesBookCountsRow
+	for rows.Next() {
+		var i GetSeriesBookCountsRow
+		if err := rows.Scan(
+			&i.SeriesID,
+			&i.BookCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSeriesBookIDs = `-- name: GetSeriesBookIDs :many
+SELECT id FROM books WHERE series_id = $1::uuid AND deleted_at IS NULL ORDER BY id
+`
+
+func (q *Queries) GetSeriesBookIDs(ctx context.Context, seriesID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getSeriesBookIDs, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSeriesByID = `-- name: GetSeriesByID :one
-SELECT id, name, slug, description, created_at, updated_at FROM series WHERE id = $1
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetSeriesByID(ctx context.Context, id uuid.UUID) (Series, error) {
@@ -83,12 +188,64 @@ func (q *Queries) GetSeriesByID(ctx context.Context, id uuid.UUID) (Series, erro
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getSeriesByIDForUpdate = `-- name: GetSeriesByIDForUpdate :one
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+`
+
+func (q *Queries) GetSeriesByIDForUpdate(ctx context.Context, id uuid.UUID) (Series, error) {
+	row := q.db.QueryRow(ctx, getSeriesByIDForUpdate, id)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getSeriesByIDs = `-- name: GetSeriesByIDs :many
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetSeriesByIDs(ctx context.Context, ids []uuid.UUID) ([]Series, error) {
+	rows, err := q.db.Query(ctx, getSeriesByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSeriesByName = `-- name: GetSeriesByName :one
-SELECT id, name, slug, description, created_at, updated_at FROM series WHERE name = $1
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE name = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetSeriesByName(ctx context.Context, name string) (Series, error) {
@@ -101,12 +258,77 @@ func (q *Queries) GetSeriesByName(ctx context.Context, name string) (Series, err
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getSeriesByNames = `-- name: GetSeriesByNames :many
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE name = ANY($1::text[]);
+`
+
+func (q *Queries) GetSeriesByNames(ctx context.Context, names []string) ([]Series, error) {
+	rows, err := q.db.Query(ctx, getSeriesByNames, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSeriesByNamesForUpdate = `-- name: GetSeriesByNamesForUpdate :many
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE name = ANY($1::text[]) FOR UPDATE
+`
+
+func (q *Queries) GetSeriesByNamesForUpdate(ctx context.Context, names []string) ([]Series, error) {
+	rows, err := q.db.Query(ctx, getSeriesByNamesForUpdate, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSeriesBySlug = `-- name: GetSeriesBySlug :one
-SELECT id, name, slug, description, created_at, updated_at FROM series WHERE slug = $1
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE slug = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetSeriesBySlug(ctx context.Context, slug *string) (Series, error) {
@@ -119,12 +341,14 @@ func (q *Queries) GetSeriesBySlug(ctx context.Context, slug *string) (Series, er
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listSeries = `-- name: ListSeries :many
-SELECT id, name, slug, description, created_at, updated_at FROM series
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1 OFFSET $2
 `
@@ -150,6 +374,289 @@ func (q *Queries) ListSeries(ctx context.Context, arg ListSeriesParams) ([]Serie
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeriesPage = `-- name: ListSeriesPage :many
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series
+WHERE deleted_at IS NULL
+  AND ($1::text = '' OR name ILIKE '%' || $1 || '%')
+  AND ($2::text = '' OR (name, id) > ($2, $3::uuid))
+ORDER BY name, id
+LIMIT $4
+`
+
+type ListSeriesPageParams struct {
+	Search    string
+	AfterName string
+	AfterID   uuid.UUID
+	PageSize  int32
+}
+
+func (q *Queries) ListSeriesPage(ctx context.Context, arg ListSeriesPageParams) ([]Series, error) {
+	rows, err := q.db.Query(ctx, listSeriesPage,
+		arg.Search,
+		arg.AfterName,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeriesSlugs = `-- name: ListSeriesSlugs :many
+SELECT slug FROM series WHERE slug IS NOT NULL;
+`
+
+func (q *Queries) ListSeriesSlugs(ctx context.Context) ([]*string, error) {
+	rows, err := q.db.Query(ctx, listSeriesSlugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*string
+	for rows.Next() {
+		var slug *string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeriesSlugsWithBases = `-- name: ListSeriesSlugsWithBases :many
+-- The slugs that any of @bases, as is or with a suffix, would collide with.
+SELECT slug FROM series t
+WHERE EXISTS (SELECT 1 FROM unnest($1::text[]) AS b(base) WHERE t.slug = b.base OR t.slug LIKE b.base || '-%')
+`
+
+func (q *Queries) ListSeriesSlugsWithBases(ctx context.Context, bases []string) ([]*string, error) {
+	rows, err := q.db.Query(ctx, listSeriesSlugsWithBases, bases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*string
+	for rows.Next() {
+		var slug *string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSeries = `-- name: LockSeries :one
+SELECT deleted_at FROM series WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockSeries(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	row := q.db.QueryRow(ctx, lockSeries, id)
+	var deleted_at *time.Time
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
+const lockSeriesByIDs = `-- name: LockSeriesByIDs :many
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series WHERE id = ANY($1::uuid[]) FOR UPDATE
+`
+
+func (q *Queries) LockSeriesByIDs(ctx context.Context, ids []uuid.UUID) ([]Series, error) {
+	rows, err := q.db.Query(ctx, lockSeriesByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeSeries = `-- name: PurgeSeries :exec
+DELETE FROM series WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgeSeries(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, purgeSeries, id)
+	return err
+}
+
+const purgeSeriesBooks = `-- name: PurgeSeriesBooks :execrows
+DELETE FROM books WHERE series_id = $1::uuid AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgeSeriesBooks(ctx context.Context, seriesID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeSeriesBooks, seriesID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reassignSeriesBooks = `-- name: ReassignSeriesBooks :many
+UPDATE books SET series_id = $1::uuid, updated_at = CURRENT_TIMESTAMP
+WHERE series_id = $2::uuid AND deleted_at IS NULL
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+`
+
+type ReassignSeriesBooksParams struct {
+	ReassignTo uuid.UUID
+	SeriesID   uuid.UUID
+}
+
+func (q *Queries) ReassignSeriesBooks(ctx context.Context, arg ReassignSeriesBooksParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, reassignSeriesBooks, arg.ReassignTo, arg.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreSeries = `-- name: RestoreSeries :one
+UPDATE series SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, slug, description, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) RestoreSeries(ctx context.Context, id uuid.UUID) (Series, error) {
+	row := q.db.QueryRow(ctx, restoreSeries, id)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restoreSeriesBooks = `-- name: RestoreSeriesBooks :many
+UPDATE books b SET deleted_at = NULL
+FROM series s
+WHERE s.id = $1::uuid AND b.series_id = s.id AND b.deleted_at = s.deleted_at
+RETURNING b.id, b.title, b.subtitle, b.author_id, b.publisher_id, b.published_date, b.isbn10, b.isbn13, b.pages, b.language, b.description, b.series_id, b.series_position, b.genres, b.tags, b.image_url, b.created_at, b.updated_at, b.deleted_at
+`
+
+func (q *Queries) RestoreSeriesBooks(ctx context.Context, seriesID uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, restoreSeriesBooks, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -162,8 +669,9 @@ func (q *Queries) ListSeries(ctx context.Context, arg ListSeriesParams) ([]Serie
 }
 
 const searchSeries = `-- name: SearchSeries :many
-SELECT id, name, slug, description, created_at, updated_at FROM series
-WHERE name ILIKE '%' || $1 || '%'
+SELECT id, name, slug, description, created_at, updated_at, deleted_at FROM series
+WHERE deleted_at IS NULL
+  AND name ILIKE '%' || $1 || '%'
 ORDER BY name
 LIMIT $2 OFFSET $3
 `
@@ -190,6 +698,62 @@ func (q *Queries) SearchSeries(ctx context.Context, arg SearchSeriesParams) ([]S
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trashSeries = `-- name: TrashSeries :exec
+UPDATE series SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+func (q *Queries) TrashSeries(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, trashSeries, id)
+	return err
+}
+
+const trashSeriesBooks = `-- name: TrashSeriesBooks :many
+UPDATE books SET deleted_at = CURRENT_TIMESTAMP
+WHERE series_id = $1::uuid AND deleted_at IS NULL
+RETURNING id, title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13, pages, language, description, series_id, series_position, genres, tags, image_url, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) TrashSeriesBooks(ctx context.Context, seriesID uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, trashSeriesBooks, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Subtitle,
+			&i.AuthorID,
+			&i.PublisherID,
+			&i.PublishedDate,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Pages,
+			&i.Language,
+			&i.Description,
+			&i.SeriesID,
+			&i.SeriesPosition,
+			&i.Genres,
+			&i.Tags,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -204,8 +768,8 @@ func (q *Queries) SearchSeries(ctx context.Context, arg SearchSeriesParams) ([]S
 const updateSeries = `-- name: UpdateSeries :one
 UPDATE series
 SET name = $2, slug = $3, description = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, name, slug, description, created_at, updated_at
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, slug, description, created_at, updated_at, deleted_at
 `
 
 type UpdateSeriesParams struct {
@@ -230,6 +794,70 @@ func (q *Queries) UpdateSeries(ctx context.Context, arg UpdateSeriesParams) (Ser
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const upsertSeriesByName = `-- name: UpsertSeriesByName :one
+-- Returns the row named @name, inserting it with @slug if there is none.
+-- Trashed rows match too, since names are unique across the trash. An empty
+-- slug is stored as NULL.
+INSERT INTO series (name, slug) VALUES ($1, NULLIF($2::text, ''))
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name, slug, description, created_at, updated_at, deleted_at
+`
+
+type UpsertSeriesByNameParams struct {
+	Name string
+	Slug string
+}
+
+func (q *Queries) UpsertSeriesByName(ctx context.Context, arg UpsertSeriesByNameParams) (Series, error) {
+	row := q.db.QueryRow(ctx, upsertSeriesByName, arg.Name, arg.Slug)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const upsertSeriesByNames = `-- name: UpsertSeriesByNames :many
+INSERT INTO series (name) SELECT unnest($1::text[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name, slug, description, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) UpsertSeriesByNames(ctx context.Context, names []string) ([]Series, error) {
+	rows, err := q.db.Query(ctx, upsertSeriesByNames, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is what services run their queries on: a pool, or a transaction from
+// WithTx. Begin on a transaction starts a savepoint, so a service that groups
+// its own statements in a transaction still commits or rolls back with the
+// caller's.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var (
+	_ DBTX = (*pgxpool.Pool)(nil)
+	_ DBTX = Tx(nil)
+)
+
+// Tx is a transaction on the primary, passed to the function given to
+// WithTx.
+type Tx = pgx.Tx
+
+// WithTx runs fn in a transaction on the primary. The transaction commits
+// when fn returns nil and rolls back otherwise, leaving nothing of a
+// half-done change behind.
+func (s *service) WithTx(ctx context.Context, fn func(tx Tx) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithTx(t *testing.T) {
+	if !dockerAvailable {
+		t.Skip("Skipping test: Docker not available")
+	}
+
+	ctx := context.Background()
+	srv, err := New(ctx, testConfig)
+	if err != nil {
+		t.Fatalf("New() returned an error: %v", err)
+	}
+	db := srv.DB()
+	if _, err := db.Exec(ctx, `CREATE TABLE tx_test (name TEXT PRIMARY KEY)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(ctx, `DROP TABLE tx_test`) })
+
+	insert := func(db DBTX, name string) error {
+		_, err := db.Exec(ctx, `INSERT INTO tx_test (name) VALUES ($1)`, name)
+		return err
+	}
+	exists := func(name string) bool {
+		var found bool
+		if err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tx_test WHERE name = $1)`, name).Scan(&found); err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		return found
+	}
+
+	if err := srv.WithTx(ctx, func(tx Tx) error { return insert(tx, "committed") }); err != nil {
+		t.Fatalf("WithTx() returned an error: %v", err)
+	}
+	if !exists("committed") {
+		t.Fatal("expected the row to be committed")
+	}
+
+	// A service's own transaction is a savepoint inside the caller's, so
+	// it is undone when the caller fails afterwards.
+	errFailed := errors.New("failed")
+	err = srv.WithTx(ctx, func(tx Tx) error {
+		nested, err := tx.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		if err := insert(nested, "nested"); err != nil {
+			return err
+		}
+		if err := nested.Commit(ctx); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("expected fn's error, got %v", err)
+	}
+	if exists("nested") {
+		t.Fatal("expected the nested insert to be rolled back")
+	}
+}
@@ -0,0 +1,103 @@
+// Package deletion holds the strategy and error types shared by the services
+// that delete catalog entities. Deleting moves an entity to the trash by
+// setting its deleted_at; only purging removes the row.
+package deletion
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Mode says what a delete does with the books that reference the entity.
+type Mode string
+
+const (
+	// Restrict refuses to delete an entity that still has books.
+	Restrict Mode = "RESTRICT"
+	// ReassignTo moves the books to Strategy.ReassignTo first.
+	ReassignTo Mode = "REASSIGN_TO"
+	// Cascade deletes the books along with the entity.
+	Cascade Mode = "CASCADE"
+	// Nullify leaves the books without the entity. Only publishers accept
+	// it, as a book must have an author and its series position means
+	// nothing without a series.
+	Nullify Mode = "NULLIFY"
+)
+
+// Strategy is the mode plus, for ReassignTo, the entity receiving the books.
+type Strategy struct {
+	Mode       Mode
+	ReassignTo *uuid.UUID
+}
+
+// ErrNotFound is returned when an entity does not exist, or is not in the
+// state (live or trashed) an operation needs.
+var ErrNotFound = errors.New("not found")
+
+// Live checks the result of a Lock query for an entity that must exist and
+// not be in the trash.
+func Live(entity string, id uuid.UUID, deletedAt *time.Time, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && deletedAt != nil) {
+		return fmt.Errorf("%s %s: %w", entity, id, ErrNotFound)
+	}
+	return err
+}
+
+// Trashed checks the result of a Lock query for an entity that must be in
+// the trash.
+func Trashed(entity string, id uuid.UUID, deletedAt *time.Time, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && deletedAt == nil) {
+		return fmt.Errorf("%s %s in trash: %w", entity, id, ErrNotFound)
+	}
+	return err
+}
+
+// ErrHasBooks is what a BlockedError unwraps to, for errors.Is checks.
+var ErrHasBooks = errors.New("entity still has books")
+
+// Validate checks the strategy before deleting the entity with the given ID.
+// An empty mode is treated as Restrict. The entity accepts the given modes,
+// or Restrict, ReassignTo and Cascade if none are given.
+func (s Strategy) Validate(id uuid.UUID, modes ...Mode) error {
+	if len(modes) == 0 {
+		modes = []Mode{Restrict, ReassignTo, Cascade}
+	}
+	if s.Mode != "" && !slices.Contains(modes, s.Mode) {
+		return fmt.Errorf("unsupported delete mode %q", s.Mode)
+	}
+	switch s.Mode {
+	case "", Restrict, Cascade, Nullify:
+		return nil
+	case ReassignTo:
+		if s.ReassignTo == nil {
+			return errors.New("reassignTo is required to reassign books")
+		}
+		if *s.ReassignTo == id {
+			return errors.New("cannot reassign books to the entity being deleted")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported delete mode %q", s.Mode)
+	}
+}
+
+// BlockedError is returned under Restrict when books still reference the
+// entity. BookIDs lists every blocking book.
+type BlockedError struct {
+	Entity  string
+	ID      uuid.UUID
+	BookIDs []uuid.UUID
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("cannot delete %s %s: %d books still reference it", e.Entity, e.ID, len(e.BookIDs))
+}
+
+func (e *BlockedError) Unwrap() error {
+	return ErrHasBooks
+}
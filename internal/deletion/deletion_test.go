@@ -0,0 +1,84 @@
+package deletion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestStrategyValidate(t *testing.T) {
+	id := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		wantErr  bool
+	}{
+		{"default", Strategy{}, false},
+		{"restrict", Strategy{Mode: Restrict}, false},
+		{"cascade", Strategy{Mode: Cascade}, false},
+		{"reassign", Strategy{Mode: ReassignTo, ReassignTo: &other}, false},
+		{"reassign without target", Strategy{Mode: ReassignTo}, true},
+		{"reassign to self", Strategy{Mode: ReassignTo, ReassignTo: &id}, true},
+		{"unknown mode", Strategy{Mode: "MERGE"}, true},
+		{"nullify", Strategy{Mode: Nullify}, true},
+	}
+	for _, tt := range tests {
+		err := tt.strategy.Validate(id)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Publishers accept Nullify instead of Cascade.
+	modes := []Mode{Restrict, ReassignTo, Nullify}
+	if err := (Strategy{Mode: Nullify}).Validate(id, modes...); err != nil {
+		t.Fatalf("Validate() with Nullify accepted = %v", err)
+	}
+	if err := (Strategy{Mode: Cascade}).Validate(id, modes...); err == nil {
+		t.Fatalf("expected Cascade to be rejected when only %v are accepted", modes)
+	}
+}
+
+func TestBlockedError(t *testing.T) {
+	var err error = &BlockedError{Entity: "author", ID: uuid.New(), BookIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+
+	if !errors.Is(err, ErrHasBooks) {
+		t.Fatalf("errors.Is(%v, ErrHasBooks) = false", err)
+	}
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) || len(blocked.BookIDs) != 2 {
+		t.Fatalf("errors.As did not recover the blocking book IDs from %v", err)
+	}
+}
+
+func TestLiveAndTrashed(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+	failed := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		deletedAt   *time.Time
+		err         error
+		wantLive    error
+		wantTrashed error
+	}{
+		{"live", nil, nil, nil, ErrNotFound},
+		{"trashed", &now, nil, ErrNotFound, nil},
+		{"missing", nil, pgx.ErrNoRows, ErrNotFound, ErrNotFound},
+		{"query failed", nil, failed, failed, failed},
+	}
+	for _, tt := range tests {
+		if err := Live("book", id, tt.deletedAt, tt.err); !errors.Is(err, tt.wantLive) {
+			t.Fatalf("%s: Live() = %v, want %v", tt.name, err, tt.wantLive)
+		}
+		if err := Trashed("book", id, tt.deletedAt, tt.err); !errors.Is(err, tt.wantTrashed) {
+			t.Fatalf("%s: Trashed() = %v, want %v", tt.name, err, tt.wantTrashed)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package genres
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+}
+
+func NewService(db database.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (s *Service) GetGenre(ctx context.Context, id uuid.UUID) (*sqlc.Genre, error) {
+	genre, err := s.queries.GetGenreByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+func (s *Service) GetGenreBySlug(ctx context.Context, slug string) (*sqlc.Genre, error) {
+	genre, err := s.queries.GetGenreBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+func (s *Service) ListGenres(ctx context.Context, limit, offset int32) ([]sqlc.Genre, error) {
+	return s.queries.ListGenres(ctx, sqlc.ListGenresParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func (s *Service) SearchGenres(ctx context.Context, query string, limit, offset int32) ([]sqlc.Genre, error) {
+	return s.queries.SearchGenres(ctx, sqlc.SearchGenresParams{
+		Column1: &query,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+func (s *Service) GetGenresByBookIDs(ctx context.Context, bookIDs []uuid.UUID) ([]sqlc.GetGenresByBookIDsRow, error) {
+	return s.queries.GetGenresByBookIDs(ctx, bookIDs)
+}
+
+func (s *Service) GetGenreBookCounts(ctx context.Context, genreIDs []uuid.UUID) ([]sqlc.GetGenreBookCountsRow, error) {
+	return s.queries.GetGenreBookCounts(ctx, genreIDs)
+}
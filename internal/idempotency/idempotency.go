@@ -0,0 +1,105 @@
+// Package idempotency makes mutations safe to retry. A mutation sent with a
+// key claims the key in its transaction and stores its response under it
+// before committing; a retry with the same key gets the stored response back
+// instead of being applied a second time.
+package idempotency
+
+import (
+	"book-nexus/internal/audit"
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// TTL is how long a key is remembered. A retry after that is applied
+	// again.
+	TTL = 24 * time.Hour
+	// MaxKeyLength is the longest key accepted, in bytes.
+	MaxKeyLength = 255
+)
+
+// ErrKeyReused is returned when a key comes back with a different request.
+var ErrKeyReused = errors.New("idempotency key was already used for a different request")
+
+// Claim claims key for the caller on ctx. When a request with the key has
+// already completed, its response is decoded into replay and Claim returns
+// true; the earlier request must have been the same operation with the same
+// request, or Claim returns ErrKeyReused. A concurrent request with the same
+// key waits until the first one commits or rolls back.
+//
+// db should be the transaction making the changes, so that rolling them back
+// releases the key.
+func Claim(ctx context.Context, db database.DBTX, key, operation string, request, replay any) (bool, error) {
+	if key == "" || len(key) > MaxKeyLength {
+		return false, fmt.Errorf("idempotency key must be 1 to %d bytes long", MaxKeyLength)
+	}
+	hash, err := requestHash(operation, request)
+	if err != nil {
+		return false, err
+	}
+
+	q := sqlc.New(db)
+	if _, err := q.DeleteExpiredIdempotencyKeys(ctx, time.Now().Add(-TTL)); err != nil {
+		return false, err
+	}
+	actor := audit.CallerFrom(ctx).Actor
+	claimed, err := q.ClaimIdempotencyKey(ctx, sqlc.ClaimIdempotencyKeyParams{
+		Actor:       actor,
+		Key:         key,
+		Operation:   operation,
+		RequestHash: hash,
+	})
+	if err != nil {
+		return false, err
+	}
+	if claimed == 1 {
+		return false, nil
+	}
+
+	stored, err := q.GetIdempotencyKey(ctx, sqlc.GetIdempotencyKeyParams{Actor: actor, Key: key})
+	if err != nil {
+		return false, err
+	}
+	if stored.Operation != operation || !bytes.Equal(stored.RequestHash, hash) {
+		return false, ErrKeyReused
+	}
+	if err := json.Unmarshal(stored.Response, replay); err != nil {
+		return false, fmt.Errorf("idempotency key %q response: %w", key, err)
+	}
+	return true, nil
+}
+
+// Save stores the response to the request that claimed key, in the same
+// transaction.
+func Save(ctx context.Context, db database.DBTX, key string, response any) error {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("idempotency key %q response: %w", key, err)
+	}
+	return sqlc.New(db).SaveIdempotencyResponse(ctx, sqlc.SaveIdempotencyResponseParams{
+		Actor:    audit.CallerFrom(ctx).Actor,
+		Key:      key,
+		Response: b,
+	})
+}
+
+// requestHash identifies a request, so a key reused for a different one is
+// caught.
+func requestHash(operation string, request any) ([]byte, error) {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("idempotency request: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(operation))
+	h.Write([]byte{0})
+	h.Write(b)
+	return h.Sum(nil), nil
+}
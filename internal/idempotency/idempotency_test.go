@@ -0,0 +1,119 @@
+package idempotency
+
+import (
+	"book-nexus/internal/audit"
+	"book-nexus/internal/database/dbtest"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+type request struct {
+	Title string `json:"title"`
+}
+
+type response struct {
+	BookID string `json:"bookId"`
+}
+
+// apply runs one request with key the way a mutation does: claim, then
+// either replay or save a response, in one transaction that commits unless
+// rollback is set.
+func apply(t *testing.T, pool *pgxpool.Pool, ctx context.Context, key, operation string, req request, resp response, rollback bool) (replayed *response, err error) {
+	t.Helper()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var stored response
+	replay, err := Claim(ctx, tx, key, operation, req, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if replay {
+		return &stored, nil
+	}
+	if err := Save(ctx, tx, key, resp); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if !rollback {
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+	}
+	return nil, nil
+}
+
+func TestClaimAndReplay(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := audit.WithCaller(context.Background(), audit.Caller{Actor: "user:1"})
+	dune := request{Title: "Dune"}
+
+	replayed, err := apply(t, pool, ctx, "k1", "upsertBooks", dune, response{BookID: "first"}, false)
+	if err != nil || replayed != nil {
+		t.Fatalf("expected the first request to claim the key, got %v, %v", replayed, err)
+	}
+
+	// The retry gets the first response back and saves nothing.
+	replayed, err = apply(t, pool, ctx, "k1", "upsertBooks", dune, response{BookID: "second"}, false)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed == nil || replayed.BookID != "first" {
+		t.Fatalf("expected the stored response, got %+v", replayed)
+	}
+
+	// Keys belong to the caller that used them.
+	other := audit.WithCaller(context.Background(), audit.Caller{Actor: "user:2"})
+	if replayed, err := apply(t, pool, other, "k1", "upsertBooks", dune, response{}, false); err != nil || replayed != nil {
+		t.Fatalf("expected another caller to claim the same key afresh, got %v, %v", replayed, err)
+	}
+}
+
+func TestClaimRejectsReusedKey(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := audit.WithCaller(context.Background(), audit.Caller{Actor: "user:1"})
+	if _, err := apply(t, pool, ctx, "k1", "upsertBooks", request{Title: "Dune"}, response{}, false); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+
+	if _, err := apply(t, pool, ctx, "k1", "upsertBooks", request{Title: "Emma"}, response{}, false); !errors.Is(err, ErrKeyReused) {
+		t.Fatalf("expected a different request to be rejected, got %v", err)
+	}
+	if _, err := apply(t, pool, ctx, "k1", "createBook", request{Title: "Dune"}, response{}, false); !errors.Is(err, ErrKeyReused) {
+		t.Fatalf("expected a different operation to be rejected, got %v", err)
+	}
+}
+
+func TestRollbackReleasesKey(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := audit.WithCaller(context.Background(), audit.Caller{Actor: "user:1"})
+	dune := request{Title: "Dune"}
+
+	if _, err := apply(t, pool, ctx, "k1", "upsertBooks", dune, response{BookID: "lost"}, true); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	replayed, err := apply(t, pool, ctx, "k1", "upsertBooks", dune, response{BookID: "kept"}, false)
+	if err != nil || replayed != nil {
+		t.Fatalf("expected a rolled back claim to leave the key free, got %v, %v", replayed, err)
+	}
+}
+
+func TestClaimKeyLength(t *testing.T) {
+	for _, key := range []string{"", strings.Repeat("k", MaxKeyLength+1)} {
+		// A key this length is rejected before any query.
+		if _, err := Claim(context.Background(), nil, key, "upsertBooks", request{}, &response{}); err == nil {
+			t.Fatalf("expected a %d byte key to be rejected", len(key))
+		}
+	}
+}
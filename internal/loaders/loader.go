@@ -0,0 +1,124 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Load when the fetch function did not return a
+// value for the requested key.
+var ErrNotFound = errors.New("not found")
+
+// FetchFunc resolves a batch of keys in a single round trip. Keys missing from
+// the returned map are reported to their callers as ErrNotFound.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects keys requested within a short window and resolves them with
+// one call to its FetchFunc. Results are cached for the lifetime of the loader,
+// which is expected to be a single request.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]V
+	batch *batch[K, V]
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	seen    map[K]struct{}
+	results map[K]V
+	err     error
+	once    sync.Once
+	done    chan struct{}
+}
+
+// NewLoader creates a loader that waits up to wait for more keys before
+// fetching, or fetches immediately once maxBatch keys are queued. A maxBatch
+// of zero means no limit.
+func NewLoader[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]V),
+	}
+}
+
+// Load returns the value for key, joining the pending batch if there is one.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	if v, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		return v, nil
+	}
+
+	if l.batch == nil {
+		l.batch = &batch[K, V]{
+			seen: make(map[K]struct{}),
+			done: make(chan struct{}),
+		}
+	}
+	b := l.batch
+	if _, ok := b.seen[key]; !ok {
+		b.seen[key] = struct{}{}
+		b.keys = append(b.keys, key)
+		if len(b.keys) == 1 {
+			go l.startTimer(ctx, b)
+		}
+		if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+			l.batch = nil
+			go l.dispatch(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	var zero V
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+
+	if b.err != nil {
+		return zero, b.err
+	}
+	v, ok := b.results[key]
+	if !ok {
+		return zero, ErrNotFound
+	}
+	return v, nil
+}
+
+// startTimer closes the batch to new keys after the wait window and fetches it.
+func (l *Loader[K, V]) startTimer(ctx context.Context, b *batch[K, V]) {
+	time.Sleep(l.wait)
+
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	l.dispatch(ctx, b)
+}
+
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	b.once.Do(func() {
+		b.results, b.err = l.fetch(ctx, b.keys)
+
+		if b.err == nil {
+			l.mu.Lock()
+			for k, v := range b.results {
+				l.cache[k] = v
+			}
+			l.mu.Unlock()
+		}
+
+		close(b.done)
+	})
+}
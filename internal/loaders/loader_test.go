@@ -0,0 +1,122 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFetch returns a FetchFunc that echoes every key back and records how
+// many times it was invoked, standing in for one database round trip.
+func countingFetch(calls *atomic.Int32) FetchFunc[int, int] {
+	return func(ctx context.Context, keys []int) (map[int]int, error) {
+		calls.Add(1)
+		result := make(map[int]int, len(keys))
+		for _, k := range keys {
+			result[k] = k * 10
+		}
+		return result, nil
+	}
+}
+
+func TestLoaderFixedQueryCount(t *testing.T) {
+	for _, n := range []int{1, 10, 100, 450} {
+		var calls atomic.Int32
+		loader := NewLoader(countingFetch(&calls), 5*time.Millisecond, 500)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(key int) {
+				defer wg.Done()
+				// Every book in a page of results shares a handful of authors.
+				v, err := loader.Load(context.Background(), key%25)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if v != (key%25)*10 {
+					errs <- errors.New("unexpected value")
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Fatalf("n=%d: Load returned error: %v", n, err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("n=%d: expected 1 fetch, got %d", n, got)
+		}
+	}
+}
+
+func TestLoaderSplitsAtMaxBatch(t *testing.T) {
+	var calls atomic.Int32
+	loader := NewLoader(countingFetch(&calls), time.Second, 10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			if _, err := loader.Load(context.Background(), key); err != nil {
+				t.Errorf("Load(%d) returned error: %v", key, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 fetches for 30 keys with maxBatch 10, got %d", got)
+	}
+}
+
+func TestLoaderCachesResults(t *testing.T) {
+	var calls atomic.Int32
+	loader := NewLoader(countingFetch(&calls), time.Millisecond, 0)
+
+	for i := 0; i < 3; i++ {
+		if _, err := loader.Load(context.Background(), 7); err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected cached loads to skip fetch, got %d fetches", got)
+	}
+}
+
+func TestLoaderNotFound(t *testing.T) {
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		return map[int]int{}, nil
+	}, time.Millisecond, 0)
+
+	if _, err := loader.Load(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLoaderFetchError(t *testing.T) {
+	fetchErr := errors.New("connection refused")
+	var calls atomic.Int32
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		calls.Add(1)
+		return nil, fetchErr
+	}, time.Millisecond, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := loader.Load(context.Background(), 1); !errors.Is(err, fetchErr) {
+			t.Fatalf("expected fetch error, got %v", err)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected errors not to be cached, got %d fetches", got)
+	}
+}
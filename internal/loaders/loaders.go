@@ -0,0 +1,274 @@
+// Package loaders provides per-request batching loaders so that resolving a
+// relation across a list of books, authors, publishers or series costs one
+// query per entity type instead of one query per row.
+package loaders
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"book-nexus/internal/authors"
+	"book-nexus/internal/books"
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/genres"
+	"book-nexus/internal/publishers"
+	"book-nexus/internal/series"
+	"book-nexus/internal/tags"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const loadersKey contextKey = "loaders"
+
+const (
+	// batchWait is how long a loader waits for sibling resolvers to enqueue
+	// their keys before it queries the database.
+	batchWait = 2 * time.Millisecond
+	// maxBatch caps the size of the ID array sent in a single query.
+	maxBatch = 500
+)
+
+// Loaders holds the loaders for a single request.
+type Loaders struct {
+	AuthorByID    *Loader[uuid.UUID, *sqlc.Author]
+	PublisherByID *Loader[uuid.UUID, *sqlc.Publisher]
+	SeriesByID    *Loader[uuid.UUID, *sqlc.Series]
+
+	BookCountByAuthor    *Loader[uuid.UUID, int64]
+	BookCountByPublisher *Loader[uuid.UUID, int64]
+	BookCountBySeries    *Loader[uuid.UUID, int64]
+
+	BooksByAuthor    *Loader[uuid.UUID, []*sqlc.Book]
+	BooksByPublisher *Loader[uuid.UUID, []*sqlc.Book]
+	BooksBySeries    *Loader[uuid.UUID, []*sqlc.Book]
+
+	GenresByBook     *Loader[uuid.UUID, []*sqlc.Genre]
+	TagsByBook       *Loader[uuid.UUID, []*sqlc.Tag]
+	BookCountByGenre *Loader[uuid.UUID, int64]
+	BookCountByTag   *Loader[uuid.UUID, int64]
+	BooksByGenre     *Loader[uuid.UUID, []*sqlc.Book]
+	BooksByTag       *Loader[uuid.UUID, []*sqlc.Book]
+}
+
+// New creates a fresh set of loaders backed by db.
+func New(db database.DBTX) *Loaders {
+	authorSvc := authors.NewService(db)
+	bookSvc := books.NewService(db)
+	publisherSvc := publishers.NewService(db)
+	seriesSvc := series.NewService(db)
+	genreSvc := genres.NewService(db)
+	tagSvc := tags.NewService(db)
+
+	return &Loaders{
+		AuthorByID: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*sqlc.Author, error) {
+			rows, err := authorSvc.GetAuthorsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uuid.UUID]*sqlc.Author, len(rows))
+			for i := range rows {
+				result[rows[i].ID] = &rows[i]
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+		PublisherByID: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*sqlc.Publisher, error) {
+			rows, err := publisherSvc.GetPublishersByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uuid.UUID]*sqlc.Publisher, len(rows))
+			for i := range rows {
+				result[rows[i].ID] = &rows[i]
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+		SeriesByID: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*sqlc.Series, error) {
+			rows, err := seriesSvc.GetSeriesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uuid.UUID]*sqlc.Series, len(rows))
+			for i := range rows {
+				result[rows[i].ID] = &rows[i]
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+
+		BookCountByAuthor: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+			rows, err := authorSvc.GetAuthorBookCounts(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := zeroCounts(ids)
+			for _, row := range rows {
+				result[row.AuthorID] = row.BookCount
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+		BookCountByPublisher: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+			rows, err := publisherSvc.GetPublisherBookCounts(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := zeroCounts(ids)
+			for _, row := range rows {
+				result[row.PublisherID.Bytes] = row.BookCount
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+		BookCountBySeries: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+			rows, err := seriesSvc.GetSeriesBookCounts(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := zeroCounts(ids)
+			for _, row := range rows {
+				result[row.SeriesID.Bytes] = row.BookCount
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+
+		BooksByAuthor: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*sqlc.Book, error) {
+			rows, err := bookSvc.GetBooksByAuthorIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return groupBooks(ids, rows, func(b *sqlc.Book) uuid.UUID { return b.AuthorID }), nil
+		}, batchWait, maxBatch),
+		BooksByPublisher: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*sqlc.Book, error) {
+			rows, err := bookSvc.GetBooksByPublisherIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return groupBooks(ids, rows, func(b *sqlc.Book) uuid.UUID { return b.PublisherID.Bytes }), nil
+		}, batchWait, maxBatch),
+		BooksBySeries: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*sqlc.Book, error) {
+			rows, err := bookSvc.GetBooksBySeriesIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return groupBooks(ids, rows, func(b *sqlc.Book) uuid.UUID { return b.SeriesID.Bytes }), nil
+		}, batchWait, maxBatch),
+
+		GenresByBook: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*sqlc.Genre, error) {
+			rows, err := genreSvc.GetGenresByBookIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return group(ids, rows,
+				func(r *sqlc.GetGenresByBookIDsRow) uuid.UUID { return r.BookID },
+				func(r *sqlc.GetGenresByBookIDsRow) *sqlc.Genre { return &r.Genre }), nil
+		}, batchWait, maxBatch),
+		TagsByBook: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*sqlc.Tag, error) {
+			rows, err := tagSvc.GetTagsByBookIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return group(ids, rows,
+				func(r *sqlc.GetTagsByBookIDsRow) uuid.UUID { return r.BookID },
+				func(r *sqlc.GetTagsByBookIDsRow) *sqlc.Tag { return &r.Tag }), nil
+		}, batchWait, maxBatch),
+		BookCountByGenre: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+			rows, err := genreSvc.GetGenreBookCounts(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := zeroCounts(ids)
+			for _, row := range rows {
+				result[row.GenreID] = row.BookCount
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+		BookCountByTag: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+			rows, err := tagSvc.GetTagBookCounts(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := zeroCounts(ids)
+			for _, row := range rows {
+				result[row.TagID] = row.BookCount
+			}
+			return result, nil
+		}, batchWait, maxBatch),
+		BooksByGenre: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*sqlc.Book, error) {
+			rows, err := bookSvc.GetBooksByGenreIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return group(ids, rows,
+				func(r *sqlc.GetBooksByGenreIDsRow) uuid.UUID { return r.GenreID },
+				func(r *sqlc.GetBooksByGenreIDsRow) *sqlc.Book { return &r.Book }), nil
+		}, batchWait, maxBatch),
+		BooksByTag: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*sqlc.Book, error) {
+			rows, err := bookSvc.GetBooksByTagIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return group(ids, rows,
+				func(r *sqlc.GetBooksByTagIDsRow) uuid.UUID { return r.TagID },
+				func(r *sqlc.GetBooksByTagIDsRow) *sqlc.Book { return &r.Book }), nil
+		}, batchWait, maxBatch),
+	}
+}
+
+// zeroCounts seeds a count map so that IDs without any books resolve to 0
+// rather than ErrNotFound.
+func zeroCounts(ids []uuid.UUID) map[uuid.UUID]int64 {
+	result := make(map[uuid.UUID]int64, len(ids))
+	for _, id := range ids {
+		result[id] = 0
+	}
+	return result
+}
+
+// groupBooks splits a grouped query result back into one slice per ID,
+// keeping the order the query returned rows in.
+func groupBooks(ids []uuid.UUID, rows []sqlc.Book, key func(*sqlc.Book) uuid.UUID) map[uuid.UUID][]*sqlc.Book {
+	return group(ids, rows, key, func(b *sqlc.Book) *sqlc.Book { return b })
+}
+
+// group is groupBooks for rows that carry the grouping key next to the value,
+// such as the rows of a join table query.
+func group[R, V any](ids []uuid.UUID, rows []R, key func(*R) uuid.UUID, value func(*R) *V) map[uuid.UUID][]*V {
+	result := make(map[uuid.UUID][]*V, len(ids))
+	for _, id := range ids {
+		result[id] = []*V{}
+	}
+	for i := range rows {
+		id := key(&rows[i])
+		result[id] = append(result[id], value(&rows[i]))
+	}
+	return result
+}
+
+// requestLoaders creates a request's loaders on first use, when the
+// operation is known, so that a query's loaders read from db.Reader's
+// replica and a mutation's from the primary.
+type requestLoaders struct {
+	db      database.Service
+	once    sync.Once
+	loaders *Loaders
+}
+
+// Middleware attaches a new set of loaders to every request so that cached
+// results never leak between requests.
+func Middleware(db database.Service, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey, &requestLoaders{db: db})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// For returns the loaders attached to ctx by Middleware.
+func For(ctx context.Context) *Loaders {
+	rl := ctx.Value(loadersKey).(*requestLoaders)
+	rl.once.Do(func() {
+		rl.loaders = New(rl.db.Reader(ctx))
+	})
+	return rl.loaders
+}
@@ -0,0 +1,68 @@
+package loaders
+
+import (
+	"testing"
+
+	"book-nexus/internal/database/sqlc"
+
+	"github.com/google/uuid"
+)
+
+func TestGroupBooks(t *testing.T) {
+	a, b, empty := uuid.New(), uuid.New(), uuid.New()
+	rows := []sqlc.Book{
+		{Title: "A1", AuthorID: a},
+		{Title: "A2", AuthorID: a},
+		{Title: "B1", AuthorID: b},
+	}
+
+	grouped := groupBooks([]uuid.UUID{a, b, empty}, rows, func(book *sqlc.Book) uuid.UUID { return book.AuthorID })
+
+	if got := len(grouped[a]); got != 2 {
+		t.Fatalf("expected 2 books for a, got %d", got)
+	}
+	if grouped[a][0].Title != "A1" || grouped[a][1].Title != "A2" {
+		t.Fatalf("expected query order to be preserved, got %s, %s", grouped[a][0].Title, grouped[a][1].Title)
+	}
+	if got := len(grouped[b]); got != 1 {
+		t.Fatalf("expected 1 book for b, got %d", got)
+	}
+	books, ok := grouped[empty]
+	if !ok || books == nil || len(books) != 0 {
+		t.Fatalf("expected an empty, non-nil slice for an ID without books, got %v", books)
+	}
+}
+
+func TestZeroCounts(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	counts := zeroCounts(ids)
+
+	for _, id := range ids {
+		count, ok := counts[id]
+		if !ok || count != 0 {
+			t.Fatalf("expected every ID to be seeded with 0, got %d (present: %v)", count, ok)
+		}
+	}
+}
+
+func TestGroupJoinRows(t *testing.T) {
+	book, other := uuid.New(), uuid.New()
+	rows := []sqlc.GetGenresByBookIDsRow{
+		{BookID: book, Genre: sqlc.Genre{Name: "Fantasy"}},
+		{BookID: book, Genre: sqlc.Genre{Name: "Horror"}},
+	}
+
+	grouped := group([]uuid.UUID{book, other}, rows,
+		func(r *sqlc.GetGenresByBookIDsRow) uuid.UUID { return r.BookID },
+		func(r *sqlc.GetGenresByBookIDsRow) *sqlc.Genre { return &r.Genre })
+
+	if got := len(grouped[book]); got != 2 {
+		t.Fatalf("expected 2 genres for book, got %d", got)
+	}
+	if grouped[book][1].Name != "Horror" {
+		t.Fatalf("expected query order to be preserved, got %s", grouped[book][1].Name)
+	}
+	if genres, ok := grouped[other]; !ok || len(genres) != 0 {
+		t.Fatalf("expected an empty slice for a book without genres, got %v", genres)
+	}
+}
@@ -0,0 +1,89 @@
+// Package pagination implements opaque, keyset-based cursors for Relay-style
+// connections.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor identifies a row by the value of the active sort key plus its ID, so
+// the next page starts strictly after it even if rows are inserted meanwhile.
+type Cursor struct {
+	Sort string    `json:"s"`
+	Key  string    `json:"k"`
+	ID   uuid.UUID `json:"i"`
+}
+
+// Encode returns the opaque string form of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode parses a cursor produced by Encode. An empty string yields a nil
+// cursor, meaning "start from the first row". The cursor must have been issued
+// for the same sort order it is being used with.
+func Decode(s string, sort string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != sort {
+		return nil, fmt.Errorf("%w: issued for a different sort order", ErrInvalidCursor)
+	}
+	return &c, nil
+}
+
+// PageSize clamps a requested page size to [1, MaxPageSize], falling back to
+// DefaultPageSize when none was given.
+func PageSize(first *int32) int32 {
+	if first == nil || *first <= 0 {
+		return DefaultPageSize
+	}
+	if *first > MaxPageSize {
+		return MaxPageSize
+	}
+	return *first
+}
+
+// Page is one page of a keyset-paginated result.
+type Page[T any] struct {
+	Items       []T
+	Cursors     []Cursor
+	HasNextPage bool
+}
+
+// NewPage builds a page from rows fetched with a limit of size+1; the extra
+// row, if present, only signals that another page exists.
+func NewPage[T any](rows []T, size int32, cursor func(T) Cursor) *Page[T] {
+	page := &Page[T]{}
+	if int32(len(rows)) > size {
+		rows = rows[:size]
+		page.HasNextPage = true
+	}
+	page.Items = rows
+	page.Cursors = make([]Cursor, len(rows))
+	for i, row := range rows {
+		page.Cursors[i] = cursor(row)
+	}
+	return page
+}
@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{Sort: "title_asc", Key: "Dune", ID: uuid.New()}
+
+	decoded, err := Decode(c.Encode(), "title_asc")
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if *decoded != c {
+		t.Fatalf("expected %+v, got %+v", c, *decoded)
+	}
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	c, err := Decode("", "newest")
+	if err != nil || c != nil {
+		t.Fatalf("expected nil cursor and no error, got %v, %v", c, err)
+	}
+}
+
+func TestDecodeRejectsInvalidCursors(t *testing.T) {
+	other := Cursor{Sort: "newest", Key: "2024-01-01", ID: uuid.New()}.Encode()
+
+	for _, s := range []string{"not base64!", "bm90IGpzb24", other} {
+		if _, err := Decode(s, "title_asc"); !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("Decode(%q): expected ErrInvalidCursor, got %v", s, err)
+		}
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	n := func(v int32) *int32 { return &v }
+	cases := []struct {
+		first *int32
+		want  int32
+	}{
+		{nil, DefaultPageSize},
+		{n(0), DefaultPageSize},
+		{n(-5), DefaultPageSize},
+		{n(10), 10},
+		{n(MaxPageSize + 1), MaxPageSize},
+	}
+	for _, c := range cases {
+		if got := PageSize(c.first); got != c.want {
+			t.Fatalf("PageSize(%v): expected %d, got %d", c.first, c.want, got)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	key := func(s string) Cursor { return Cursor{Key: s} }
+
+	page := NewPage([]string{"a", "b", "c"}, 2, key)
+	if !page.HasNextPage || len(page.Items) != 2 || page.Cursors[1].Key != "b" {
+		t.Fatalf("expected two items and a next page, got %+v", page)
+	}
+
+	page = NewPage([]string{"a", "b"}, 2, key)
+	if page.HasNextPage || len(page.Items) != 2 {
+		t.Fatalf("expected a final page of two items, got %+v", page)
+	}
+}
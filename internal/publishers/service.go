@@ -1,20 +1,28 @@
 package publishers
 
 import (
+	"book-nexus/internal/database"
 	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/deletion"
+	"book-nexus/internal/pagination"
+	"book-nexus/internal/revisions"
+	"book-nexus/internal/slug"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Service struct {
-	db      *pgxpool.Pool
+	db      database.DBTX
 	queries *sqlc.Queries
 }
 
-func NewService(db *pgxpool.Pool) *Service {
+func NewService(db database.DBTX) *Service {
 	return &Service{
 		db:      db,
 		queries: sqlc.New(db),
@@ -29,6 +37,10 @@ func (s *Service) GetPublisher(ctx context.Context, id uuid.UUID) (*sqlc.Publish
 	return &publisher, nil
 }
 
+func (s *Service) GetPublishersByIDs(ctx context.Context, ids []uuid.UUID) ([]sqlc.Publisher, error) {
+	return s.queries.GetPublishersByIDs(ctx, ids)
+}
+
 func (s *Service) GetPublisherBySlug(ctx context.Context, slug string) (*sqlc.Publisher, error) {
 	publisher, err := s.queries.GetPublisherBySlug(ctx, &slug)
 	if err != nil {
@@ -60,14 +72,48 @@ func (s *Service) SearchPublishers(ctx context.Context, query string, limit, off
 	})
 }
 
+// ListPublishersPage returns up to first publishers ordered by name, optionally
+// filtered by search, starting after the opaque cursor after.
+func (s *Service) ListPublishersPage(ctx context.Context, search string, first int32, after string) (*pagination.Page[sqlc.Publisher], error) {
+	cursor, err := pagination.Decode(after, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.ListPublishersPageParams{
+		Search:   search,
+		PageSize: first + 1,
+	}
+	if cursor != nil {
+		params.AfterName = cursor.Key
+		params.AfterID = cursor.ID
+	}
+
+	rows, err := s.queries.ListPublishersPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(rows, first, func(row sqlc.Publisher) pagination.Cursor {
+		return pagination.Cursor{Sort: "name", Key: row.Name, ID: row.ID}
+	}), nil
+}
+
 func (s *Service) CountPublishers(ctx context.Context) (int64, error) {
 	return s.queries.CountPublishers(ctx)
 }
 
+func (s *Service) CountPublishersSearch(ctx context.Context, query string) (int64, error) {
+	return s.queries.CountPublishersSearch(ctx, &query)
+}
+
 func (s *Service) GetPublisherBookCount(ctx context.Context, publisherID uuid.UUID) (int64, error) {
 	return s.queries.GetPublisherBookCount(ctx, pgtype.UUID{Bytes: publisherID, Valid: true})
 }
 
+func (s *Service) GetPublisherBookCounts(ctx context.Context, publisherIDs []uuid.UUID) ([]sqlc.GetPublisherBookCountsRow, error) {
+	return s.queries.GetPublisherBookCounts(ctx, publisherIDs)
+}
+
 type CreatePublisherInput struct {
 	Name    string
 	Slug    *string
@@ -86,6 +132,42 @@ func (s *Service) CreatePublisher(ctx context.Context, input CreatePublisherInpu
 	return &publisher, nil
 }
 
+// UpsertPublisherByName returns the live publisher with exactly this name, creating
+// one when there is none; created reports which. Names are unique across the
+// trash too, so a trashed publisher of that name is an error until it is
+// restored or renamed. A new publisher gets a slug made from its name, with a
+// numeric suffix when that is taken.
+func (s *Service) UpsertPublisherByName(ctx context.Context, name string) (_ *sqlc.Publisher, created bool, err error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, errors.New("publisher name is required")
+	}
+
+	existing, err := s.queries.GetPublisherByName(ctx, name)
+	if err == nil {
+		return &existing, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	taken, err := s.queries.ListPublisherSlugsWithBases(ctx, []string{slug.Make(name)})
+	if err != nil {
+		return nil, false, err
+	}
+	publisher, err := s.queries.UpsertPublisherByName(ctx, sqlc.UpsertPublisherByNameParams{
+		Name: name,
+		Slug: slug.Unique(slug.Taken(taken), slug.Make(name)),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if publisher.DeletedAt != nil {
+		return nil, false, fmt.Errorf("publisher %q is in the trash; restore it first", name)
+	}
+	return &publisher, true, nil
+}
+
 type UpdatePublisherInput struct {
 	ID      uuid.UUID
 	Name    string
@@ -93,8 +175,28 @@ type UpdatePublisherInput struct {
 	Website *string
 }
 
+// UpdatePublisher replaces a publisher's fields, saving the row as it was as a new
+// revision.
 func (s *Service) UpdatePublisher(ctx context.Context, input UpdatePublisherInput) (*sqlc.Publisher, error) {
-	publisher, err := s.queries.UpdatePublisher(ctx, sqlc.UpdatePublisherParams{
+	if input.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	before, err := q.GetPublisherByIDForUpdate(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := revisions.Save(ctx, q, revisions.TypePublisher, before.ID, before); err != nil {
+		return nil, err
+	}
+	publisher, err := q.UpdatePublisher(ctx, sqlc.UpdatePublisherParams{
 		ID:      input.ID,
 		Name:    input.Name,
 		Slug:    input.Slug,
@@ -103,9 +205,88 @@ func (s *Service) UpdatePublisher(ctx context.Context, input UpdatePublisherInpu
 	if err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return &publisher, nil
 }
 
-func (s *Service) DeletePublisher(ctx context.Context, id uuid.UUID) error {
-	return s.queries.DeletePublisher(ctx, id)
+// RevertPublisher updates a publisher back to the fields saved in one of its
+// revisions. It goes through UpdatePublisher, so the current fields are saved as
+// a revision in turn.
+func (s *Service) RevertPublisher(ctx context.Context, id, revisionID uuid.UUID) (*sqlc.Publisher, error) {
+	var snapshot sqlc.Publisher
+	if err := revisions.Load(ctx, s.queries, revisions.TypePublisher, id, revisionID, &snapshot); err != nil {
+		return nil, err
+	}
+	return s.UpdatePublisher(ctx, UpdatePublisherInput{
+		ID:      id,
+		Name:    snapshot.Name,
+		Slug:    snapshot.Slug,
+		Website: snapshot.Website,
+	})
+}
+
+// ErrPublisherHasBooks is what the *deletion.BlockedError returned by
+// DeletePublisher under deletion.Restrict unwraps to.
+var ErrPublisherHasBooks = deletion.ErrHasBooks
+
+// DeletePublisher moves a publisher to the trash, first dealing with its
+// books as the strategy says. Publishers take deletion.Nullify in place of
+// deletion.Cascade: a book outlives its publisher. It runs in a single
+// transaction, so live books are never left pointing at a trashed publisher,
+// and returns the books it reassigned or nullified. Trashed books are only
+// nullified; reassigning leaves them with the trashed publisher.
+func (s *Service) DeletePublisher(ctx context.Context, id uuid.UUID, strategy deletion.Strategy) (moved []sqlc.Book, err error) {
+	if err := strategy.Validate(id, deletion.Restrict, deletion.ReassignTo, deletion.Nullify); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	deletedAt, err := q.LockPublisher(ctx, id)
+	if err := deletion.Live("publisher", id, deletedAt, err); err != nil {
+		return nil, err
+	}
+
+	switch strategy.Mode {
+	case deletion.ReassignTo:
+		deletedAt, err := q.LockPublisher(ctx, *strategy.ReassignTo)
+		if err := deletion.Live("reassign target publisher", *strategy.ReassignTo, deletedAt, err); err != nil {
+			return nil, err
+		}
+		moved, err = q.ReassignPublisherBooks(ctx, sqlc.ReassignPublisherBooksParams{
+			ReassignTo:  *strategy.ReassignTo,
+			PublisherID: id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case deletion.Nullify:
+		moved, err = q.NullifyPublisherBooks(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		bookIDs, err := q.GetPublisherBookIDs(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(bookIDs) > 0 {
+			return nil, &deletion.BlockedError{Entity: "publisher", ID: id, BookIDs: bookIDs}
+		}
+	}
+
+	if err := q.TrashPublisher(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return moved, nil
 }
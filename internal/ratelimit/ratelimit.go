@@ -0,0 +1,93 @@
+// Package ratelimit is a per-client token bucket. Each client has a bucket
+// of Capacity points that refills at Rate points per second; a request takes
+// as many points as it costs, and is refused when the bucket holds too few.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepSize is the number of buckets above which full ones are dropped on
+// the next Take. A full bucket holds no state a new bucket would not.
+const sweepSize = 10000
+
+type Limiter struct {
+	capacity float64
+	rate     float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Result is the outcome of a Take.
+type Result struct {
+	Allowed bool
+	// Remaining is what the bucket holds after the take, rounded down.
+	Remaining int
+	// RetryAfter is how long until the bucket holds enough for the cost,
+	// when it was refused. It is zero for a cost over the capacity, which
+	// can never be taken.
+	RetryAfter time.Duration
+}
+
+// New returns a limiter whose buckets hold capacity points and refill at
+// rate points per second.
+func New(capacity int, rate float64) *Limiter {
+	return &Limiter{
+		capacity: float64(capacity),
+		rate:     rate,
+		buckets:  make(map[string]*bucket),
+		now:      time.Now,
+	}
+}
+
+// Capacity is the size of each bucket.
+func (l *Limiter) Capacity() int {
+	return int(l.capacity)
+}
+
+// Take removes cost points from key's bucket if it holds enough. A refused
+// take removes nothing.
+func (l *Limiter) Take(key string, cost int) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if len(l.buckets) >= sweepSize {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.capacity, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.capacity, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+
+	c := float64(cost)
+	if c > b.tokens {
+		var retry time.Duration
+		if c <= l.capacity && l.rate > 0 {
+			retry = time.Duration((c - b.tokens) / l.rate * float64(time.Second))
+		}
+		return Result{Remaining: int(b.tokens), RetryAfter: retry}
+	}
+	b.tokens -= c
+	return Result{Allowed: true, Remaining: int(b.tokens)}
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*l.rate >= l.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
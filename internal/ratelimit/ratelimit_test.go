@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTakeRefillsOverTime(t *testing.T) {
+	now := time.Now()
+	l := New(100, 10)
+	l.now = func() time.Time { return now }
+
+	if r := l.Take("a", 80); !r.Allowed || r.Remaining != 20 {
+		t.Fatalf("expected 80 to be taken leaving 20, got %+v", r)
+	}
+	r := l.Take("a", 50)
+	if r.Allowed || r.Remaining != 20 || r.RetryAfter != 3*time.Second {
+		t.Fatalf("expected a refusal with 3s to wait, got %+v", r)
+	}
+	if r := l.Take("b", 50); !r.Allowed {
+		t.Fatalf("expected another key to have its own bucket, got %+v", r)
+	}
+
+	now = now.Add(3 * time.Second)
+	if r := l.Take("a", 50); !r.Allowed || r.Remaining != 0 {
+		t.Fatalf("expected the refill to cover 50, got %+v", r)
+	}
+
+	now = now.Add(time.Hour)
+	if r := l.Take("a", 0); r.Remaining != 100 {
+		t.Fatalf("expected the bucket to refill only to capacity, got %+v", r)
+	}
+}
+
+func TestTakeOverCapacity(t *testing.T) {
+	l := New(100, 10)
+	if r := l.Take("a", 101); r.Allowed || r.RetryAfter != 0 {
+		t.Fatalf("expected a cost over capacity to be refused for good, got %+v", r)
+	}
+}
@@ -1,20 +1,20 @@
 package recommendations
 
 import (
+	"book-nexus/internal/database"
 	"book-nexus/internal/database/sqlc"
 	"context"
 	"sort"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Service struct {
-	db      *pgxpool.Pool
+	db      database.DBTX
 	queries *sqlc.Queries
 }
 
-func NewService(db *pgxpool.Pool) *Service {
+func NewService(db database.DBTX) *Service {
 	return &Service{
 		db:      db,
 		queries: sqlc.New(db),
@@ -47,6 +47,7 @@ func rowToBook(row sqlc.GetRecommendationsByTagsRow) sqlc.Book {
 		ImageUrl:       row.ImageUrl,
 		CreatedAt:      row.CreatedAt,
 		UpdatedAt:      row.UpdatedAt,
+		DeletedAt:      row.DeletedAt,
 	}
 }
 
@@ -96,25 +97,22 @@ func (s *Service) GetRecommendations(ctx context.Context, bookID uuid.UUID, limi
 	}
 
 	// Tag overlap books
-	if book.Tags != nil && *book.Tags != "" {
-		tagBooks, err := s.queries.GetRecommendationsByTags(ctx, sqlc.GetRecommendationsByTagsParams{
-			ID:      bookID,
-			Column2: *book.Tags,
-			Limit:   int32(limit * 2), // Get more to filter
-		})
-		if err == nil {
-			for _, row := range tagBooks {
-				b := rowToBook(row)
-				if _, exists := scored[b.ID]; !exists {
-					scored[b.ID] = &ScoredBook{Book: b, Score: 0}
-				}
-				// Add 1 point per matching tag (up to 3)
-				tagScore := int(row.TagMatches)
-				if tagScore > 3 {
-					tagScore = 3
-				}
-				scored[b.ID].Score += tagScore
+	tagBooks, err := s.queries.GetRecommendationsByTags(ctx, sqlc.GetRecommendationsByTagsParams{
+		ID:    bookID,
+		Limit: int32(limit * 2), // Get more to filter
+	})
+	if err == nil {
+		for _, row := range tagBooks {
+			b := rowToBook(row)
+			if _, exists := scored[b.ID]; !exists {
+				scored[b.ID] = &ScoredBook{Book: b, Score: 0}
+			}
+			// Add 1 point per matching tag (up to 3)
+			tagScore := int(row.TagMatches)
+			if tagScore > 3 {
+				tagScore = 3
 			}
+			scored[b.ID].Score += tagScore
 		}
 	}
 
@@ -0,0 +1,123 @@
+// Package revisions keeps the history of catalog rows. The update paths of
+// the entity services save the row as it was just before each update, inside
+// the same transaction, so any earlier version can be inspected or reverted
+// to.
+package revisions
+
+import (
+	"book-nexus/internal/audit"
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Entity types a revision can belong to. They match the GraphQL EntityType
+// enum.
+const (
+	TypeBook      = "BOOK"
+	TypeAuthor    = "AUTHOR"
+	TypeSeries    = "SERIES"
+	TypePublisher = "PUBLISHER"
+)
+
+// ErrNotFound is returned when a revision does not exist or belongs to a
+// different entity than the one being reverted.
+var ErrNotFound = errors.New("revision not found")
+
+// Save stores snapshot as the next revision of the entity, attributed to
+// the caller on ctx. q should be bound to the transaction doing the update,
+// with the entity row already locked.
+func Save(ctx context.Context, q *sqlc.Queries, entityType string, id uuid.UUID, snapshot any) error {
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("revision snapshot: %w", err)
+	}
+
+	caller := audit.CallerFrom(ctx)
+	var requestID *string
+	if caller.RequestID != "" {
+		requestID = &caller.RequestID
+	}
+	return q.CreateEntityRevision(ctx, sqlc.CreateEntityRevisionParams{
+		EntityType: entityType,
+		EntityID:   id,
+		Snapshot:   b,
+		Actor:      caller.Actor,
+		RequestID:  requestID,
+	})
+}
+
+// SaveAll is Save for several entities of one type, sent in one batch.
+// snapshots maps each entity's ID to its row as it was.
+func SaveAll(ctx context.Context, q *sqlc.Queries, entityType string, snapshots map[uuid.UUID]any) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	caller := audit.CallerFrom(ctx)
+	var requestID *string
+	if caller.RequestID != "" {
+		requestID = &caller.RequestID
+	}
+	params := make([]sqlc.CreateEntityRevisionsParams, 0, len(snapshots))
+	for id, snapshot := range snapshots {
+		b, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("revision snapshot: %w", err)
+		}
+		params = append(params, sqlc.CreateEntityRevisionsParams{
+			EntityType: entityType,
+			EntityID:   id,
+			Snapshot:   b,
+			Actor:      caller.Actor,
+			RequestID:  requestID,
+		})
+	}
+
+	var batchErr error
+	q.CreateEntityRevisions(ctx, params).Exec(func(_ int, err error) {
+		if err != nil && batchErr == nil {
+			batchErr = err
+		}
+	})
+	return batchErr
+}
+
+// Load fetches a revision of the given entity and decodes its snapshot into
+// dst.
+func Load(ctx context.Context, q *sqlc.Queries, entityType string, entityID, revisionID uuid.UUID, dst any) error {
+	rev, err := q.GetEntityRevision(ctx, revisionID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && (rev.EntityType != entityType || rev.EntityID != entityID)) {
+		return fmt.Errorf("%w: %s for %s %s", ErrNotFound, revisionID, entityType, entityID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(rev.Snapshot, dst); err != nil {
+		return fmt.Errorf("revision %s snapshot: %w", revisionID, err)
+	}
+	return nil
+}
+
+type Service struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+}
+
+func NewService(db database.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// ListRevisions returns every revision of an entity, newest first.
+func (s *Service) ListRevisions(ctx context.Context, entityID uuid.UUID) ([]sqlc.EntityRevision, error) {
+	return s.queries.ListEntityRevisions(ctx, entityID)
+}
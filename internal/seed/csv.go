@@ -0,0 +1,228 @@
+package seed
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// errMissingRequired marks rows without a title or author. They are skipped
+// without a log line, like blank rows.
+var errMissingRequired = errors.New("title and author are required")
+
+// requiredColumns must be in the header. Other known columns may be left out.
+var requiredColumns = []string{"title", "author"}
+
+// book is one parsed CSV row, with its relations still as names.
+type book struct {
+	Title          string
+	Subtitle       *string
+	Author         string
+	Publisher      string
+	PublishedDate  *time.Time
+	ISBN10         *string
+	ISBN13         *string
+	Pages          *int32
+	Language       *string
+	Description    *string
+	Series         string
+	SeriesPosition *int32
+	Genres         *string
+	Tags           *string
+	ImageURL       *string
+}
+
+// batch is the next rows of the file.
+type batch struct {
+	books []book
+	// read counts every record consumed, skipped ones included.
+	read    int64
+	skipped int64
+	// offset is where the record after the batch starts in the file.
+	offset int64
+}
+
+// reader streams a CSV file's rows in batches, starting at any record
+// boundary.
+type reader struct {
+	src    io.ReadSeeker
+	csv    *csv.Reader
+	header int
+	cols   map[string]int
+	// base is where csv started reading in the file.
+	base int64
+}
+
+// newReader reads the header of src, then moves to offset, the start of a
+// record as returned by an earlier batch, or stays after the header when
+// offset is 0.
+func newReader(src io.ReadSeeker, offset int64) (*reader, error) {
+	r := &reader{src: src, csv: newCSVReader(src)}
+	header, err := r.csv.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	r.header = len(header)
+	r.cols = make(map[string]int, len(header))
+	for i, col := range header {
+		r.cols[strings.TrimSpace(col)] = i
+	}
+	for _, col := range requiredColumns {
+		if _, ok := r.cols[col]; !ok {
+			return nil, fmt.Errorf("CSV header has no %q column", col)
+		}
+	}
+
+	if offset > 0 {
+		if _, err := src.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		r.csv = newCSVReader(src)
+		r.base = offset
+	}
+	return r, nil
+}
+
+func newCSVReader(src io.Reader) *csv.Reader {
+	cr := csv.NewReader(src)
+	cr.LazyQuotes = true
+	cr.TrimLeadingSpace = true
+	// Short rows are skipped rather than failing the import.
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return cr
+}
+
+// offset returns where the next record starts in the file.
+func (r *reader) offset() int64 {
+	return r.base + r.csv.InputOffset()
+}
+
+// next reads up to n rows. Rows that cannot be imported are counted as
+// skipped, and logged unless they are just missing a title or author. It
+// returns io.EOF once the file is exhausted.
+func (r *reader) next(n int, logf func(format string, args ...any)) (*batch, error) {
+	b := &batch{books: make([]book, 0, n)}
+	for len(b.books) < n {
+		record, err := r.csv.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			b.read++
+			b.skipped++
+			logf("Skipping CSV row: %v", err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.read++
+
+		bk, err := r.parse(record)
+		if err != nil {
+			b.skipped++
+			if !errors.Is(err, errMissingRequired) {
+				line, _ := r.csv.FieldPos(0)
+				logf("Skipping CSV row on line %d: %v", line, err)
+			}
+			continue
+		}
+		b.books = append(b.books, bk)
+	}
+	b.offset = r.offset()
+	if b.read == 0 {
+		return nil, io.EOF
+	}
+	return b, nil
+}
+
+// parse converts a record into a book. Values are trimmed, the ".0" that
+// spreadsheets leave on numbers is dropped, empty values become NULL and
+// unparsable dates, pages and positions are left out.
+func (r *reader) parse(record []string) (book, error) {
+	if len(record) < r.header {
+		return book{}, fmt.Errorf("expected %d fields, got %d", r.header, len(record))
+	}
+	field := func(col string) string {
+		i, ok := r.cols[col]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	number := func(col string) string {
+		return strings.TrimSuffix(field(col), ".0")
+	}
+
+	bk := book{
+		Title:          field("title"),
+		Subtitle:       optional(field("subtitle")),
+		Author:         field("author"),
+		Publisher:      field("publisher"),
+		PublishedDate:  parseDate(field("publishedDate")),
+		ISBN10:         optional(number("isbn10")),
+		ISBN13:         optional(number("isbn13")),
+		Pages:          parsePositive(number("pages")),
+		Language:       optional(field("language")),
+		Description:    optional(field("description")),
+		Series:         field("series_name"),
+		SeriesPosition: parsePositive(number("series_position")),
+		Genres:         optional(field("genres")),
+		Tags:           optional(field("tags")),
+		ImageURL:       optional(field("image_url")),
+	}
+	if bk.Title == "" || bk.Author == "" {
+		return book{}, errMissingRequired
+	}
+	// Anything the books table would reject has to be caught here: one bad
+	// row fails the COPY of its whole batch.
+	if bk.ISBN10 != nil && len(*bk.ISBN10) > 10 {
+		return book{}, errors.New("ISBN-10 is longer than 10 characters")
+	}
+	if bk.ISBN13 != nil && len(*bk.ISBN13) > 13 {
+		return book{}, errors.New("ISBN-13 is longer than 13 characters")
+	}
+	for _, v := range record {
+		if !utf8.ValidString(v) || strings.ContainsRune(v, 0) {
+			return book{}, errors.New("invalid UTF-8 or NUL byte")
+		}
+	}
+	return bk, nil
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func parseDate(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func parsePositive(s string) *int32 {
+	if s == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || n <= 0 {
+		return nil
+	}
+	v := int32(n)
+	return &v
+}
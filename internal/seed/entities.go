@@ -0,0 +1,154 @@
+package seed
+
+import (
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/slug"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// entities resolves the author, publisher or series names of the import to
+// IDs, creating the ones that do not exist yet. IDs are cached for the whole
+// import, so each name costs a query only the first time it appears. Names
+// of trashed rows resolve too, and are marked so their books can be skipped.
+type entities[T any] struct {
+	noun string
+
+	listSlugs       func(*sqlc.Queries, context.Context) ([]*string, error)
+	getByNames      func(*sqlc.Queries, context.Context, []string) ([]T, error)
+	createWithSlugs func(q *sqlc.Queries, ctx context.Context, names, slugs []string) ([]T, error)
+	upsertByNames   func(*sqlc.Queries, context.Context, []string) ([]T, error)
+	key             func(T) (id uuid.UUID, name string, deletedAt *time.Time)
+
+	ids     map[string]uuid.UUID
+	trashed map[string]bool
+	// slugs holds every slug in the table, so new rows get unique ones
+	// without probing for each.
+	slugs   map[string]bool
+	created int
+}
+
+func newAuthors() *entities[sqlc.Author] {
+	return &entities[sqlc.Author]{
+		noun:       "authors",
+		listSlugs:  (*sqlc.Queries).ListAuthorSlugs,
+		getByNames: (*sqlc.Queries).GetAuthorsByNames,
+		createWithSlugs: func(q *sqlc.Queries, ctx context.Context, names, slugs []string) ([]sqlc.Author, error) {
+			return q.CreateAuthorsWithSlugs(ctx, sqlc.CreateAuthorsWithSlugsParams{Names: names, Slugs: slugs})
+		},
+		upsertByNames: (*sqlc.Queries).UpsertAuthorsByName,
+		key:           func(a sqlc.Author) (uuid.UUID, string, *time.Time) { return a.ID, a.Name, a.DeletedAt },
+	}
+}
+
+func newPublishers() *entities[sqlc.Publisher] {
+	return &entities[sqlc.Publisher]{
+		noun:       "publishers",
+		listSlugs:  (*sqlc.Queries).ListPublisherSlugs,
+		getByNames: (*sqlc.Queries).GetPublishersByNames,
+		createWithSlugs: func(q *sqlc.Queries, ctx context.Context, names, slugs []string) ([]sqlc.Publisher, error) {
+			return q.CreatePublishersWithSlugs(ctx, sqlc.CreatePublishersWithSlugsParams{Names: names, Slugs: slugs})
+		},
+		upsertByNames: (*sqlc.Queries).UpsertPublishersByName,
+		key:           func(p sqlc.Publisher) (uuid.UUID, string, *time.Time) { return p.ID, p.Name, p.DeletedAt },
+	}
+}
+
+func newSeries() *entities[sqlc.Series] {
+	return &entities[sqlc.Series]{
+		noun:       "series",
+		listSlugs:  (*sqlc.Queries).ListSeriesSlugs,
+		getByNames: (*sqlc.Queries).GetSeriesByNames,
+		createWithSlugs: func(q *sqlc.Queries, ctx context.Context, names, slugs []string) ([]sqlc.Series, error) {
+			return q.CreateSeriesWithSlugs(ctx, sqlc.CreateSeriesWithSlugsParams{Names: names, Slugs: slugs})
+		},
+		upsertByNames: (*sqlc.Queries).UpsertSeriesByNames,
+		key:           func(s sqlc.Series) (uuid.UUID, string, *time.Time) { return s.ID, s.Name, s.DeletedAt },
+	}
+}
+
+// load reads the slugs already taken.
+func (e *entities[T]) load(ctx context.Context, q *sqlc.Queries) error {
+	slugs, err := e.listSlugs(q, ctx)
+	if err != nil {
+		return fmt.Errorf("list %s slugs: %w", e.noun, err)
+	}
+	e.ids = make(map[string]uuid.UUID)
+	e.trashed = make(map[string]bool)
+	e.slugs = slug.Taken(slugs)
+	return nil
+}
+
+// resolve makes sure every name has an ID, with at most three queries for
+// the names not seen before: one to find the existing ones, one to create
+// the rest and, rarely, one for names whose slug was taken in the meantime.
+// Trashed rows match too, since names are unique across the trash.
+func (e *entities[T]) resolve(ctx context.Context, q *sqlc.Queries, names []string) error {
+	var missing []string
+	for _, name := range names {
+		if _, ok := e.ids[name]; !ok && name != "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	// Sorted so concurrent imports insert, and so lock, names in the same
+	// order.
+	slices.Sort(missing)
+	missing = slices.Compact(missing)
+
+	found, err := e.getByNames(q, ctx, missing)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", e.noun, err)
+	}
+	e.add(found)
+	missing = e.unresolved(missing)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	slugs := make([]string, len(missing))
+	for i, name := range missing {
+		slugs[i] = slug.Unique(e.slugs, slug.Make(name))
+	}
+	created, err := e.createWithSlugs(q, ctx, missing, slugs)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", e.noun, err)
+	}
+	e.add(created)
+	e.created += len(created)
+	missing = e.unresolved(missing)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// Someone else took the name or slug since; these go without a slug.
+	upserted, err := e.upsertByNames(q, ctx, missing)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", e.noun, err)
+	}
+	e.add(upserted)
+	return nil
+}
+
+func (e *entities[T]) add(rows []T) {
+	for _, row := range rows {
+		id, name, deletedAt := e.key(row)
+		e.ids[name] = id
+		if deletedAt != nil {
+			e.trashed[name] = true
+		}
+	}
+}
+
+func (e *entities[T]) unresolved(names []string) []string {
+	return slices.DeleteFunc(names, func(name string) bool {
+		_, ok := e.ids[name]
+		return ok
+	})
+}
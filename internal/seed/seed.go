@@ -0,0 +1,301 @@
+// Package seed imports books from a CSV file. The file is streamed in
+// batches, each written in one transaction: the batch's authors, publishers
+// and series are resolved with a few set-based queries, its books are copied
+// into a staging table and merged into books, and a checkpoint records how
+// far the import got. An interrupted import resumes after the last committed
+// batch.
+package seed
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultBatchSize is how many rows a batch holds when Options leaves it
+// unset.
+const DefaultBatchSize = 5000
+
+type Options struct {
+	// BatchSize is how many rows are written per transaction.
+	BatchSize int
+	// Restart ignores the checkpoint of an earlier import of the file.
+	Restart bool
+	// Logf reports progress and skipped rows, log.Printf when nil.
+	Logf func(format string, args ...any)
+}
+
+// Stats counts the rows of the whole import, including the batches of an
+// earlier, interrupted run.
+type Stats struct {
+	RowsRead      int64
+	BooksInserted int64
+	// RowsSkipped counts rows that could not be imported, those naming a
+	// trashed author, publisher or series included. Books whose ISBN-13 is
+	// taken are not skipped rows, only not inserted.
+	RowsSkipped int64
+	// Authors, Publishers and Series count the rows this run added.
+	Authors    int
+	Publishers int
+	Series     int
+	// ResumedAt is the row the import resumed after, 0 for a fresh import.
+	ResumedAt int64
+	Elapsed   time.Duration
+}
+
+// RowsPerSecond is the throughput of this run.
+func (s *Stats) RowsPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.RowsRead-s.ResumedAt) / s.Elapsed.Seconds()
+}
+
+// bookColumns are the books columns the staging table carries.
+var bookColumns = []string{
+	"title", "subtitle", "author_id", "publisher_id", "published_date", "isbn10", "isbn13",
+	"pages", "language", "description", "series_id", "series_position", "genres", "tags", "image_url",
+}
+
+const (
+	createStaging = `CREATE TEMP TABLE seed_books ON COMMIT DROP AS
+SELECT title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13,
+    pages, language, description, series_id, series_position, genres, tags, image_url
+FROM books WITH NO DATA`
+
+	mergeStaging = `INSERT INTO books (
+    title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13,
+    pages, language, description, series_id, series_position, genres, tags, image_url
+)
+SELECT title, subtitle, author_id, publisher_id, published_date, isbn10, isbn13,
+    pages, language, description, series_id, series_position, genres, tags, image_url
+FROM seed_books
+ON CONFLICT (isbn13) DO NOTHING`
+)
+
+// Import loads the books of the CSV file at path. Books whose ISBN-13 is
+// already in the catalog are left alone. It returns with an error on the
+// first batch that fails, ctx being canceled included; running it again
+// carries on from there unless opts.Restart is set.
+func Import(ctx context.Context, db database.DBTX, path string, opts Options) (*Stats, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Logf == nil {
+		opts.Logf = log.Printf
+	}
+	start := time.Now()
+
+	source, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(db)
+	stats := &Stats{}
+	var offset int64
+	checkpoint, err := q.GetSeedCheckpoint(ctx, source)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("get checkpoint: %w", err)
+	case opts.Restart:
+		opts.Logf("Ignoring the checkpoint at row %d, starting over", checkpoint.RowsRead)
+	case checkpoint.FileSize != info.Size() || !checkpoint.FileModifiedAt.Equal(info.ModTime()):
+		opts.Logf("%s changed since the checkpoint at row %d, starting over", path, checkpoint.RowsRead)
+	default:
+		offset = checkpoint.ByteOffset
+		stats.RowsRead = checkpoint.RowsRead
+		stats.BooksInserted = checkpoint.BooksInserted
+		stats.RowsSkipped = checkpoint.RowsSkipped
+		stats.ResumedAt = checkpoint.RowsRead
+		opts.Logf("Resuming after row %d", checkpoint.RowsRead)
+	}
+
+	r, err := newReader(file, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	im := &importer{
+		db:         db,
+		queries:    q,
+		stats:      stats,
+		logf:       opts.Logf,
+		authors:    newAuthors(),
+		publishers: newPublishers(),
+		series:     newSeries(),
+		checkpoint: sqlc.SaveSeedCheckpointParams{
+			Source:         source,
+			FileSize:       info.Size(),
+			FileModifiedAt: info.ModTime(),
+		},
+	}
+	for _, load := range []func(context.Context, *sqlc.Queries) error{im.authors.load, im.publishers.load, im.series.load} {
+		if err := load(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		b, err := r.next(opts.BatchSize, opts.Logf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("read CSV after row %d: %w", stats.RowsRead, err)
+		}
+		if err := im.write(ctx, b); err != nil {
+			return stats, fmt.Errorf("import rows %d-%d: %w", stats.RowsRead+1, stats.RowsRead+b.read, err)
+		}
+		stats.Elapsed = time.Since(start)
+		opts.Logf("Imported %d rows: %d books inserted, %d skipped (%.0f rows/s)",
+			stats.RowsRead, stats.BooksInserted, stats.RowsSkipped, stats.RowsPerSecond())
+	}
+
+	// The file is done, so a later import of it starts from the top.
+	if err := q.DeleteSeedCheckpoint(ctx, source); err != nil {
+		return stats, fmt.Errorf("delete checkpoint: %w", err)
+	}
+	stats.Elapsed = time.Since(start)
+	return stats, nil
+}
+
+// importer writes the batches of one Import.
+type importer struct {
+	db         database.DBTX
+	queries    *sqlc.Queries
+	stats      *Stats
+	logf       func(format string, args ...any)
+	authors    *entities[sqlc.Author]
+	publishers *entities[sqlc.Publisher]
+	series     *entities[sqlc.Series]
+	// checkpoint is saved with each batch, with the file's identity filled
+	// in up front.
+	checkpoint sqlc.SaveSeedCheckpointParams
+}
+
+// write imports a batch and moves the checkpoint past it in one
+// transaction, then adds it to the stats.
+func (im *importer) write(ctx context.Context, b *batch) error {
+	tx, err := im.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := im.queries.WithTx(tx)
+
+	authors := make([]string, 0, len(b.books))
+	var publishers, series []string
+	for _, bk := range b.books {
+		authors = append(authors, bk.Author)
+		publishers = append(publishers, bk.Publisher)
+		series = append(series, bk.Series)
+	}
+	created := [3]int{im.authors.created, im.publishers.created, im.series.created}
+	if err := im.authors.resolve(ctx, q, authors); err != nil {
+		return err
+	}
+	if err := im.publishers.resolve(ctx, q, publishers); err != nil {
+		return err
+	}
+	if err := im.series.resolve(ctx, q, series); err != nil {
+		return err
+	}
+
+	// Books are not linked to trashed rows, which a restore or purge would
+	// not expect to have live books.
+	books := make([]book, 0, len(b.books))
+	skipped := b.skipped
+	for _, bk := range b.books {
+		if reason := im.trashed(bk); reason != "" {
+			im.logf("Skipping CSV row for %q: its %s is in the trash", bk.Title, reason)
+			skipped++
+			continue
+		}
+		books = append(books, bk)
+	}
+
+	if _, err := tx.Exec(ctx, createStaging); err != nil {
+		return fmt.Errorf("create staging table: %w", err)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"seed_books"}, bookColumns, pgx.CopyFromSlice(len(books), func(i int) ([]any, error) {
+		return im.values(books[i]), nil
+	})); err != nil {
+		return fmt.Errorf("copy books: %w", err)
+	}
+	tag, err := tx.Exec(ctx, mergeStaging)
+	if err != nil {
+		return fmt.Errorf("merge books: %w", err)
+	}
+
+	cp := im.checkpoint
+	cp.ByteOffset = b.offset
+	cp.RowsRead = im.stats.RowsRead + b.read
+	cp.BooksInserted = im.stats.BooksInserted + tag.RowsAffected()
+	cp.RowsSkipped = im.stats.RowsSkipped + skipped
+	if err := q.SaveSeedCheckpoint(ctx, cp); err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	im.stats.RowsRead = cp.RowsRead
+	im.stats.BooksInserted = cp.BooksInserted
+	im.stats.RowsSkipped = cp.RowsSkipped
+	im.stats.Authors += im.authors.created - created[0]
+	im.stats.Publishers += im.publishers.created - created[1]
+	im.stats.Series += im.series.created - created[2]
+	return nil
+}
+
+// trashed names the relation of a book that is in the trash, if any.
+func (im *importer) trashed(bk book) string {
+	switch {
+	case im.authors.trashed[bk.Author]:
+		return fmt.Sprintf("author %q", bk.Author)
+	case im.publishers.trashed[bk.Publisher]:
+		return fmt.Sprintf("publisher %q", bk.Publisher)
+	case im.series.trashed[bk.Series]:
+		return fmt.Sprintf("series %q", bk.Series)
+	}
+	return ""
+}
+
+// values returns a book's row of the staging table, in bookColumns order.
+func (im *importer) values(bk book) []any {
+	return []any{
+		bk.Title, bk.Subtitle, im.authors.ids[bk.Author], optionalID(im.publishers.ids, bk.Publisher),
+		bk.PublishedDate, bk.ISBN10, bk.ISBN13, bk.Pages, bk.Language, bk.Description,
+		optionalID(im.series.ids, bk.Series), bk.SeriesPosition, bk.Genres, bk.Tags, bk.ImageURL,
+	}
+}
+
+// optionalID looks up an optional relation, NULL when the name is empty.
+func optionalID(ids map[string]uuid.UUID, name string) pgtype.UUID {
+	if name == "" {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: ids[name], Valid: true}
+}
@@ -0,0 +1,161 @@
+package seed
+
+import (
+	"book-nexus/internal/database/dbtest"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+const testCSV = `title,subtitle,author,publisher,publishedDate,isbn10,isbn13,pages,language,description,series_name,series_position,genres,tags,image_url
+Dune,,Frank Herbert,Chilton,1965-08-01,0441013597.0,9780441013593.0,412.0,en,"Desert
+planet",Dune,1.0,Science Fiction,,
+,,Nobody,,,,,,,,,,,,
+Dune Messiah,,Frank Herbert,,not a date,,97804410135930,,,,Dune,,,,
+Children of Dune,,Frank Herbert,,,,,-3,,,Dune,3,,,
+short,row
+God Emperor of Dune,,Frank Herbert,,,,,,,,Dune,4,,,
+`
+
+func TestReaderBatches(t *testing.T) {
+	var logged []string
+	logf := func(format string, args ...any) { logged = append(logged, format) }
+
+	r, err := newReader(strings.NewReader(testCSV), 0)
+	if err != nil {
+		t.Fatalf("newReader: %v", err)
+	}
+	b, err := r.next(2, logf)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	// The row without a title is skipped quietly; the long ISBN-13 is
+	// logged.
+	if len(b.books) != 2 || b.read != 4 || b.skipped != 2 || len(logged) != 1 {
+		t.Fatalf("unexpected first batch: %d books, %d read, %d skipped, %d logged", len(b.books), b.read, b.skipped, len(logged))
+	}
+	dune := b.books[0]
+	if *dune.ISBN10 != "0441013597" || *dune.ISBN13 != "9780441013593" || *dune.Pages != 412 || *dune.SeriesPosition != 1 {
+		t.Fatalf("expected spreadsheet numbers to be cleaned up, got %+v", dune)
+	}
+	if dune.PublishedDate == nil || dune.PublishedDate.Year() != 1965 || *dune.Description != "Desert\nplanet" {
+		t.Fatalf("unexpected fields: %+v", dune)
+	}
+	if dune.Subtitle != nil || dune.Tags != nil {
+		t.Fatalf("expected empty values to be NULL, got %+v", dune)
+	}
+	children := b.books[1]
+	if children.Title != "Children of Dune" || children.Pages != nil || children.Publisher != "" {
+		t.Fatalf("unexpected second book: %+v", children)
+	}
+
+	rest, err := r.next(2, logf)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if len(rest.books) != 1 || rest.skipped != 1 || rest.books[0].Title != "God Emperor of Dune" {
+		t.Fatalf("unexpected second batch: %+v", rest)
+	}
+	if _, err := r.next(2, logf); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	// Resuming at the end of the first batch reads the same rows again.
+	resumed, err := newReader(strings.NewReader(testCSV), b.offset)
+	if err != nil {
+		t.Fatalf("newReader: %v", err)
+	}
+	again, err := resumed.next(2, logf)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if len(again.books) != 1 || again.books[0].Title != "God Emperor of Dune" || again.offset != rest.offset {
+		t.Fatalf("expected the resumed reader to match, got %+v", again)
+	}
+}
+
+func TestReaderHeader(t *testing.T) {
+	if _, err := newReader(strings.NewReader("title,publisher\nDune,Chilton\n"), 0); err == nil {
+		t.Fatal("expected a header without an author column to be rejected")
+	}
+}
+
+const importCSV = `title,author,publisher,isbn13,series_name,series_position
+Dune,Frank Herbert,Chilton,9780441013593,Dune,1
+Dune Messiah,Frank Herbert,,9780441172696,Dune,2
+Children of Dune,Frank Herbert,,9780441104024,Dune,3
+Dune,Frank Herbert,Chilton,9780441013593,Dune,1
+Old Book,Retired Author,,9780000000002,,
+`
+
+func TestImportResumes(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+
+	retired, err := q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Name: "Retired Author"})
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	if err := q.TrashAuthor(ctx, retired.ID); err != nil {
+		t.Fatalf("trash author: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "books.csv")
+	if err := os.WriteFile(path, []byte(importCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first run is interrupted once its first batch has committed.
+	interrupted, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopAfterBatch := func(format string, args ...any) {
+		if strings.HasPrefix(format, "Imported") {
+			cancel()
+		}
+	}
+	stats, err := Import(interrupted, pool, path, Options{BatchSize: 2, Logf: stopAfterBatch})
+	if err == nil {
+		t.Fatal("expected the import to stop when canceled")
+	}
+	if stats.RowsRead != 2 || stats.BooksInserted != 2 || stats.Authors != 1 || stats.Publishers != 1 || stats.Series != 1 {
+		t.Fatalf("unexpected stats for the first batch: %+v", stats)
+	}
+
+	var logged []string
+	logf := func(format string, args ...any) { logged = append(logged, format) }
+	stats, err = Import(ctx, pool, path, Options{BatchSize: 2, Logf: logf})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	// The repeated Dune is not inserted again, and the book of the trashed
+	// author is skipped.
+	if stats.ResumedAt != 2 || stats.RowsRead != 5 || stats.BooksInserted != 3 || stats.RowsSkipped != 1 || stats.Authors != 0 {
+		t.Fatalf("unexpected stats after resuming: %+v", stats)
+	}
+	if !strings.HasPrefix(logged[0], "Resuming") {
+		t.Fatalf("expected the second run to resume, logged %q", logged)
+	}
+
+	var books, retiredBooks int
+	if err := pool.QueryRow(ctx, "SELECT count(*), count(*) FILTER (WHERE author_id = $1) FROM books", retired.ID).Scan(&books, &retiredBooks); err != nil {
+		t.Fatalf("count books: %v", err)
+	}
+	if books != 3 || retiredBooks != 0 {
+		t.Fatalf("expected 3 books, none by the trashed author, got %d and %d", books, retiredBooks)
+	}
+	source, _ := filepath.Abs(path)
+	if _, err := q.GetSeedCheckpoint(ctx, source); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected the checkpoint to be deleted once the file is done, got %v", err)
+	}
+}
@@ -1,20 +1,28 @@
 package series
 
 import (
+	"book-nexus/internal/database"
 	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/deletion"
+	"book-nexus/internal/pagination"
+	"book-nexus/internal/revisions"
+	"book-nexus/internal/slug"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Service struct {
-	db      *pgxpool.Pool
+	db      database.DBTX
 	queries *sqlc.Queries
 }
 
-func NewService(db *pgxpool.Pool) *Service {
+func NewService(db database.DBTX) *Service {
 	return &Service{
 		db:      db,
 		queries: sqlc.New(db),
@@ -29,6 +37,10 @@ func (s *Service) GetSeries(ctx context.Context, id uuid.UUID) (*sqlc.Series, er
 	return &series, nil
 }
 
+func (s *Service) GetSeriesByIDs(ctx context.Context, ids []uuid.UUID) ([]sqlc.Series, error) {
+	return s.queries.GetSeriesByIDs(ctx, ids)
+}
+
 func (s *Service) GetSeriesBySlug(ctx context.Context, slug string) (*sqlc.Series, error) {
 	series, err := s.queries.GetSeriesBySlug(ctx, &slug)
 	if err != nil {
@@ -60,14 +72,48 @@ func (s *Service) SearchSeries(ctx context.Context, query string, limit, offset
 	})
 }
 
+// ListSeriesPage returns up to first series ordered by name, optionally
+// filtered by search, starting after the opaque cursor after.
+func (s *Service) ListSeriesPage(ctx context.Context, search string, first int32, after string) (*pagination.Page[sqlc.Series], error) {
+	cursor, err := pagination.Decode(after, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.ListSeriesPageParams{
+		Search:   search,
+		PageSize: first + 1,
+	}
+	if cursor != nil {
+		params.AfterName = cursor.Key
+		params.AfterID = cursor.ID
+	}
+
+	rows, err := s.queries.ListSeriesPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(rows, first, func(row sqlc.Series) pagination.Cursor {
+		return pagination.Cursor{Sort: "name", Key: row.Name, ID: row.ID}
+	}), nil
+}
+
 func (s *Service) CountSeries(ctx context.Context) (int64, error) {
 	return s.queries.CountSeries(ctx)
 }
 
+func (s *Service) CountSeriesSearch(ctx context.Context, query string) (int64, error) {
+	return s.queries.CountSeriesSearch(ctx, &query)
+}
+
 func (s *Service) GetSeriesBookCount(ctx context.Context, seriesID uuid.UUID) (int64, error) {
 	return s.queries.GetSeriesBookCount(ctx, pgtype.UUID{Bytes: seriesID, Valid: true})
 }
 
+func (s *Service) GetSeriesBookCounts(ctx context.Context, seriesIDs []uuid.UUID) ([]sqlc.GetSeriesBookCountsRow, error) {
+	return s.queries.GetSeriesBookCounts(ctx, seriesIDs)
+}
+
 type CreateSeriesInput struct {
 	Name        string
 	Slug        *string
@@ -86,6 +132,42 @@ func (s *Service) CreateSeries(ctx context.Context, input CreateSeriesInput) (*s
 	return &series, nil
 }
 
+// UpsertSeriesByName returns the live series with exactly this name, creating
+// one when there is none; created reports which. Names are unique across the
+// trash too, so a trashed series of that name is an error until it is
+// restored or renamed. A new series gets a slug made from its name, with a
+// numeric suffix when that is taken.
+func (s *Service) UpsertSeriesByName(ctx context.Context, name string) (_ *sqlc.Series, created bool, err error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, errors.New("series name is required")
+	}
+
+	existing, err := s.queries.GetSeriesByName(ctx, name)
+	if err == nil {
+		return &existing, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	taken, err := s.queries.ListSeriesSlugsWithBases(ctx, []string{slug.Make(name)})
+	if err != nil {
+		return nil, false, err
+	}
+	series, err := s.queries.UpsertSeriesByName(ctx, sqlc.UpsertSeriesByNameParams{
+		Name: name,
+		Slug: slug.Unique(slug.Taken(taken), slug.Make(name)),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if series.DeletedAt != nil {
+		return nil, false, fmt.Errorf("series %q is in the trash; restore it first", name)
+	}
+	return &series, true, nil
+}
+
 type UpdateSeriesInput struct {
 	ID          uuid.UUID
 	Name        string
@@ -93,8 +175,28 @@ type UpdateSeriesInput struct {
 	Description *string
 }
 
+// UpdateSeries replaces a series's fields, saving the row as it was as a new
+// revision.
 func (s *Service) UpdateSeries(ctx context.Context, input UpdateSeriesInput) (*sqlc.Series, error) {
-	series, err := s.queries.UpdateSeries(ctx, sqlc.UpdateSeriesParams{
+	if input.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	before, err := q.GetSeriesByIDForUpdate(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := revisions.Save(ctx, q, revisions.TypeSeries, before.ID, before); err != nil {
+		return nil, err
+	}
+	series, err := q.UpdateSeries(ctx, sqlc.UpdateSeriesParams{
 		ID:          input.ID,
 		Name:        input.Name,
 		Slug:        input.Slug,
@@ -103,9 +205,85 @@ func (s *Service) UpdateSeries(ctx context.Context, input UpdateSeriesInput) (*s
 	if err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return &series, nil
 }
 
-func (s *Service) DeleteSeries(ctx context.Context, id uuid.UUID) error {
-	return s.queries.DeleteSeries(ctx, id)
+// RevertSeries updates a series back to the fields saved in one of its
+// revisions. It goes through UpdateSeries, so the current fields are saved as
+// a revision in turn.
+func (s *Service) RevertSeries(ctx context.Context, id, revisionID uuid.UUID) (*sqlc.Series, error) {
+	var snapshot sqlc.Series
+	if err := revisions.Load(ctx, s.queries, revisions.TypeSeries, id, revisionID, &snapshot); err != nil {
+		return nil, err
+	}
+	return s.UpdateSeries(ctx, UpdateSeriesInput{
+		ID:          id,
+		Name:        snapshot.Name,
+		Slug:        snapshot.Slug,
+		Description: snapshot.Description,
+	})
+}
+
+// DeleteSeries moves a series to the trash, first dealing with its books as the
+// strategy says. Under Cascade the books are trashed with it, at the same
+// deleted_at, so restoring the series brings them back. The series row is locked
+// for the whole transaction, so no book can be attached to it between the
+// check and the delete. It returns the books trashed along with it, or the
+// live books moved to another series; trashed books stay with the trashed
+// series.
+func (s *Service) DeleteSeries(ctx context.Context, id uuid.UUID, strategy deletion.Strategy) (trashed, moved []sqlc.Book, err error) {
+	if err := strategy.Validate(id); err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	deletedAt, err := q.LockSeries(ctx, id)
+	if err := deletion.Live("series", id, deletedAt, err); err != nil {
+		return nil, nil, err
+	}
+
+	switch strategy.Mode {
+	case deletion.ReassignTo:
+		deletedAt, err := q.LockSeries(ctx, *strategy.ReassignTo)
+		if err := deletion.Live("reassign target series", *strategy.ReassignTo, deletedAt, err); err != nil {
+			return nil, nil, err
+		}
+		moved, err = q.ReassignSeriesBooks(ctx, sqlc.ReassignSeriesBooksParams{
+			ReassignTo: *strategy.ReassignTo,
+			SeriesID:   id,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	case deletion.Cascade:
+		trashed, err = q.TrashSeriesBooks(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		bookIDs, err := q.GetSeriesBookIDs(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(bookIDs) > 0 {
+			return nil, nil, &deletion.BlockedError{Entity: "series", ID: id, BookIDs: bookIDs}
+		}
+	}
+
+	if err := q.TrashSeries(ctx, id); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return trashed, moved, nil
 }
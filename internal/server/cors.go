@@ -0,0 +1,171 @@
+package server
+
+import (
+	"book-nexus/internal/config"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	corsAllowMethods  = "GET, POST, OPTIONS"
+	corsAllowHeaders  = "Content-Type, Authorization, X-Admin-Password, X-Request-ID"
+	corsExposeHeaders = "X-Request-ID, X-Query-Cost, X-RateLimit-Limit, X-RateLimit-Remaining, Retry-After"
+
+	// eventCORSRejected is logged for requests from origins that are not
+	// allowed.
+	eventCORSRejected = "cors_origin_rejected"
+)
+
+// corsOrigins is a set of allowed origins: exact origins, patterns with *
+// standing for one host label or a port, like https://*.example.com, or *
+// for any origin.
+type corsOrigins struct {
+	any      bool
+	exact    map[string]bool
+	patterns []*regexp.Regexp
+}
+
+func parseCORSOrigins(list []string) corsOrigins {
+	o := corsOrigins{exact: make(map[string]bool)}
+	for _, origin := range list {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "":
+		case origin == "*":
+			o.any = true
+		case strings.Contains(origin, "*"):
+			parts := strings.Split(origin, "*")
+			for i, part := range parts {
+				parts[i] = regexp.QuoteMeta(part)
+			}
+			o.patterns = append(o.patterns, regexp.MustCompile("^"+strings.Join(parts, "[a-z0-9-]+")+"$"))
+		default:
+			o.exact[origin] = true
+		}
+	}
+	return o
+}
+
+func (o corsOrigins) allows(origin string) bool {
+	origin = strings.ToLower(origin)
+	if o.any || o.exact[origin] {
+		return true
+	}
+	for _, p := range o.patterns {
+		if p.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// corsPolicy decides which cross-origin requests are served. Requests whose
+// Origin is not allowed are rejected with 403 and logged; requests from the
+// server's own host and requests without an Origin header, which do not come
+// from a cross-origin browser context, pass through untouched.
+type corsPolicy struct {
+	origins     corsOrigins
+	credentials bool
+	maxAge      time.Duration
+	// routes override the allowed origins for paths under a prefix, longest
+	// prefix first.
+	routes []corsRoute
+}
+
+type corsRoute struct {
+	prefix  string
+	origins corsOrigins
+}
+
+// newCORSPolicy builds the policy from its configuration, which has been
+// validated.
+func newCORSPolicy(cfg config.CORS) *corsPolicy {
+	p := &corsPolicy{
+		origins:     parseCORSOrigins(cfg.AllowedOrigins),
+		credentials: cfg.AllowCredentials,
+		maxAge:      cfg.MaxAge,
+	}
+	for prefix, origins := range cfg.RouteOrigins {
+		p.setRoute(prefix, origins)
+	}
+	return p
+}
+
+// setRoute overrides the allowed origins for paths under prefix.
+func (p *corsPolicy) setRoute(prefix string, origins []string) {
+	p.routes = append(p.routes, corsRoute{prefix: prefix, origins: parseCORSOrigins(origins)})
+	sort.SliceStable(p.routes, func(i, j int) bool {
+		return len(p.routes[i].prefix) > len(p.routes[j].prefix)
+	})
+}
+
+func (p *corsPolicy) originsFor(path string) corsOrigins {
+	for _, route := range p.routes {
+		if strings.HasPrefix(path, route.prefix) {
+			return route.origins
+		}
+	}
+	return p.origins
+}
+
+// sameHost reports whether origin names the host the request was sent to,
+// as browsers send Origin on same-origin POSTs too.
+func sameHost(origin string, r *http.Request) bool {
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, r.Host)
+}
+
+func (s *Server) withCORS(handler http.Handler) http.Handler {
+	p := s.cors
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || sameHost(origin, r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+
+		origins := p.originsFor(r.URL.Path)
+		if !origins.allows(origin) {
+			slog.Warn(eventCORSRejected,
+				"origin", origin,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"remote_ip", clientIP(r))
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		// With credentials the origin must be echoed; without, a route open
+		// to any origin can answer with * and be cached across origins.
+		credentials := p.credentials && !origins.any
+		if origins.any && !credentials {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if credentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+
+		// Handle preflight requests
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.maxAge.Seconds())))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
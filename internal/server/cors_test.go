@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serveCORS(p *corsPolicy, method, path, origin string) *httptest.ResponseRecorder {
+	s := &Server{cors: p}
+	handler := s.withCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	r := httptest.NewRequest(method, "http://api.example.com"+path, nil)
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	if method == http.MethodOptions {
+		r.Header.Set("Access-Control-Request-Method", "POST")
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestCORSOrigins(t *testing.T) {
+	o := parseCORSOrigins([]string{"https://books.example.com/", "https://*.netlify.app", "http://localhost:*"})
+
+	for _, origin := range []string{"https://books.example.com", "https://BOOKS.example.com", "https://preview-42.netlify.app", "http://localhost:5173"} {
+		if !o.allows(origin) {
+			t.Fatalf("expected %s to be allowed", origin)
+		}
+	}
+	for _, origin := range []string{"https://evil.com", "https://books.example.com.evil.com", "https://a.b.netlify.app", "https://netlify.app", "http://localhost"} {
+		if o.allows(origin) {
+			t.Fatalf("expected %s to be rejected", origin)
+		}
+	}
+}
+
+func TestCORSAllowsListedOrigin(t *testing.T) {
+	p := &corsPolicy{
+		origins:     parseCORSOrigins([]string{"https://books.example.com"}),
+		credentials: true,
+		maxAge:      time.Hour,
+	}
+
+	w := serveCORS(p, http.MethodOptions, "/query", "https://books.example.com")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected preflight to succeed, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://books.example.com" {
+		t.Fatalf("expected the origin to be echoed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("expected credentials to be allowed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "3600" {
+		t.Fatalf("expected max age 3600, got %q", got)
+	}
+
+	w = serveCORS(p, http.MethodPost, "/query", "https://books.example.com")
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") == "" {
+		t.Fatalf("expected the request to be served with CORS headers, got %d", w.Code)
+	}
+}
+
+func TestCORSRejectsOtherOrigins(t *testing.T) {
+	p := &corsPolicy{origins: parseCORSOrigins([]string{"https://books.example.com"})}
+
+	for _, method := range []string{http.MethodOptions, http.MethodPost} {
+		w := serveCORS(p, method, "/query", "https://evil.com")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("expected %s from another origin to be rejected, got %d", method, w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Fatalf("expected no Access-Control-Allow-Origin, got %q", got)
+		}
+	}
+
+	// Same-origin requests and requests without an Origin are not CORS.
+	if w := serveCORS(p, http.MethodPost, "/query", "http://api.example.com"); w.Code != http.StatusOK {
+		t.Fatalf("expected a same-origin request to be served, got %d", w.Code)
+	}
+	if w := serveCORS(p, http.MethodPost, "/query", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected a request without an origin to be served, got %d", w.Code)
+	}
+}
+
+func TestCORSRouteOverride(t *testing.T) {
+	p := &corsPolicy{
+		origins:     parseCORSOrigins([]string{"https://books.example.com"}),
+		credentials: true,
+	}
+	p.setRoute("/health", []string{"*"})
+
+	w := serveCORS(p, http.MethodGet, "/health", "https://status.example.org")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the health check to be open to any origin, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("expected *, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Fatalf("expected no credentials for a * route, got %q", got)
+	}
+
+	if w := serveCORS(p, http.MethodPost, "/query", "https://status.example.org"); w.Code != http.StatusForbidden {
+		t.Fatalf("expected other routes to keep the default policy, got %d", w.Code)
+	}
+}
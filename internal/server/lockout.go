@@ -0,0 +1,105 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// lockoutThreshold is how many wrong admin passwords a client may send
+	// before it is locked out.
+	lockoutThreshold = 5
+	// lockoutBase is the first lockout; each further failure doubles it.
+	lockoutBase = time.Second
+	// lockoutMax caps the lockout.
+	lockoutMax = 15 * time.Minute
+	// lockoutForget is how long a client must go without failing before its
+	// failures are forgotten.
+	lockoutForget = time.Hour
+	// lockoutSweepSize is the number of tracked clients above which idle
+	// ones are swept out on the next failure.
+	lockoutSweepSize = 10000
+)
+
+// lockout tracks failed admin password attempts per client and locks a client
+// out with exponential backoff once it passes lockoutThreshold. State is kept
+// in memory, per process.
+type lockout struct {
+	mu      sync.Mutex
+	clients map[string]*lockoutEntry
+	now     func() time.Time
+}
+
+type lockoutEntry struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+func newLockout() *lockout {
+	return &lockout{
+		clients: make(map[string]*lockoutEntry),
+		now:     time.Now,
+	}
+}
+
+// Locked returns how much longer the client is locked out, or zero.
+func (l *lockout) Locked(client string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.clients[client]
+	if !ok {
+		return 0
+	}
+	if remaining := entry.lockedUntil.Sub(l.now()); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Fail records a wrong password and returns the client's failure count and
+// the lockout it now has, zero while under the threshold.
+func (l *lockout) Fail(client string) (int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if len(l.clients) >= lockoutSweepSize {
+		l.sweep(now)
+	}
+
+	entry, ok := l.clients[client]
+	if !ok || now.Sub(entry.lastFailure) > lockoutForget {
+		entry = &lockoutEntry{}
+		l.clients[client] = entry
+	}
+	entry.failures++
+	entry.lastFailure = now
+
+	if entry.failures < lockoutThreshold {
+		return entry.failures, 0
+	}
+	d := lockoutBase
+	for i := lockoutThreshold; i < entry.failures && d < lockoutMax; i++ {
+		d *= 2
+	}
+	d = min(d, lockoutMax)
+	entry.lockedUntil = now.Add(d)
+	return entry.failures, d
+}
+
+// Succeed forgets the client's failures after a correct password.
+func (l *lockout) Succeed(client string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.clients, client)
+}
+
+func (l *lockout) sweep(now time.Time) {
+	for client, entry := range l.clients {
+		if now.Sub(entry.lastFailure) > lockoutForget && !now.Before(entry.lockedUntil) {
+			delete(l.clients, client)
+		}
+	}
+}
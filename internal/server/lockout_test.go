@@ -0,0 +1,82 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLockoutBacksOffExponentially(t *testing.T) {
+	now := time.Now()
+	l := newLockout()
+	l.now = func() time.Time { return now }
+
+	for i := 1; i < lockoutThreshold; i++ {
+		if _, d := l.Fail("10.0.0.1"); d != 0 {
+			t.Fatalf("failure %d: expected no lockout, got %v", i, d)
+		}
+	}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		if _, d := l.Fail("10.0.0.1"); d != want {
+			t.Fatalf("failure %d past threshold: expected %v, got %v", i, want, d)
+		}
+	}
+	if got := l.Locked("10.0.0.1"); got != 4*time.Second {
+		t.Fatalf("expected 4s left, got %v", got)
+	}
+	if got := l.Locked("10.0.0.2"); got != 0 {
+		t.Fatalf("expected another client not to be locked, got %v", got)
+	}
+
+	now = now.Add(5 * time.Second)
+	if got := l.Locked("10.0.0.1"); got != 0 {
+		t.Fatalf("expected the lockout to have passed, got %v", got)
+	}
+}
+
+func TestLockoutCapsAndResets(t *testing.T) {
+	now := time.Now()
+	l := newLockout()
+	l.now = func() time.Time { return now }
+
+	var d time.Duration
+	for i := 0; i < lockoutThreshold+40; i++ {
+		_, d = l.Fail("10.0.0.1")
+	}
+	if d != lockoutMax {
+		t.Fatalf("expected the lockout to be capped at %v, got %v", lockoutMax, d)
+	}
+
+	l.Succeed("10.0.0.1")
+	if got := l.Locked("10.0.0.1"); got != 0 {
+		t.Fatalf("expected success to clear the lockout, got %v", got)
+	}
+
+	for i := 0; i < lockoutThreshold-1; i++ {
+		l.Fail("10.0.0.3")
+	}
+	now = now.Add(lockoutForget + time.Minute)
+	if failures, d := l.Fail("10.0.0.3"); failures != 1 || d != 0 {
+		t.Fatalf("expected old failures to be forgotten, got %d failures and %v", failures, d)
+	}
+}
+
+func TestCheckAdminPassword(t *testing.T) {
+	s := &Server{}
+	if s.checkAdminPassword("anything") {
+		t.Fatalf("expected every password to fail without ADMIN_PASSWORD_HASH")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	s = &Server{adminPasswordHash: hash}
+	if !s.checkAdminPassword("correct horse") {
+		t.Fatalf("expected the right password to pass")
+	}
+	if s.checkAdminPassword("correct hors") {
+		t.Fatalf("expected a wrong password to fail")
+	}
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// persistedQueries is a gqlgen extension for strict persisted-query mode:
+// only operations listed in a manifest generated from the frontend's codegen
+// output are executed. It replaces AutomaticPersistedQuery, so clients cannot
+// register new queries.
+//
+// The manifest is a JSON object mapping each document's SHA-256 hash to its
+// text. A request may send just the hash in extensions.persistedQuery, as
+// with automatic persisted queries, or the full text. Full text is matched
+// after normalizing it, so whitespace and the order of fragments do not
+// matter.
+type persistedQueries struct {
+	// documents maps manifest hashes to query text.
+	documents map[string]string
+	// allowed holds the canonical hash of every document.
+	allowed map[string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = &persistedQueries{}
+
+// loadPersistedQueries reads a manifest file.
+func loadPersistedQueries(path string) (*persistedQueries, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest map[string]string
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid persisted query manifest: %w", err)
+	}
+	return newPersistedQueries(manifest)
+}
+
+func newPersistedQueries(manifest map[string]string) (*persistedQueries, error) {
+	p := &persistedQueries{
+		documents: make(map[string]string, len(manifest)),
+		allowed:   make(map[string]bool, len(manifest)),
+	}
+	for hash, query := range manifest {
+		canonical, err := canonicalQueryHash(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid persisted query %s: %w", hash, err)
+		}
+		p.documents[strings.ToLower(hash)] = query
+		p.allowed[canonical] = true
+	}
+	return p, nil
+}
+
+func (p *persistedQueries) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (p *persistedQueries) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (p *persistedQueries) MutateOperationParameters(ctx context.Context, params *graphql.RawParams) *gqlerror.Error {
+	if params.Query == "" {
+		hash := persistedQueryHash(params.Extensions)
+		if hash == "" {
+			return nil
+		}
+		query, ok := p.documents[strings.ToLower(hash)]
+		if !ok {
+			return persistedQueryNotAllowed()
+		}
+		params.Query = query
+		return nil
+	}
+
+	canonical, err := canonicalQueryHash(params.Query)
+	if err != nil || !p.allowed[canonical] {
+		return persistedQueryNotAllowed()
+	}
+	return nil
+}
+
+func persistedQueryNotAllowed() *gqlerror.Error {
+	return &gqlerror.Error{
+		Message: "query is not in the persisted query allowlist",
+		Extensions: map[string]any{
+			"code": "PERSISTED_QUERY_NOT_ALLOWED",
+		},
+	}
+}
+
+// persistedQueryHash is the hash from an automatic persisted query request's
+// extensions, or "".
+func persistedQueryHash(extensions map[string]any) string {
+	pq, ok := extensions["persistedQuery"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	hash, _ := pq["sha256Hash"].(string)
+	return hash
+}
+
+// canonicalQueryHash hashes a query document printed in a normal form:
+// compacted, with operations and fragments each sorted by name.
+func canonicalQueryHash(query string) (string, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return "", err
+	}
+	slices.SortStableFunc(doc.Operations, func(a, b *ast.OperationDefinition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortStableFunc(doc.Fragments, func(a, b *ast.FragmentDefinition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithCompacted()).FormatQueryDocument(doc)
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
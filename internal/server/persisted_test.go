@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func TestPersistedQueries(t *testing.T) {
+	const query = `query Books { books { ...Item } } fragment Item on Book { id title }`
+	const hash = "abc123"
+	p, err := newPersistedQueries(map[string]string{hash: query})
+	if err != nil {
+		t.Fatalf("newPersistedQueries: %v", err)
+	}
+	ctx := context.Background()
+
+	// The same document with different whitespace and fragment order.
+	params := &graphql.RawParams{Query: `
+		fragment Item on Book { id
+			title }
+		query Books {
+			books { ...Item }
+		}
+	`}
+	if err := p.MutateOperationParameters(ctx, params); err != nil {
+		t.Fatalf("expected a registered query to be allowed, got %v", err)
+	}
+
+	params = &graphql.RawParams{Query: `query Books { books { id title isbn } }`}
+	if err := p.MutateOperationParameters(ctx, params); err == nil || err.Extensions["code"] != "PERSISTED_QUERY_NOT_ALLOWED" {
+		t.Fatalf("expected an unregistered query to be rejected, got %v", err)
+	}
+
+	params = &graphql.RawParams{Extensions: map[string]any{
+		"persistedQuery": map[string]any{"version": 1, "sha256Hash": hash},
+	}}
+	if err := p.MutateOperationParameters(ctx, params); err != nil {
+		t.Fatalf("expected a registered hash to be allowed, got %v", err)
+	}
+	if params.Query != query {
+		t.Fatalf("expected the hash to resolve to the registered query, got %q", params.Query)
+	}
+
+	params = &graphql.RawParams{Extensions: map[string]any{
+		"persistedQuery": map[string]any{"version": 1, "sha256Hash": "unknown"},
+	}}
+	if err := p.MutateOperationParameters(ctx, params); err == nil {
+		t.Fatalf("expected an unknown hash to be rejected")
+	}
+}
+
+func TestPersistedQueriesRejectsInvalidManifest(t *testing.T) {
+	if _, err := newPersistedQueries(map[string]string{"abc": "query {"}); err == nil {
+		t.Fatalf("expected a document that does not parse to be rejected")
+	}
+}
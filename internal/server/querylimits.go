@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// depthLimit is a gqlgen extension that rejects operations whose selections
+// nest deeper than max. Introspection fields are not counted, since
+// introspection queries are deep by nature and are disabled in production.
+type depthLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = depthLimit{}
+
+func (d depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d depthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d depthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+	if depth := selectionDepth(op.SelectionSet, map[string]bool{}); depth > d.max {
+		return &gqlerror.Error{
+			Message: "query is nested too deeply",
+			Extensions: map[string]any{
+				"code":     "QUERY_TOO_DEEP",
+				"depth":    depth,
+				"maxDepth": d.max,
+			},
+		}
+	}
+	return nil
+}
+
+// selectionDepth is the number of nested field levels in set, following
+// fragments. visiting guards against fragment cycles, which validation
+// should already have rejected.
+func selectionDepth(set ast.SelectionSet, visiting map[string]bool) int {
+	deepest := 0
+	for _, sel := range set {
+		depth := 0
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if sel.Name == "__schema" || sel.Name == "__type" {
+				continue
+			}
+			depth = 1 + selectionDepth(sel.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			depth = selectionDepth(sel.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			if sel.Definition == nil || visiting[sel.Name] {
+				continue
+			}
+			visiting[sel.Name] = true
+			depth = selectionDepth(sel.Definition.SelectionSet, visiting)
+			delete(visiting, sel.Name)
+		}
+		deepest = max(deepest, depth)
+	}
+	return deepest
+}
+
+// listLimit is a gqlgen extension that rejects operations asking for more
+// than max items through a limit or first argument, including the limit
+// field of input objects like SearchBooksInput.
+type listLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = listLimit{}
+
+func (l listLimit) ExtensionName() string {
+	return "ListLimit"
+}
+
+func (l listLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (l listLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+	if field, n, ok := l.firstOverLimit(op.SelectionSet, opCtx.Variables, map[string]bool{}); ok {
+		return &gqlerror.Error{
+			Message: "requested too many items from " + field,
+			Extensions: map[string]any{
+				"code":     "LIMIT_TOO_LARGE",
+				"field":    field,
+				"limit":    n,
+				"maxLimit": l.max,
+			},
+		}
+	}
+	return nil
+}
+
+func (l listLimit) firstOverLimit(set ast.SelectionSet, vars map[string]any, visiting map[string]bool) (string, int64, bool) {
+	for _, sel := range set {
+		var (
+			field string
+			n     int64
+			ok    bool
+		)
+		switch sel := sel.(type) {
+		case *ast.Field:
+			for _, arg := range sel.Arguments {
+				if n, ok := l.overLimit(arg.Name, arg.Value, vars); ok {
+					return sel.Name, n, true
+				}
+			}
+			field, n, ok = l.firstOverLimit(sel.SelectionSet, vars, visiting)
+		case *ast.InlineFragment:
+			field, n, ok = l.firstOverLimit(sel.SelectionSet, vars, visiting)
+		case *ast.FragmentSpread:
+			if sel.Definition == nil || visiting[sel.Name] {
+				continue
+			}
+			visiting[sel.Name] = true
+			field, n, ok = l.firstOverLimit(sel.Definition.SelectionSet, vars, visiting)
+			delete(visiting, sel.Name)
+		}
+		if ok {
+			return field, n, true
+		}
+	}
+	return "", 0, false
+}
+
+// overLimit checks one argument, or the fields of an input object argument.
+func (l listLimit) overLimit(name string, value *ast.Value, vars map[string]any) (int64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	v, err := value.Value(vars)
+	if err != nil {
+		return 0, false
+	}
+	return l.overLimitValue(name, v)
+}
+
+func (l listLimit) overLimitValue(name string, v any) (int64, bool) {
+	if obj, ok := v.(map[string]any); ok {
+		for k, child := range obj {
+			if n, ok := l.overLimitValue(k, child); ok {
+				return n, true
+			}
+		}
+		return 0, false
+	}
+	if name != "limit" && name != "first" {
+		return 0, false
+	}
+	n, ok := toInt64(v)
+	return n, ok && n > int64(l.max)
+}
+
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
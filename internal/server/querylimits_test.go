@@ -0,0 +1,74 @@
+package server
+
+import (
+	"book-nexus/graph"
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2"
+)
+
+func runOperationMutator(t *testing.T, m graphql.OperationContextMutator, query string, vars map[string]any) map[string]any {
+	t.Helper()
+	es := graph.NewExecutableSchema(graph.Config{})
+	doc, errs := gqlparser.LoadQuery(es.Schema(), query)
+	if errs != nil {
+		t.Fatalf("parse %q: %v", query, errs)
+	}
+	opCtx := &graphql.OperationContext{Doc: doc, Variables: vars}
+	if err := m.MutateOperationContext(context.Background(), opCtx); err != nil {
+		return err.Extensions
+	}
+	return nil
+}
+
+func TestDepthLimit(t *testing.T) {
+	d := depthLimit{max: 3}
+
+	if ext := runOperationMutator(t, d, `{ books { author { name } } }`, nil); ext != nil {
+		t.Fatalf("expected depth 3 to be allowed, got %v", ext)
+	}
+	ext := runOperationMutator(t, d, `{ books { author { books { title } } } }`, nil)
+	if ext == nil || ext["code"] != "QUERY_TOO_DEEP" || ext["depth"] != 4 {
+		t.Fatalf("expected depth 4 to be rejected, got %v", ext)
+	}
+
+	// Fragments count towards the depth of where they are spread.
+	query := `
+		query { books { ...BookAuthor } }
+		fragment BookAuthor on Book { author { books { title } } }
+	`
+	if ext := runOperationMutator(t, d, query, nil); ext == nil || ext["code"] != "QUERY_TOO_DEEP" {
+		t.Fatalf("expected a deep fragment to be rejected, got %v", ext)
+	}
+
+	// Introspection is not counted.
+	if ext := runOperationMutator(t, d, `{ __schema { types { fields { type { ofType { name } } } } } }`, nil); ext != nil {
+		t.Fatalf("expected introspection to be allowed, got %v", ext)
+	}
+}
+
+func TestListLimit(t *testing.T) {
+	l := listLimit{max: 50}
+
+	if ext := runOperationMutator(t, l, `{ books(limit: 50) { title } }`, nil); ext != nil {
+		t.Fatalf("expected limit 50 to be allowed, got %v", ext)
+	}
+	ext := runOperationMutator(t, l, `{ books(limit: 51) { title } }`, nil)
+	if ext == nil || ext["code"] != "LIMIT_TOO_LARGE" || ext["field"] != "books" {
+		t.Fatalf("expected limit 51 to be rejected, got %v", ext)
+	}
+
+	ext = runOperationMutator(t, l, `query($n: Int) { booksConnection(first: $n) { totalCount } }`,
+		map[string]any{"n": int64(500)})
+	if ext == nil || ext["code"] != "LIMIT_TOO_LARGE" {
+		t.Fatalf("expected first from a variable to be rejected, got %v", ext)
+	}
+
+	ext = runOperationMutator(t, l, `query($in: SearchBooksInput!) { searchBooks(input: $in) { total } }`,
+		map[string]any{"in": map[string]any{"query": "dune", "limit": int64(1000)}})
+	if ext == nil || ext["code"] != "LIMIT_TOO_LARGE" || ext["field"] != "searchBooks" {
+		t.Fatalf("expected a limit inside an input object to be rejected, got %v", ext)
+	}
+}
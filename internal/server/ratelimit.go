@@ -0,0 +1,118 @@
+package server
+
+import (
+	"book-nexus/internal/ratelimit"
+	"book-nexus/internal/users"
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// costLimit is a gqlgen extension that prices each operation with the
+// schema's complexity functions (see graph.NewComplexity), rejects those over
+// maxCost and charges the rest to the client's token bucket. The client is
+// its API token, its user, or else its IP address. The cost and what is left
+// of the bucket are reported in response headers.
+type costLimit struct {
+	maxCost int
+	limiter *ratelimit.Limiter
+	es      graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &costLimit{}
+
+func (c *costLimit) ExtensionName() string {
+	return "CostLimit"
+}
+
+func (c *costLimit) Validate(es graphql.ExecutableSchema) error {
+	c.es = es
+	return nil
+}
+
+func (c *costLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+	cost := complexity.Calculate(ctx, c.es, op, opCtx.Variables)
+
+	header := responseHeaderFrom(ctx)
+	header.Set("X-Query-Cost", strconv.Itoa(cost))
+	if cost > c.maxCost {
+		return &gqlerror.Error{
+			Message: "query is too expensive; request fewer items or fewer nested lists",
+			Extensions: map[string]any{
+				"code":    "QUERY_TOO_EXPENSIVE",
+				"cost":    cost,
+				"maxCost": c.maxCost,
+			},
+		}
+	}
+
+	result := c.limiter.Take(rateLimitKey(ctx), cost)
+	header.Set("X-RateLimit-Limit", strconv.Itoa(c.limiter.Capacity()))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	if !result.Allowed {
+		retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+		header.Set("Retry-After", strconv.Itoa(retryAfter))
+		return &gqlerror.Error{
+			Message: "rate limit exceeded",
+			Extensions: map[string]any{
+				"code":       "RATE_LIMITED",
+				"cost":       cost,
+				"remaining":  result.Remaining,
+				"retryAfter": retryAfter,
+			},
+		}
+	}
+	return nil
+}
+
+// rateLimitKey identifies the client whose bucket a request is charged to.
+func rateLimitKey(ctx context.Context) string {
+	if p, ok := users.PrincipalFrom(ctx); ok {
+		if p.TokenID != uuid.Nil {
+			return "token:" + p.TokenID.String()
+		}
+		if p.UserID != uuid.Nil {
+			return "user:" + p.UserID.String()
+		}
+	}
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return "ip:" + ip
+}
+
+type (
+	responseHeaderKey struct{}
+	clientIPKey       struct{}
+)
+
+// withCostLimitContext makes what the cost limit needs reachable from gqlgen
+// extensions, which only see the request context: the client's IP address,
+// and the response headers, which must be set before the response body is
+// written.
+func withCostLimitContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), responseHeaderKey{}, w.Header())
+		ctx = context.WithValue(ctx, clientIPKey{}, clientIP(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func responseHeaderFrom(ctx context.Context) http.Header {
+	if header, ok := ctx.Value(responseHeaderKey{}).(http.Header); ok {
+		return header
+	}
+	// Headers set here are dropped, as when running without the middleware.
+	return http.Header{}
+}
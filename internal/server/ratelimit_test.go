@@ -0,0 +1,89 @@
+package server
+
+import (
+	"book-nexus/graph"
+	"book-nexus/internal/config"
+	"book-nexus/internal/ratelimit"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2"
+)
+
+func runCostLimit(t *testing.T, c *costLimit, query string) (http.Header, map[string]any) {
+	t.Helper()
+	doc, errs := gqlparser.LoadQuery(c.es.Schema(), query)
+	if errs != nil {
+		t.Fatalf("parse %q: %v", query, errs)
+	}
+	header := http.Header{}
+	ctx := context.WithValue(context.Background(), responseHeaderKey{}, header)
+	opCtx := &graphql.OperationContext{Doc: doc, Variables: map[string]any{}}
+	if err := c.MutateOperationContext(ctx, opCtx); err != nil {
+		return header, err.Extensions
+	}
+	return header, nil
+}
+
+func newTestCostLimit(t *testing.T, maxCost, burst int) *costLimit {
+	t.Helper()
+	c := &costLimit{maxCost: maxCost, limiter: ratelimit.New(burst, 1)}
+	es := graph.NewExecutableSchema(graph.Config{Complexity: graph.NewComplexity()})
+	if err := c.Validate(es); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	return c
+}
+
+func TestCostLimitChargesListSize(t *testing.T) {
+	c := newTestCostLimit(t, config.Default().GraphQL.MaxQueryCost, 1000)
+
+	header, ext := runCostLimit(t, c, `{ books(limit: 10) { title recommendations { title } } }`)
+	if ext != nil {
+		t.Fatalf("expected the query to be allowed, got %v", ext)
+	}
+	// 10 books * (title + 5 recommendations * title)
+	if got := header.Get("X-Query-Cost"); got != "71" {
+		t.Fatalf("expected cost 71, got %s", got)
+	}
+	if got := header.Get("X-RateLimit-Remaining"); got != "929" {
+		t.Fatalf("expected 929 remaining, got %s", got)
+	}
+
+	_, ext = runCostLimit(t, c, `{ books(limit: 1000000) { title } }`)
+	if ext == nil || ext["code"] != "QUERY_TOO_EXPENSIVE" {
+		t.Fatalf("expected a huge limit to be rejected, got %v", ext)
+	}
+}
+
+func TestCostLimitRateLimits(t *testing.T) {
+	c := newTestCostLimit(t, config.Default().GraphQL.MaxQueryCost, 100)
+
+	query := `{ books(limit: 60) { title } }`
+	if _, ext := runCostLimit(t, c, query); ext != nil {
+		t.Fatalf("expected the first query to be allowed, got %v", ext)
+	}
+	header, ext := runCostLimit(t, c, query)
+	if ext == nil || ext["code"] != "RATE_LIMITED" {
+		t.Fatalf("expected the second query to be rate limited, got %v", ext)
+	}
+	if header.Get("Retry-After") == "" {
+		t.Fatalf("expected a Retry-After header")
+	}
+}
+
+func TestRateLimitKeyUsesClientIP(t *testing.T) {
+	var got string
+	handler := withCostLimitContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = rateLimitKey(r.Context())
+	}))
+	r := httptest.NewRequest(http.MethodPost, "/query", nil)
+	r.RemoteAddr = "203.0.113.7:51234"
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if got != "ip:203.0.113.7" {
+		t.Fatalf("expected the client IP without the audit middleware, got %q", got)
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"book-nexus/internal/database"
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// readRouting is a gqlgen extension that marks query operations read-only,
+// so database.Service.Reader may serve them from a replica. Mutations, and
+// the fields resolved in their results, read from the primary and so see
+// their own writes.
+type readRouting struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = readRouting{}
+
+func (readRouting) ExtensionName() string {
+	return "ReadRouting"
+}
+
+func (readRouting) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (readRouting) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	if op := graphql.GetOperationContext(ctx).Operation; op != nil && op.Operation == ast.Query {
+		ctx = database.WithReadOnly(ctx)
+	}
+	return next(ctx)
+}
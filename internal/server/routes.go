@@ -2,9 +2,17 @@ package server
 
 import (
 	"book-nexus/graph"
+	"book-nexus/internal/audit"
+	"book-nexus/internal/loaders"
+	"book-nexus/internal/users"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -12,19 +20,27 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/google/uuid"
 	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func (s *Server) setupRoutes() http.Handler {
 	mux := http.NewServeMux()
 
 	// Create GraphQL handler
-	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
-		DB: s.db,
-	}}))
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers: &graph.Resolver{
+			DB:       s.db,
+			Sessions: s.sessions,
+		},
+		Complexity: graph.NewComplexity(),
+	}))
 
-	// Wrap with admin auth context
-	graphQLHandler := s.withAdminAuth(srv)
+	// Wrap with authentication, the caller recorded in the audit log, the
+	// client IP and response headers for the cost limit and per-request
+	// batching loaders
+	graphQLHandler := s.withAuth(s.withCaller(withCostLimitContext(loaders.Middleware(s.db, srv))))
 
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
@@ -32,10 +48,20 @@ func (s *Server) setupRoutes() http.Handler {
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
-	srv.Use(extension.Introspection{})
-	srv.Use(extension.AutomaticPersistedQuery{
-		Cache: lru.New[string](100),
-	})
+	if s.config.IntrospectionEnabled() {
+		srv.Use(extension.Introspection{})
+	}
+	srv.Use(readRouting{})
+	srv.Use(depthLimit{max: s.config.GraphQL.MaxQueryDepth})
+	srv.Use(listLimit{max: s.config.GraphQL.MaxListLimit})
+	srv.Use(&costLimit{maxCost: s.config.GraphQL.MaxQueryCost, limiter: s.rateLimiter})
+	if s.persistedQueries != nil {
+		srv.Use(s.persistedQueries)
+	} else {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](100),
+		})
+	}
 
 	// Main GraphQL endpoint
 	mux.Handle("/query", s.withLogging(graphQLHandler))
@@ -43,46 +69,168 @@ func (s *Server) setupRoutes() http.Handler {
 	// Health check endpoint for load balancers
 	mux.HandleFunc("/health", s.healthCheck)
 
-	mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	if s.config.IntrospectionEnabled() {
+		mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	}
 
 	return s.withCORS(mux)
 }
 
 func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	health := s.db.Health()
 
-	// Check database connection
-	if err := s.db.Health()["status"]; err == "down" {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case health["error"] != "":
+		// The database does not answer, so load balancers should route
+		// elsewhere until it does.
 		w.WriteHeader(http.StatusServiceUnavailable)
 		json.NewEncoder(w).Encode(map[string]string{
-			"status":   "unhealthy",
+			"status":   "degraded",
 			"database": "down",
 		})
-		return
-	}
-
-	// Ping the database
-	if err := s.db.DB().Ping(ctx); err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
+	case health["status"] == "degraded":
+		// Still serving, so load balancers should keep routing here.
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":   "degraded",
+			"database": "saturated",
+		})
+	default:
 		json.NewEncoder(w).Encode(map[string]string{
-			"status":   "unhealthy",
-			"database": "connection failed",
+			"status":   "healthy",
+			"database": "connected",
 		})
-		return
 	}
+}
+
+// withAuth authenticates the request as a users.Principal. A Bearer token in
+// the Authorization header, either a session access token (a JWT) or an API
+// token, takes precedence; a bad one is rejected with 401 rather than served
+// anonymously. Without one, the legacy X-Admin-Password
+// header authenticates as an admin with no account. Requests with neither are
+// anonymous and can only read the public catalog.
+func (s *Server) withAuth(next http.Handler) http.Handler {
+	usersService := users.NewService(s.db.DB())
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if authorization := r.Header.Get("Authorization"); authorization != "" {
+			token, ok := strings.CutPrefix(authorization, "Bearer ")
+			if !ok {
+				unauthorized(w, "Authorization must be a Bearer token")
+				return
+			}
+			token = strings.TrimSpace(token)
+			if users.IsJWT(token) {
+				principal, err := s.sessions.Verify(token)
+				if err != nil {
+					unauthorized(w, err.Error())
+					return
+				}
+				ctx = users.WithPrincipal(ctx, principal)
+			} else {
+				principal, err := usersService.Authenticate(ctx, token)
+				if errors.Is(err, users.ErrInvalidToken) {
+					unauthorized(w, err.Error())
+					return
+				}
+				if err != nil {
+					slog.Error("failed to authenticate API token", "error", err)
+					http.Error(w, "authentication failed", http.StatusInternalServerError)
+					return
+				}
+				ctx = users.WithPrincipal(ctx, *principal)
+			}
+		} else if password := r.Header.Get("X-Admin-Password"); password != "" {
+			client := clientIP(r)
+			if wait := s.adminLockout.Locked(client); wait > 0 {
+				slog.Warn(eventAdminAuthLocked, "remote_ip", client, "retry_after", wait)
+				tooManyAttempts(w, wait)
+				return
+			}
+			if s.checkAdminPassword(password) {
+				s.adminLockout.Succeed(client)
+				ctx = users.WithPrincipal(ctx, users.LegacyAdmin)
+			} else {
+				failures, lockedFor := s.adminLockout.Fail(client)
+				slog.Warn(eventAdminAuthFailed,
+					"remote_ip", client,
+					"failures", failures,
+					"locked_for", lockedFor,
+					"user_agent", r.UserAgent())
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
 
+// Log messages for failed admin password attempts, for alerting.
+const (
+	eventAdminAuthFailed = "admin_auth_failed"
+	eventAdminAuthLocked = "admin_auth_locked_out"
+)
+
+// checkAdminPassword compares the password with the stored bcrypt hash,
+// which takes the same time however much of it matches. Without
+// ADMIN_PASSWORD_HASH every password is wrong.
+func (s *Server) checkAdminPassword(password string) bool {
+	if s.adminPasswordHash == nil {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword(s.adminPasswordHash, []byte(password)) == nil
+}
+
+// clientIP is the address admin password failures and anonymous rate limits
+// are tracked by. It is the connection's peer address; X-Forwarded-For is not
+// trusted, since any client can set it.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func tooManyAttempts(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status":   "healthy",
-		"database": "connected",
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]string{{"message": "too many failed admin password attempts; try again later"}},
 	})
 }
 
-func (s *Server) withAdminAuth(next http.Handler) http.Handler {
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="book-nexus"`)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]string{{"message": message}},
+	})
+}
+
+// maxRequestIDLength bounds the X-Request-ID a client may supply.
+const maxRequestIDLength = 128
+
+// withCaller attaches the audit caller: the request ID (the client's
+// X-Request-ID, or a generated one echoed back in the response), the actor
+// and the remote address. Must run inside withAuth.
+func (s *Server) withCaller(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract admin password from header
-		adminPassword := r.Header.Get("X-Admin-Password")
-		ctx := graph.WithAdminAuth(r.Context(), adminPassword)
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		actor := "anonymous"
+		if principal, ok := users.PrincipalFrom(r.Context()); ok {
+			actor = principal.Actor()
+		}
+		ctx := audit.WithCaller(r.Context(), audit.Caller{
+			Actor:      actor,
+			RequestID:  requestID,
+			RemoteAddr: r.RemoteAddr,
+		})
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -115,23 +263,6 @@ func (s *Server) withLogging(handler http.Handler) http.Handler {
 	})
 }
 
-func (s *Server) withCORS(handler http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Password")
-
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-
-		handler.ServeHTTP(w, r)
-	})
-}
-
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -5,47 +5,83 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"os"
-	"strconv"
-	"time"
-
-	_ "github.com/joho/godotenv/autoload"
 
+	"book-nexus/internal/config"
 	"book-nexus/internal/database"
+	"book-nexus/internal/ratelimit"
+	"book-nexus/internal/trash"
+	"book-nexus/internal/users"
 )
 
 type Server struct {
-	port       int
-	db         database.Service
-	httpServer *http.Server
+	config   *config.Config
+	db       database.Service
+	sessions *users.Sessions
+	// adminPasswordHash is the bcrypt hash of the admin password, or nil
+	// when the legacy admin password is disabled.
+	adminPasswordHash []byte
+	adminLockout      *lockout
+	rateLimiter       *ratelimit.Limiter
+	// persistedQueries, when set, restricts /query to an allowlist.
+	persistedQueries *persistedQueries
+	cors             *corsPolicy
+	httpServer       *http.Server
+	stopPurger       context.CancelFunc
 }
 
-func NewServer() *Server {
-	port := 8080
-	if envPort := os.Getenv("PORT"); envPort != "" {
-		if p, err := strconv.Atoi(envPort); err == nil {
-			port = p
-		} else {
-			slog.Warn("invalid PORT environment variable, using default", "port", port)
+// NewServer sets up the API server on an open database. The configuration
+// must already be validated.
+func NewServer(cfg *config.Config, db database.Service) (*Server, error) {
+	server := &Server{
+		config:       cfg,
+		db:           db,
+		adminLockout: newLockout(),
+		rateLimiter:  ratelimit.New(cfg.RateLimit.Burst, float64(cfg.RateLimit.Rate)),
+		cors:         newCORSPolicy(cfg.CORS),
+	}
+	if cfg.Auth.AdminPasswordHash != "" {
+		server.adminPasswordHash = []byte(cfg.Auth.AdminPasswordHash)
+	}
+	server.sessions = users.NewSessions(db.DB(), users.SessionConfig{
+		Secret:     []byte(cfg.Auth.JWTSecret),
+		AccessTTL:  cfg.Auth.AccessTokenTTL,
+		RefreshTTL: cfg.Auth.RefreshTokenTTL,
+	})
+
+	// With a persisted query manifest, only the queries in it are executed.
+	// A manifest that cannot be loaded is an error rather than silently
+	// leaving the API open.
+	if path := cfg.GraphQL.PersistedQueriesFile; path != "" {
+		pq, err := loadPersistedQueries(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load persisted queries: %w", err)
 		}
+		slog.Info("persisted query allowlist enabled", "path", path, "queries", len(pq.documents))
+		server.persistedQueries = pq
 	}
 
-	server := &Server{
-		port: port,
-		db:   database.New(),
+	purgerCtx, stopPurger := context.WithCancel(context.Background())
+	server.stopPurger = stopPurger
+	if retention := cfg.Trash.Retention; retention > 0 {
+		go trash.NewService(db.DB()).RunPurger(purgerCtx, retention)
 	}
 
 	mux := server.setupRoutes()
 
 	server.httpServer = &http.Server{
-		Addr:         fmt.Sprintf(":%d", port),
+		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:      mux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  time.Minute,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
-	return server
+	return server, nil
+}
+
+// Addr is the address the server listens on.
+func (s *Server) Addr() string {
+	return s.httpServer.Addr
 }
 
 func (s *Server) Listen() error {
@@ -53,5 +89,6 @@ func (s *Server) Listen() error {
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopPurger()
 	return s.httpServer.Shutdown(ctx)
 }
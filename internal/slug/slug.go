@@ -0,0 +1,60 @@
+// Package slug makes the URL-friendly identifiers authors, publishers and
+// series are looked up by.
+package slug
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Make converts a name to a slug: lowercase ASCII letters and digits, with
+// runs of spaces, hyphens and underscores turned into single hyphens. Other
+// characters are dropped, so a name without ASCII letters or digits yields an
+// empty slug.
+func Make(name string) string {
+	// Convert to lowercase
+	slug := strings.ToLower(name)
+	// Replace spaces and special characters with hyphens
+	slug = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		if r == ' ' || r == '-' || r == '_' {
+			return '-'
+		}
+		return -1
+	}, slug)
+	// Remove multiple consecutive hyphens
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	// Trim leading/trailing hyphens
+	slug = strings.Trim(slug, "-")
+	return slug
+}
+
+// Taken turns the slugs of a table, as its queries return them, into the set
+// Unique checks against. NULL slugs are left out.
+func Taken(slugs []*string) map[string]bool {
+	taken := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		if slug != nil {
+			taken[*slug] = true
+		}
+	}
+	return taken
+}
+
+// Unique reserves base in taken, or base with the first free numeric suffix,
+// and returns it. An empty base stays empty and is stored as NULL.
+func Unique(taken map[string]bool, base string) string {
+	if base == "" {
+		return ""
+	}
+	slug := base
+	for i := 1; taken[slug]; i++ {
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	taken[slug] = true
+	return slug
+}
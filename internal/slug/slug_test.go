@@ -0,0 +1,33 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	for name, want := range map[string]string{
+		"Ursula K. Le Guin":     "ursula-k-le-guin",
+		"  Tor -- Forge_Books ": "tor-forge-books",
+		"The 39 Steps":          "the-39-steps",
+		"李白":                    "",
+	} {
+		if got := Make(name); got != want {
+			t.Fatalf("Make(%q) = %q, expected %q", name, got, want)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	taken := Taken([]*string{ptr("frank-herbert"), nil, ptr("frank-herbert-1")})
+	for _, want := range []string{"frank-herbert-2", "frank-herbert-3"} {
+		if got := Unique(taken, Make("Frank Herbert")); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+	if got := Unique(taken, Make("Ursula K. Le Guin")); got != "ursula-k-le-guin" {
+		t.Fatalf("unexpected slug %q", got)
+	}
+	if got := Unique(taken, ""); got != "" || taken[""] {
+		t.Fatalf("expected an empty base to stay empty and not be reserved, got %q", got)
+	}
+}
+
+func ptr(s string) *string { return &s }
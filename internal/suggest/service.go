@@ -0,0 +1,63 @@
+// Package suggest provides typo-tolerant autocomplete across books, authors,
+// series and publishers, ranked by trigram similarity.
+package suggest
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"strings"
+)
+
+// Entity types a suggestion can refer to. The values match the entity_type
+// column returned by the Suggest query.
+const (
+	TypeBook      = "BOOK"
+	TypeAuthor    = "AUTHOR"
+	TypeSeries    = "SERIES"
+	TypePublisher = "PUBLISHER"
+)
+
+// AllTypes is used when the caller does not restrict the entity types.
+var AllTypes = []string{TypeBook, TypeAuthor, TypeSeries, TypePublisher}
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 50
+)
+
+type Service struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+}
+
+func NewService(db database.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Suggest returns up to limit entities whose name is similar to prefix, best
+// matches first. An empty types slice searches every entity type.
+func (s *Service) Suggest(ctx context.Context, prefix string, types []string, limit int32) ([]sqlc.SuggestRow, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []sqlc.SuggestRow{}, nil
+	}
+	if len(types) == 0 {
+		types = AllTypes
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	return s.queries.Suggest(ctx, sqlc.SuggestParams{
+		Prefix:     prefix,
+		Types:      types,
+		MaxResults: limit,
+	})
+}
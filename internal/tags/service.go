@@ -0,0 +1,60 @@
+package tags
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+}
+
+func NewService(db database.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (s *Service) GetTag(ctx context.Context, id uuid.UUID) (*sqlc.Tag, error) {
+	tag, err := s.queries.GetTagByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (s *Service) GetTagBySlug(ctx context.Context, slug string) (*sqlc.Tag, error) {
+	tag, err := s.queries.GetTagBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (s *Service) ListTags(ctx context.Context, limit, offset int32) ([]sqlc.Tag, error) {
+	return s.queries.ListTags(ctx, sqlc.ListTagsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func (s *Service) SearchTags(ctx context.Context, query string, limit, offset int32) ([]sqlc.Tag, error) {
+	return s.queries.SearchTags(ctx, sqlc.SearchTagsParams{
+		Column1: &query,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+func (s *Service) GetTagsByBookIDs(ctx context.Context, bookIDs []uuid.UUID) ([]sqlc.GetTagsByBookIDsRow, error) {
+	return s.queries.GetTagsByBookIDs(ctx, bookIDs)
+}
+
+func (s *Service) GetTagBookCounts(ctx context.Context, tagIDs []uuid.UUID) ([]sqlc.GetTagBookCountsRow, error) {
+	return s.queries.GetTagBookCounts(ctx, tagIDs)
+}
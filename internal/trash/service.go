@@ -0,0 +1,329 @@
+// Package trash lists, restores and purges deleted catalog entities. Deleting
+// an entity only sets its deleted_at (see the deletion package); purging, by
+// an admin or by the background purger once the retention has passed, is what
+// removes the row.
+package trash
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/deletion"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Entity types that can be in the trash. The values match the entity_type
+// column returned by the trash queries.
+const (
+	TypeBook      = "BOOK"
+	TypeAuthor    = "AUTHOR"
+	TypeSeries    = "SERIES"
+	TypePublisher = "PUBLISHER"
+)
+
+// AllTypes is used when the caller does not restrict the entity types.
+var AllTypes = []string{TypeBook, TypeAuthor, TypeSeries, TypePublisher}
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+
+	// PurgeInterval is how often the background purger runs.
+	PurgeInterval = time.Hour
+)
+
+// Item is an entity in the trash.
+type Item struct {
+	Type      string
+	ID        uuid.UUID
+	Name      string
+	DeletedAt time.Time
+}
+
+type Service struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+}
+
+func NewService(db database.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// List returns up to limit trashed entities, most recently deleted first. An
+// empty types slice lists every entity type.
+func (s *Service) List(ctx context.Context, types []string, limit int32) ([]Item, error) {
+	if len(types) == 0 {
+		types = AllTypes
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	rows, err := s.queries.ListTrash(ctx, sqlc.ListTrashParams{
+		Types:      types,
+		MaxResults: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		item := Item{Type: row.EntityType, ID: row.ID, Name: row.Name}
+		if row.DeletedAt != nil {
+			item.DeletedAt = *row.DeletedAt
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Restore takes an entity out of the trash and returns its type. Restoring
+// an author or series also restores the books that were trashed with it by a
+// cascading delete. A book cannot be restored while its author, series or
+// publisher is in the trash. It returns the books restored along with an author or series.
+func (s *Service) Restore(ctx context.Context, id uuid.UUID) (entityType string, books []sqlc.Book, err error) {
+	entityType, err = s.entityType(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	books, err = s.restore(ctx, entityType, id)
+	return entityType, books, err
+}
+
+func (s *Service) restore(ctx context.Context, entityType string, id uuid.UUID) (books []sqlc.Book, err error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	switch entityType {
+	case TypeBook:
+		deletedAt, err := q.LockBook(ctx, id)
+		if err := deletion.Trashed("book", id, deletedAt, err); err != nil {
+			return nil, err
+		}
+		book, err := q.RestoreBook(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := liveRelation(ctx, id, "author", book.AuthorID, q.LockAuthor); err != nil {
+			return nil, err
+		}
+		if book.SeriesID.Valid {
+			if err := liveRelation(ctx, id, "series", book.SeriesID.Bytes, q.LockSeries); err != nil {
+				return nil, err
+			}
+		}
+		if book.PublisherID.Valid {
+			if err := liveRelation(ctx, id, "publisher", book.PublisherID.Bytes, q.LockPublisher); err != nil {
+				return nil, err
+			}
+		}
+	case TypeAuthor:
+		deletedAt, err := q.LockAuthor(ctx, id)
+		if err := deletion.Trashed("author", id, deletedAt, err); err != nil {
+			return nil, err
+		}
+		books, err = q.RestoreAuthorBooks(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := q.RestoreAuthor(ctx, id); err != nil {
+			return nil, err
+		}
+	case TypeSeries:
+		deletedAt, err := q.LockSeries(ctx, id)
+		if err := deletion.Trashed("series", id, deletedAt, err); err != nil {
+			return nil, err
+		}
+		books, err = q.RestoreSeriesBooks(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := q.RestoreSeries(ctx, id); err != nil {
+			return nil, err
+		}
+	case TypePublisher:
+		deletedAt, err := q.LockPublisher(ctx, id)
+		if err := deletion.Trashed("publisher", id, deletedAt, err); err != nil {
+			return nil, err
+		}
+		if _, err := q.RestorePublisher(ctx, id); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// liveRelation locks the entity a book being restored references and fails
+// if it is in the trash, so a restored book never points at a trashed one.
+func liveRelation(ctx context.Context, bookID uuid.UUID, entity string, id uuid.UUID, lock func(context.Context, uuid.UUID) (*time.Time, error)) error {
+	deletedAt, err := lock(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deletedAt != nil {
+		return fmt.Errorf("book %s: its %s %s is in the trash; restore the %s first", bookID, entity, id, entity)
+	}
+	return nil
+}
+
+// Purge permanently deletes an entity in the trash and returns its type.
+// Purging an author or series also purges its trashed books, and fails with
+// a *deletion.BlockedError if live books still reference it. Purging a
+// publisher clears it from any books that still reference it.
+func (s *Service) Purge(ctx context.Context, id uuid.UUID) (string, error) {
+	entityType, err := s.entityType(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return entityType, s.purge(ctx, entityType, id)
+}
+
+func (s *Service) purge(ctx context.Context, entityType string, id uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	switch entityType {
+	case TypeBook:
+		deletedAt, err := q.LockBook(ctx, id)
+		if err := deletion.Trashed("book", id, deletedAt, err); err != nil {
+			return err
+		}
+		if err := q.PurgeBook(ctx, id); err != nil {
+			return err
+		}
+	case TypeAuthor:
+		deletedAt, err := q.LockAuthor(ctx, id)
+		if err := deletion.Trashed("author", id, deletedAt, err); err != nil {
+			return err
+		}
+		bookIDs, err := q.GetAuthorBookIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(bookIDs) > 0 {
+			return &deletion.BlockedError{Entity: "author", ID: id, BookIDs: bookIDs}
+		}
+		if _, err := q.PurgeAuthorBooks(ctx, id); err != nil {
+			return err
+		}
+		if err := q.PurgeAuthor(ctx, id); err != nil {
+			return err
+		}
+	case TypeSeries:
+		deletedAt, err := q.LockSeries(ctx, id)
+		if err := deletion.Trashed("series", id, deletedAt, err); err != nil {
+			return err
+		}
+		bookIDs, err := q.GetSeriesBookIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(bookIDs) > 0 {
+			return &deletion.BlockedError{Entity: "series", ID: id, BookIDs: bookIDs}
+		}
+		if _, err := q.PurgeSeriesBooks(ctx, id); err != nil {
+			return err
+		}
+		if err := q.PurgeSeries(ctx, id); err != nil {
+			return err
+		}
+	case TypePublisher:
+		deletedAt, err := q.LockPublisher(ctx, id)
+		if err := deletion.Trashed("publisher", id, deletedAt, err); err != nil {
+			return err
+		}
+		if _, err := q.NullifyPublisherBooks(ctx, id); err != nil {
+			return err
+		}
+		if err := q.PurgePublisher(ctx, id); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown entity type %q", entityType)
+	}
+	return tx.Commit(ctx)
+}
+
+// entityType finds which table a trashed entity lives in.
+func (s *Service) entityType(ctx context.Context, id uuid.UUID) (string, error) {
+	entityType, err := s.queries.GetTrashedEntityType(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("%s in trash: %w", id, deletion.ErrNotFound)
+	}
+	return entityType, err
+}
+
+// PurgeExpired purges every entity deleted before the given time, books
+// first, and returns how many were purged. Entities that cannot be purged
+// because live books still reference them are logged and left in the trash.
+func (s *Service) PurgeExpired(ctx context.Context, before time.Time) (int, error) {
+	rows, err := s.queries.ListExpiredTrash(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, row := range rows {
+		err := s.purge(ctx, row.EntityType, row.ID)
+		var blocked *deletion.BlockedError
+		switch {
+		case err == nil:
+			purged++
+		case errors.As(err, &blocked):
+			slog.Warn("trash purge skipped entity still referenced by live books",
+				"type", row.EntityType, "id", row.ID, "books", len(blocked.BookIDs))
+		case errors.Is(err, deletion.ErrNotFound):
+			// Restored or purged since it was listed.
+		default:
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// RunPurger purges entities older than retention every PurgeInterval until
+// ctx is done. It is meant to run in its own goroutine.
+func (s *Service) RunPurger(ctx context.Context, retention time.Duration) {
+	ticker := time.NewTicker(PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpired(ctx, time.Now().Add(-retention))
+		if err != nil && ctx.Err() == nil {
+			slog.Error("trash purge failed", "error", err)
+		} else if purged > 0 {
+			slog.Info("trash purge completed", "purged", purged, "retention", retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
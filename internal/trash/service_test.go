@@ -0,0 +1,204 @@
+package trash
+
+import (
+	"book-nexus/internal/authors"
+	"book-nexus/internal/database/dbtest"
+	"book-nexus/internal/database/sqlc"
+	"book-nexus/internal/deletion"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+// catalog is an author, series and publisher with one book referencing all
+// three, all named after name since names are unique.
+type catalog struct {
+	author    sqlc.Author
+	series    sqlc.Series
+	publisher sqlc.Publisher
+	book      sqlc.Book
+}
+
+func newCatalog(t *testing.T, pool *pgxpool.Pool, name string) catalog {
+	t.Helper()
+	ctx := context.Background()
+	q := sqlc.New(pool)
+
+	var c catalog
+	var err error
+	if c.author, err = q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Name: name + " author"}); err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+	if c.series, err = q.CreateSeries(ctx, sqlc.CreateSeriesParams{Name: name + " series"}); err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+	if c.publisher, err = q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Name: name + " publisher"}); err != nil {
+		t.Fatalf("create publisher: %v", err)
+	}
+	if c.book, err = q.CreateBook(ctx, sqlc.CreateBookParams{
+		Title:       name,
+		AuthorID:    c.author.ID,
+		SeriesID:    pgtype.UUID{Bytes: c.series.ID, Valid: true},
+		PublisherID: pgtype.UUID{Bytes: c.publisher.ID, Valid: true},
+	}); err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	return c
+}
+
+func bookDeleted(t *testing.T, pool *pgxpool.Pool, id uuid.UUID) bool {
+	t.Helper()
+	deletedAt, err := sqlc.New(pool).LockBook(context.Background(), id)
+	if err != nil {
+		t.Fatalf("lock book: %v", err)
+	}
+	return deletedAt != nil
+}
+
+func TestRestoreBookNeedsLiveRelations(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+	s := NewService(pool)
+
+	tests := []struct {
+		name  string
+		id    func(catalog) uuid.UUID
+		trash func(context.Context, uuid.UUID) error
+	}{
+		{"author", func(c catalog) uuid.UUID { return c.author.ID }, q.TrashAuthor},
+		{"series", func(c catalog) uuid.UUID { return c.series.ID }, q.TrashSeries},
+		{"publisher", func(c catalog) uuid.UUID { return c.publisher.ID }, q.TrashPublisher},
+	}
+	for _, tt := range tests {
+		c := newCatalog(t, pool, tt.name)
+		if err := q.TrashBook(ctx, c.book.ID); err != nil {
+			t.Fatalf("%s: trash book: %v", tt.name, err)
+		}
+		if err := tt.trash(ctx, tt.id(c)); err != nil {
+			t.Fatalf("%s: trash %s: %v", tt.name, tt.name, err)
+		}
+
+		if _, _, err := s.Restore(ctx, c.book.ID); err == nil {
+			t.Fatalf("%s: expected the book to stay in the trash while its %s is", tt.name, tt.name)
+		}
+		if !bookDeleted(t, pool, c.book.ID) {
+			t.Fatalf("%s: a refused restore left the book restored", tt.name)
+		}
+
+		if _, _, err := s.Restore(ctx, tt.id(c)); err != nil {
+			t.Fatalf("%s: restore %s: %v", tt.name, tt.name, err)
+		}
+		if entityType, _, err := s.Restore(ctx, c.book.ID); err != nil || entityType != TypeBook {
+			t.Fatalf("%s: restore book = %q, %v", tt.name, entityType, err)
+		}
+	}
+}
+
+func TestRestoreAuthorRestoresCascadedBooks(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+	s := NewService(pool)
+	c := newCatalog(t, pool, "Dune")
+
+	// A book trashed on its own before the author stays in the trash.
+	earlier, err := q.CreateBook(ctx, sqlc.CreateBookParams{Title: "Dune Messiah", AuthorID: c.author.ID})
+	if err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if err := q.TrashBook(ctx, earlier.ID); err != nil {
+		t.Fatalf("trash book: %v", err)
+	}
+	trashed, _, err := authors.NewService(pool).DeleteAuthor(ctx, c.author.ID, deletion.Strategy{Mode: deletion.Cascade})
+	if err != nil {
+		t.Fatalf("delete author: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != c.book.ID {
+		t.Fatalf("expected the cascade to trash only %s, got %v", c.book.ID, trashed)
+	}
+
+	entityType, restored, err := s.Restore(ctx, c.author.ID)
+	if err != nil || entityType != TypeAuthor {
+		t.Fatalf("restore author = %q, %v", entityType, err)
+	}
+	if len(restored) != 1 || restored[0].ID != c.book.ID || restored[0].DeletedAt != nil {
+		t.Fatalf("expected %s to be restored with its author, got %v", c.book.ID, restored)
+	}
+	if !bookDeleted(t, pool, earlier.ID) {
+		t.Fatalf("expected %s, trashed before its author, to stay in the trash", earlier.ID)
+	}
+}
+
+func TestPurgeAuthorBlockedByLiveBooks(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+	s := NewService(pool)
+	c := newCatalog(t, pool, "Dune")
+
+	if err := q.TrashAuthor(ctx, c.author.ID); err != nil {
+		t.Fatalf("trash author: %v", err)
+	}
+	var blocked *deletion.BlockedError
+	if _, err := s.Purge(ctx, c.author.ID); !errors.As(err, &blocked) || len(blocked.BookIDs) != 1 {
+		t.Fatalf("expected the live book to block the purge, got %v", err)
+	}
+
+	// Once the book is trashed too, purging the author takes it along.
+	if err := q.TrashBook(ctx, c.book.ID); err != nil {
+		t.Fatalf("trash book: %v", err)
+	}
+	if _, err := s.Purge(ctx, c.author.ID); err != nil {
+		t.Fatalf("purge author: %v", err)
+	}
+	if _, err := q.LockBook(ctx, c.book.ID); err == nil {
+		t.Fatalf("expected %s to be purged with its author", c.book.ID)
+	}
+}
+
+func TestPurgeExpiredSkipsBlocked(t *testing.T) {
+	pool := dbtest.New(t)
+	ctx := context.Background()
+	q := sqlc.New(pool)
+	s := NewService(pool)
+	blocked := newCatalog(t, pool, "Dune")
+	expired := newCatalog(t, pool, "Emma")
+
+	// blocked's author is trashed under its live book; expired's series
+	// and book are both trashed, and go, book first.
+	if err := q.TrashAuthor(ctx, blocked.author.ID); err != nil {
+		t.Fatalf("trash author: %v", err)
+	}
+	if err := q.TrashSeries(ctx, expired.series.ID); err != nil {
+		t.Fatalf("trash series: %v", err)
+	}
+	if err := q.TrashBook(ctx, expired.book.ID); err != nil {
+		t.Fatalf("trash book: %v", err)
+	}
+
+	purged, err := s.PurgeExpired(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeExpired: %v", err)
+	}
+	if purged != 2 {
+		t.Fatalf("expected the book and series to be purged, got %d", purged)
+	}
+	items, err := s.List(ctx, nil, 0)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != blocked.author.ID {
+		t.Fatalf("expected only the blocked author left in the trash, got %v", items)
+	}
+}
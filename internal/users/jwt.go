@@ -0,0 +1,94 @@
+package users
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidAccessToken is returned for an access token that is malformed,
+// badly signed or expired.
+var ErrInvalidAccessToken = errors.New("invalid or expired access token")
+
+// issuer is the iss claim of the access tokens this API signs.
+const issuer = "book-nexus"
+
+// jwtHeader is the only header accepted. Pinning the algorithm rules out
+// "alg": "none" and algorithm confusion attacks.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// AccessClaims are the claims of a session access token. The role is
+// copied in at issue time, so a role change applies once the user's current
+// access token expires.
+type AccessClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Email     string `json:"email"`
+	Role      Role   `json:"role"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Principal returns who the claims authenticate.
+func (c AccessClaims) Principal() (Principal, error) {
+	id, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return Principal{}, ErrInvalidAccessToken
+	}
+	return Principal{UserID: id, Email: c.Email, Role: c.Role}, nil
+}
+
+// SignAccessToken returns claims as an HS256-signed JWT.
+func SignAccessToken(secret []byte, claims AccessClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sign(secret, signingInput)), nil
+}
+
+// ParseAccessToken verifies an HS256 JWT signed with secret and returns its
+// claims if it has not expired at now.
+func ParseAccessToken(secret []byte, token string, now time.Time) (AccessClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return AccessClaims{}, ErrInvalidAccessToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(secret, parts[0]+"."+parts[1])) {
+		return AccessClaims{}, ErrInvalidAccessToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return AccessClaims{}, ErrInvalidAccessToken
+	}
+	var claims AccessClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return AccessClaims{}, fmt.Errorf("%w: %v", ErrInvalidAccessToken, err)
+	}
+	if claims.Issuer != issuer || now.Unix() >= claims.ExpiresAt {
+		return AccessClaims{}, ErrInvalidAccessToken
+	}
+	return claims, nil
+}
+
+// IsJWT reports whether a Bearer credential has the shape of a JWT rather
+// than an API token.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func sign(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,75 @@
+package users
+
+import (
+	"book-nexus/internal/config"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func testClaims(now time.Time) AccessClaims {
+	return AccessClaims{
+		Issuer:    issuer,
+		Subject:   uuid.NewString(),
+		Email:     "ed@example.com",
+		Role:      RoleEditor,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(config.Default().Auth.AccessTokenTTL).Unix(),
+	}
+}
+
+func TestAccessTokenRoundTrip(t *testing.T) {
+	now := time.Now()
+	claims := testClaims(now)
+	token, err := SignAccessToken(testSecret, claims)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if !IsJWT(token) {
+		t.Fatalf("expected %q to look like a JWT", token)
+	}
+
+	got, err := ParseAccessToken(testSecret, token, now)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got != claims {
+		t.Fatalf("expected %+v, got %+v", claims, got)
+	}
+	p, err := got.Principal()
+	if err != nil || p.Email != claims.Email || p.Role != RoleEditor || p.UserID.String() != claims.Subject {
+		t.Fatalf("unexpected principal %+v (%v)", p, err)
+	}
+}
+
+func TestParseAccessTokenRejects(t *testing.T) {
+	now := time.Now()
+	token, err := SignAccessToken(testSecret, testClaims(now))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	parts := strings.Split(token, ".")
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	cases := map[string]struct {
+		secret []byte
+		token  string
+		now    time.Time
+	}{
+		"expired":        {testSecret, token, now.Add(config.Default().Auth.AccessTokenTTL)},
+		"wrong secret":   {[]byte("another secret of thirty-two bytes"), token, now},
+		"tampered claim": {testSecret, parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"role":"admin"}`)) + "." + parts[2], now},
+		"alg none":       {testSecret, none + "." + parts[1] + ".", now},
+		"not a jwt":      {testSecret, "bnx_abc", now},
+	}
+	for name, c := range cases {
+		if _, err := ParseAccessToken(c.secret, c.token, c.now); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
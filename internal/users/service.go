@@ -0,0 +1,191 @@
+package users
+
+import (
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MinPasswordLength is the shortest password CreateUser accepts.
+	MinPasswordLength = 12
+
+	// touchInterval limits how often a token's last_used_at is written, so
+	// a busy client does not update the row on every request.
+	touchInterval = time.Minute
+)
+
+var (
+	ErrEmailTaken    = errors.New("a user with that email already exists")
+	ErrInvalidToken  = errors.New("invalid, expired or revoked API token")
+	ErrTokenNotFound = errors.New("API token not found or already revoked")
+)
+
+type Service struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+}
+
+func NewService(db database.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (s *Service) GetUser(ctx context.Context, id uuid.UUID) (*sqlc.User, error) {
+	user, err := s.queries.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *Service) ListUsers(ctx context.Context) ([]sqlc.User, error) {
+	return s.queries.ListUsers(ctx)
+}
+
+type CreateUserInput struct {
+	Email    string
+	Name     string
+	Password string
+	Role     Role
+}
+
+// CreateUser adds an account, storing a bcrypt hash of its password.
+func (s *Service) CreateUser(ctx context.Context, input CreateUserInput) (*sqlc.User, error) {
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if !strings.Contains(email, "@") {
+		return nil, fmt.Errorf("invalid email %q", input.Email)
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, errors.New("name is required")
+	}
+	if len(input.Password) < MinPasswordLength {
+		return nil, fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	if _, ok := rolePermissions[input.Role]; !ok {
+		return nil, fmt.Errorf("unknown role %q", input.Role)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.queries.CreateUser(ctx, sqlc.CreateUserParams{
+		Email:        email,
+		Name:         strings.TrimSpace(input.Name),
+		PasswordHash: string(hash),
+		Role:         string(input.Role),
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrEmailTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *Service) SetRole(ctx context.Context, id uuid.UUID, role Role) (*sqlc.User, error) {
+	if _, ok := rolePermissions[role]; !ok {
+		return nil, fmt.Errorf("unknown role %q", role)
+	}
+	user, err := s.queries.UpdateUserRole(ctx, sqlc.UpdateUserRoleParams{
+		ID:   id,
+		Role: string(role),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateToken issues an API token for a user. The token is returned only
+// here; afterwards just its hash is kept. A nil expiresAt never expires.
+func (s *Service) CreateToken(ctx context.Context, userID uuid.UUID, name string, expiresAt *time.Time) (string, *sqlc.ApiToken, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil, errors.New("token name is required")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", nil, errors.New("expiresAt must be in the future")
+	}
+
+	token, hash, err := NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+	apiToken, err := s.queries.CreateApiToken(ctx, sqlc.CreateApiTokenParams{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &apiToken, nil
+}
+
+func (s *Service) GetToken(ctx context.Context, id uuid.UUID) (*sqlc.ApiToken, error) {
+	apiToken, err := s.queries.GetApiTokenByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &apiToken, nil
+}
+
+// ListTokens returns a user's tokens, newest first, including revoked and
+// expired ones.
+func (s *Service) ListTokens(ctx context.Context, userID uuid.UUID) ([]sqlc.ApiToken, error) {
+	return s.queries.ListApiTokensByUser(ctx, userID)
+}
+
+// RevokeToken stops a token from authenticating. It takes effect on the
+// next request that uses it.
+func (s *Service) RevokeToken(ctx context.Context, id uuid.UUID) error {
+	n, err := s.queries.RevokeApiToken(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a Bearer token to the principal it belongs to.
+func (s *Service) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	row, err := s.queries.AuthenticateApiToken(ctx, HashToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if row.LastUsedAt == nil || time.Since(*row.LastUsedAt) > touchInterval {
+		if err := s.queries.TouchApiToken(ctx, row.TokenID); err != nil {
+			return nil, err
+		}
+	}
+	return &Principal{
+		UserID:  row.UserID,
+		Email:   row.Email,
+		Role:    Role(row.Role),
+		TokenID: row.TokenID,
+	}, nil
+}
@@ -0,0 +1,227 @@
+package users
+
+import (
+	"book-nexus/internal/config"
+	"book-nexus/internal/database"
+	"book-nexus/internal/database/sqlc"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrSessionsDisabled   = errors.New("login is disabled: JWT_SECRET is not set")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidSession     = errors.New("invalid, expired or revoked refresh token")
+)
+
+// SessionConfig configures login sessions. Without a secret, login is
+// disabled and only API tokens and the admin password authenticate.
+type SessionConfig struct {
+	Secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+func (c SessionConfig) Enabled() bool {
+	return len(c.Secret) > 0
+}
+
+// Session is what login and refresh hand to the client: a short-lived access
+// token for the Authorization header and a refresh token to get the next one.
+type Session struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	User             sqlc.User
+}
+
+// Sessions issues, rotates and verifies login sessions.
+type Sessions struct {
+	db      database.DBTX
+	queries *sqlc.Queries
+	config  SessionConfig
+}
+
+// NewSessions sets up login sessions. TTLs left at zero take the config
+// package's defaults.
+func NewSessions(db database.DBTX, cfg SessionConfig) *Sessions {
+	defaults := config.Default().Auth
+	if cfg.AccessTTL <= 0 {
+		cfg.AccessTTL = defaults.AccessTokenTTL
+	}
+	if cfg.RefreshTTL <= 0 {
+		cfg.RefreshTTL = defaults.RefreshTokenTTL
+	}
+	return &Sessions{
+		db:      db,
+		queries: sqlc.New(db),
+		config:  cfg,
+	}
+}
+
+// dummyHash is compared against when a login names an unknown email, so the
+// response takes as long as for a wrong password.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("book-nexus dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Login checks an email and password and starts a new session.
+func (s *Sessions) Login(ctx context.Context, email, password string) (*Session, error) {
+	if !s.config.Enabled() {
+		return nil, ErrSessionsDisabled
+	}
+
+	user, err := s.queries.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issue(ctx, s.queries, user, uuid.New())
+}
+
+// Refresh exchanges a refresh token for a new session. The token is revoked
+// and replaced by one in the same family. Presenting an already revoked
+// token revokes the whole family, ending the session for whoever holds the
+// current token too, since one of them must have stolen it.
+func (s *Sessions) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	if !s.config.Enabled() {
+		return nil, ErrSessionsDisabled
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	current, err := q.GetRefreshTokenByHashForUpdate(ctx, HashToken(refreshToken))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidSession
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current.RevokedAt != nil {
+		revoked, err := q.RevokeRefreshTokenFamily(ctx, current.FamilyID)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		slog.Warn("revoked refresh token reused; session family revoked",
+			"user_id", current.UserID, "family_id", current.FamilyID, "revoked", revoked)
+		return nil, ErrInvalidSession
+	}
+	if !current.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidSession
+	}
+
+	user, err := q.GetUserByID(ctx, current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.RevokeRefreshToken(ctx, current.ID); err != nil {
+		return nil, err
+	}
+	session, err := s.issue(ctx, q, user, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Logout revokes the session the refresh token belongs to. Access tokens
+// already issued stay valid until they expire. Unknown tokens are ignored,
+// so logging out twice is not an error.
+func (s *Sessions) Logout(ctx context.Context, refreshToken string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := s.queries.WithTx(tx)
+
+	current, err := q.GetRefreshTokenByHashForUpdate(ctx, HashToken(refreshToken))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := q.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Verify checks an access token and returns who it authenticates.
+func (s *Sessions) Verify(accessToken string) (Principal, error) {
+	if !s.config.Enabled() {
+		return Principal{}, ErrInvalidAccessToken
+	}
+	claims, err := ParseAccessToken(s.config.Secret, accessToken, time.Now())
+	if err != nil {
+		return Principal{}, err
+	}
+	return claims.Principal()
+}
+
+func (s *Sessions) issue(ctx context.Context, q *sqlc.Queries, user sqlc.User, familyID uuid.UUID) (*Session, error) {
+	now := time.Now()
+	accessExpiresAt := now.Add(s.config.AccessTTL)
+	accessToken, err := SignAccessToken(s.config.Secret, AccessClaims{
+		Issuer:    issuer,
+		Subject:   user.ID.String(),
+		Email:     user.Email,
+		Role:      Role(user.Role),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: accessExpiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, hash, err := NewToken()
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := q.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(s.config.RefreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  time.Unix(accessExpiresAt.Unix(), 0),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refresh.ExpiresAt,
+		User:             user,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package users
+
+import (
+	"book-nexus/internal/database/dbtest"
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m))
+}
+
+const testPassword = "correct horse battery"
+
+// newSessions returns sessions on a fresh database with one editor account.
+func newSessions(t *testing.T) *Sessions {
+	t.Helper()
+	pool := dbtest.New(t)
+	if _, err := NewService(pool).CreateUser(context.Background(), CreateUserInput{
+		Email:    "ed@example.com",
+		Name:     "Ed",
+		Password: testPassword,
+		Role:     RoleEditor,
+	}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return NewSessions(pool, SessionConfig{Secret: testSecret})
+}
+
+func TestLogin(t *testing.T) {
+	sessions := newSessions(t)
+	ctx := context.Background()
+
+	if _, err := sessions.Login(ctx, "ed@example.com", "wrong password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected a wrong password to be rejected, got %v", err)
+	}
+	if _, err := sessions.Login(ctx, "nobody@example.com", testPassword); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected an unknown email to be rejected, got %v", err)
+	}
+
+	session, err := sessions.Login(ctx, " Ed@Example.com ", testPassword)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	principal, err := sessions.Verify(session.AccessToken)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if principal.Role != RoleEditor {
+		t.Fatalf("expected the access token to carry the editor role, got %q", principal.Role)
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	sessions := newSessions(t)
+	ctx := context.Background()
+
+	first, err := sessions.Login(ctx, "ed@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	second, err := sessions.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatalf("expected refresh to issue a new refresh token")
+	}
+	if second.User.ID != first.User.ID {
+		t.Fatalf("expected the refreshed session to belong to %s, got %s", first.User.ID, second.User.ID)
+	}
+
+	// The new token works in turn.
+	if _, err := sessions.Refresh(ctx, second.RefreshToken); err != nil {
+		t.Fatalf("refresh with rotated token: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	sessions := newSessions(t)
+	ctx := context.Background()
+
+	first, err := sessions.Login(ctx, "ed@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	second, err := sessions.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	// Another login is a separate family and survives.
+	other, err := sessions.Login(ctx, "ed@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+
+	if _, err := sessions.Refresh(ctx, first.RefreshToken); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected the revoked token to be rejected, got %v", err)
+	}
+	if _, err := sessions.Refresh(ctx, second.RefreshToken); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected reuse to revoke the current token of the family too, got %v", err)
+	}
+	if _, err := sessions.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Fatalf("expected another session to be unaffected, got %v", err)
+	}
+}
+
+func TestLogoutRevokesFamily(t *testing.T) {
+	sessions := newSessions(t)
+	ctx := context.Background()
+
+	first, err := sessions.Login(ctx, "ed@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	second, err := sessions.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// Logging out with a stale token still ends the session.
+	if err := sessions.Logout(ctx, first.RefreshToken); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if _, err := sessions.Refresh(ctx, second.RefreshToken); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected logout to revoke the session, got %v", err)
+	}
+	if err := sessions.Logout(ctx, first.RefreshToken); err != nil {
+		t.Fatalf("expected logging out twice to succeed, got %v", err)
+	}
+}
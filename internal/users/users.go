@@ -0,0 +1,134 @@
+// Package users holds accounts, their roles and their API tokens. A request
+// authenticates as a Principal, either with a Bearer API token or with the
+// legacy X-Admin-Password header, and resolvers check the principal's role
+// for the permission an operation needs.
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Role is what an account may do. The values are stored in users.role.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// ParseRole accepts a role in any case, as the GraphQL Role enum sends it.
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToLower(s))
+	if _, ok := rolePermissions[role]; !ok {
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+	return role, nil
+}
+
+// Permission is one kind of operation a role may be allowed.
+type Permission string
+
+const (
+	// PermViewHistory reads entity revisions.
+	PermViewHistory Permission = "history:read"
+	// PermViewAudit reads the audit log, which names callers and their
+	// addresses and records account and token changes.
+	PermViewAudit Permission = "audit:read"
+	// PermEditCatalog creates, updates and reverts books, authors, series
+	// and publishers.
+	PermEditCatalog Permission = "catalog:edit"
+	// PermDeleteBooks moves books to the trash.
+	PermDeleteBooks Permission = "books:delete"
+	// PermDeleteEntities moves authors, series and publishers to the trash,
+	// along with their books when cascading.
+	PermDeleteEntities Permission = "entities:delete"
+	// PermManageTrash lists, restores and purges the trash.
+	PermManageTrash Permission = "trash:manage"
+	// PermManageUsers creates accounts, changes roles and manages other
+	// accounts' tokens.
+	PermManageUsers Permission = "users:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {PermViewHistory},
+	RoleEditor: {PermViewHistory, PermEditCatalog, PermDeleteBooks},
+	RoleAdmin: {
+		PermViewHistory, PermViewAudit, PermEditCatalog, PermDeleteBooks,
+		PermDeleteEntities, PermManageTrash, PermManageUsers,
+	},
+}
+
+// Can reports whether the role has the permission.
+func (r Role) Can(perm Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal is who a request is authenticated as.
+type Principal struct {
+	// UserID is uuid.Nil for the legacy admin password, which has no account.
+	UserID uuid.UUID
+	Email  string
+	Role   Role
+	// TokenID is the API token the request used, if any.
+	TokenID uuid.UUID
+}
+
+// LegacyAdmin is the principal for a request with the right X-Admin-Password.
+var LegacyAdmin = Principal{Role: RoleAdmin}
+
+// Actor names the principal in the audit log: the account's email, or
+// "admin" for the legacy admin password.
+func (p Principal) Actor() string {
+	if p.UserID == uuid.Nil {
+		return "admin"
+	}
+	return p.Email
+}
+
+type contextKey struct{}
+
+// WithPrincipal attaches the authenticated principal to ctx.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// PrincipalFrom returns the principal attached to ctx, if the request was
+// authenticated.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
+
+// tokenPrefix marks book-nexus API tokens so they are easy to recognise in
+// configs and secret scanners.
+const tokenPrefix = "bnx_"
+
+// NewToken returns a random API token and the hash stored for it.
+func NewToken() (string, []byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := tokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the SHA-256 hash an API token is stored and looked up
+// by. Tokens are long and random, so a fast unsalted hash is enough.
+func HashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
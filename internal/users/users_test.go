@@ -0,0 +1,85 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRolePermissions(t *testing.T) {
+	cases := []struct {
+		role Role
+		perm Permission
+		want bool
+	}{
+		{RoleViewer, PermViewHistory, true},
+		{RoleViewer, PermEditCatalog, false},
+		{RoleViewer, PermViewAudit, false},
+		{RoleEditor, PermViewAudit, false},
+		{RoleAdmin, PermViewAudit, true},
+		{RoleEditor, PermEditCatalog, true},
+		{RoleEditor, PermDeleteBooks, true},
+		{RoleEditor, PermDeleteEntities, false},
+		{RoleEditor, PermManageUsers, false},
+		{RoleAdmin, PermDeleteEntities, true},
+		{RoleAdmin, PermManageUsers, true},
+		{Role("owner"), PermViewHistory, false},
+	}
+	for _, c := range cases {
+		if got := c.role.Can(c.perm); got != c.want {
+			t.Fatalf("%s.Can(%s): expected %v, got %v", c.role, c.perm, c.want, got)
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	role, err := ParseRole("EDITOR")
+	if err != nil || role != RoleEditor {
+		t.Fatalf("expected editor, got %q (%v)", role, err)
+	}
+	if _, err := ParseRole("owner"); err == nil {
+		t.Fatalf("expected an error for an unknown role")
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	token, hash, err := NewToken()
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if !strings.HasPrefix(token, tokenPrefix) {
+		t.Fatalf("expected prefix %q, got %q", tokenPrefix, token)
+	}
+	if !bytes.Equal(hash, HashToken(token)) {
+		t.Fatalf("hash does not match HashToken of the token")
+	}
+
+	other, _, err := NewToken()
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if other == token {
+		t.Fatalf("expected distinct tokens")
+	}
+}
+
+func TestPrincipalActor(t *testing.T) {
+	if got := LegacyAdmin.Actor(); got != "admin" {
+		t.Fatalf("expected admin, got %q", got)
+	}
+	p := Principal{UserID: uuid.New(), Email: "ed@example.com", Role: RoleEditor}
+	if got := p.Actor(); got != "ed@example.com" {
+		t.Fatalf("expected the email, got %q", got)
+	}
+
+	ctx := WithPrincipal(context.Background(), p)
+	if got, ok := PrincipalFrom(ctx); !ok || got != p {
+		t.Fatalf("expected the principal back, got %+v (%v)", got, ok)
+	}
+	if _, ok := PrincipalFrom(context.Background()); ok {
+		t.Fatalf("expected no principal on a bare context")
+	}
+}